@@ -1,10 +1,9 @@
 package main
 
 import (
-	"log"
-
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
 	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning/alibabacloud"
 	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning/aws"
 	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning/gcp"
@@ -21,8 +20,14 @@ func main() {
 	rootCmd.AddCommand(gcp.NewGCPCmd())
 	rootCmd.AddCommand(ibmcloud.NewIBMCloudCmd())
 	rootCmd.AddCommand(alibabacloud.NewAliababaCloudCmd())
+	rootCmd.AddCommand(provisioning.NewCapabilitiesCmd())
+	rootCmd.AddCommand(provisioning.NewAdoptSecretCmd())
+	rootCmd.AddCommand(provisioning.NewExportCmd())
 
+	// Individual commands are free to return a provisioning.CLIError (via
+	// provisioning.NewValidationError et al.) to signal a specific exit status; provisioning.Fatal
+	// maps that to the matching process exit code, defaulting to ExitGenericError otherwise.
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+		provisioning.Fatal(err)
 	}
 }
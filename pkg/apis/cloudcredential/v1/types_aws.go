@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -28,6 +29,39 @@ type AWSProviderSpec struct {
 	metav1.TypeMeta `json:",inline"`
 	// StatementEntries contains a list of policy statements that should be associated with this credentials access key.
 	StatementEntries []StatementEntry `json:"statementEntries"`
+	// AdditionalSecretData contains additional content to be included in the Secret generated for this
+	// CredentialsRequest. It is used to allow additional data to be included in the generated Secret
+	// (e.g. a region or endpoint the consuming operator also needs) alongside the generated credential.
+	// +optional
+	AdditionalSecretData map[string]string `json:"additionalSecretData,omitempty"`
+	// AdditionalSecretDataConfigMapRef optionally names a ConfigMap, in the CredentialsRequest's own
+	// namespace, whose keys are merged into the generated Secret alongside the credential. Useful for
+	// companion config some consuming SDKs need alongside the key itself, e.g. a CA bundle or an
+	// endpoint override. Keys here that collide with AdditionalSecretData or the credential's own
+	// managed keys are an error. If the named ConfigMap does not exist, the controller sets the
+	// AdditionalSecretDataConfigMapNotFound condition and leaves the Secret as last written.
+	// +optional
+	AdditionalSecretDataConfigMapRef *corev1.LocalObjectReference `json:"additionalSecretDataConfigMapRef,omitempty"`
+	// AdditionalSecrets optionally declares extra secretRefs that should each receive their own
+	// separately-minted IAM user and access key, permissioned according to that entry's own
+	// StatementEntries, alongside the CredentialsRequest's primary SecretRef. This is for
+	// components that split duties across Secrets, e.g. a read-only consumer and a
+	// write-capable consumer sourced from one logical CredentialsRequest. Each SecretRef,
+	// including the primary one, must be unique.
+	// +optional
+	AdditionalSecrets []AdditionalSecretSpec `json:"additionalSecrets,omitempty"`
+}
+
+// AdditionalSecretSpec declares one extra secretRef that AWSProviderSpec.AdditionalSecrets wants
+// its own separately-permissioned credential minted for.
+type AdditionalSecretSpec struct {
+	// SecretRef points to the secret where this additional credential should be stored once
+	// generated. Must be unique among the CredentialsRequest's primary SecretRef and all other
+	// AdditionalSecretSpec entries.
+	SecretRef corev1.ObjectReference `json:"secretRef"`
+	// StatementEntries contains the policy statements that should be associated with the IAM
+	// user minted for this secretRef.
+	StatementEntries []StatementEntry `json:"statementEntries"`
 }
 
 // StatementEntry models an AWS policy statement entry.
@@ -50,6 +84,20 @@ type AWSProviderStatus struct {
 	User string `json:"user"`
 	// Policy is the name of the policy attached to the user in AWS.
 	Policy string `json:"policy"`
+	// AdditionalSecrets records the AWS user/policy minted for each entry in
+	// AWSProviderSpec.AdditionalSecrets, keyed by SecretRef.
+	// +optional
+	AdditionalSecrets []AdditionalSecretStatus `json:"additionalSecrets,omitempty"`
+}
+
+// AdditionalSecretStatus is the status of one AWSProviderSpec.AdditionalSecrets entry.
+type AdditionalSecretStatus struct {
+	// SecretRef echoes the AdditionalSecretSpec.SecretRef this status entry corresponds to.
+	SecretRef corev1.ObjectReference `json:"secretRef"`
+	// User is the name of the User created in AWS for this additional credential.
+	User string `json:"user"`
+	// Policy is the name of the policy attached to the user in AWS.
+	Policy string `json:"policy"`
 }
 
 // IAMPolicyCondition - map of condition types, with associated key - value mapping
@@ -39,6 +39,67 @@ const (
 
 	// CloudCredOperatorNamespace is the namespace where the credentials operator runs.
 	CloudCredOperatorNamespace = "openshift-cloud-credential-operator"
+
+	// AnnotationRotate can be set on a CredentialsRequest to request that the mint actuator
+	// immediately rotate the underlying cloud credential (generate a new key, update the target
+	// Secret, and revoke the old key) the next time it is reconciled. The operator clears the
+	// annotation once the rotation has completed successfully.
+	AnnotationRotate string = "cloudcredential.openshift.io/rotate"
+
+	// AnnotationManagedSecretKeys records, as a comma-separated list, the .data keys that CCO
+	// owns in a target Secret. When a Secret is shared with another controller, CCO only ever
+	// creates/updates/reads these keys, leaving any others the other controller manages alone.
+	AnnotationManagedSecretKeys string = "cloudcredential.openshift.io/managed-secret-keys"
+
+	// AnnotationDeletionPolicy can be set on a CredentialsRequest to control what happens to the
+	// minted cloud user/credential when the CredentialsRequest itself is deleted. Defaults to
+	// DeletionPolicyDelete when unset.
+	AnnotationDeletionPolicy string = "cloudcredential.openshift.io/deletion-policy"
+
+	// DeletionPolicyDelete is the default AnnotationDeletionPolicy value: the mint actuator deletes
+	// the cloud user it created when the CredentialsRequest is deleted.
+	DeletionPolicyDelete string = "delete"
+
+	// DeletionPolicyRetain is an AnnotationDeletionPolicy value requesting that the mint actuator
+	// leave the cloud user it created in place when the CredentialsRequest is deleted, for forensic
+	// or handover purposes.
+	DeletionPolicyRetain string = "retain"
+
+	// AnnotationFederationTokenDuration can be set on a CredentialsRequest, as a Go duration string
+	// (e.g. "1h", "30m"), to have the AWS mint actuator issue short-lived, auto-expiring credentials
+	// via STS GetFederationToken instead of a long-lived IAM user and access key. Intended for
+	// throwaway environments, such as short-lived CI clusters, where static keys that outlive the
+	// cluster are an unnecessary risk. The operator does not currently refresh the credential before
+	// it expires; reconciling the CredentialsRequest again (e.g. by annotating it to bump its
+	// generation) mints a fresh one.
+	AnnotationFederationTokenDuration string = "cloudcredential.openshift.io/federation-token-duration"
+
+	// AnnotationCredentialsExpiration records, in RFC 3339 format, when a credential minted via
+	// AnnotationFederationTokenDuration expires. Set on the target Secret alongside the credential.
+	AnnotationCredentialsExpiration string = "cloudcredential.openshift.io/credentials-expiration"
+
+	// AnnotationDisableDriftCorrection can be set on a CredentialsRequest to stop the mint actuator
+	// from re-applying its desired policy to the minted cloud user when it detects the live policy
+	// has drifted (e.g. from a manual edit). Useful when another controller or process is known to
+	// be intentionally managing the same cloud user's policy, so CCO doesn't fight it every reconcile.
+	AnnotationDisableDriftCorrection string = "cloudcredential.openshift.io/disable-drift-correction"
+
+	// AnnotationIBMResourceGroup can be set on a CredentialsRequest to scope the IBM Cloud access
+	// policies created for it to a specific resource group, overriding the --resource-group-name
+	// value ccoctl ibmcloud create-service-id was run with for this one request.
+	AnnotationIBMResourceGroup string = "cloudcredential.openshift.io/ibmcloud-resource-group"
+
+	// AnnotationCredentialsCreated records, in RFC 3339 format, when a credential was minted. Set
+	// on the target Secret alongside the credential when stamping rotation-governance metadata is
+	// enabled (see utils.StampMetadataEnabled / ccoctl's --stamp-metadata flag), so external
+	// tooling can alert on stale credentials without needing to inspect the cloud account directly.
+	AnnotationCredentialsCreated string = "cloudcredential.openshift.io/credentials-created"
+
+	// AnnotationCloudKeyID records the cloud-side identifier of the key/credential stored in the
+	// Secret (e.g. an AWS IAM access key ID, or an IBM Cloud API key ID), distinct from the secret
+	// value itself, so rotation tooling can correlate a Secret with the specific cloud-side key it
+	// wraps. Set alongside AnnotationCredentialsCreated.
+	AnnotationCloudKeyID string = "cloudcredential.openshift.io/cloud-key-id"
 )
 
 // NOTE: Run "make" to regenerate code after modifying this file
@@ -60,8 +121,51 @@ type CredentialsRequestSpec struct {
 	// credentials flow.
 	// +optional
 	ServiceAccountNames []string `json:"serviceAccountNames,omitempty"`
+
+	// DependsOn is an optional list of other CredentialsRequests (by namespace/name) that must
+	// report Provisioned before the controller will reconcile this one. Useful when a component's
+	// credential must not be minted until another component's credential already exists, e.g. to
+	// avoid a thundering herd of simultaneous cloud API calls during bulk provisioning, or to
+	// enforce an ordering dependency between components. Dependency cycles are detected and
+	// reported via the CredentialsRequestDependencyCycle condition instead of deadlocking.
+	// +optional
+	DependsOn []corev1.ObjectReference `json:"dependsOn,omitempty"`
+
+	// SyncPriority controls how often the controller re-reconciles this CredentialsRequest once it
+	// is provisioned, independent of any other CredentialsRequest. Core platform credentials should
+	// generally be left at the default so they keep being refreshed promptly; optional add-on
+	// credentials can be set to SyncPriorityLow to fall back to a longer resync interval and leave
+	// more headroom for higher-priority requests when the controller is processing many at once.
+	// Defaults to SyncPriorityNormal.
+	// +optional
+	// +kubebuilder:validation:Enum=High;Normal;Low
+	SyncPriority SyncPriority `json:"syncPriority,omitempty"`
+
+	// SecretType overrides the type of the generated Secret (Spec.SecretRef). Some consumers expect
+	// a specific type, e.g. kubernetes.io/basic-auth, rather than the default Opaque. Arbitrary
+	// custom types are allowed in addition to the well-known corev1.SecretType values. Defaults to
+	// corev1.SecretTypeOpaque.
+	// +optional
+	SecretType corev1.SecretType `json:"secretType,omitempty"`
 }
 
+// SyncPriority controls how frequently a CredentialsRequest is re-reconciled once provisioned.
+type SyncPriority string
+
+const (
+	// SyncPriorityHigh re-reconciles more frequently than the default, for credentials that must
+	// stay fresh even when the controller is working through a large backlog of other requests.
+	SyncPriorityHigh SyncPriority = "High"
+
+	// SyncPriorityNormal is the default resync interval.
+	SyncPriorityNormal SyncPriority = "Normal"
+
+	// SyncPriorityLow re-reconciles less frequently than the default, for optional credentials
+	// where a stale Secret for a while is an acceptable trade-off against giving higher-priority
+	// requests more of the controller's attention.
+	SyncPriorityLow SyncPriority = "Low"
+)
+
 // CredentialsRequestStatus defines the observed state of CredentialsRequest
 type CredentialsRequestStatus struct {
 	// Provisioned is true once the credentials have been initially provisioned.
@@ -86,6 +190,13 @@ type CredentialsRequestStatus struct {
 	// +optional
 	LastSyncCloudCredsSecretResourceVersion string `json:"lastSyncCloudCredsSecretResourceVersion,omitempty"`
 
+	// LastResyncNowObserved is the value of the cloudcredential.operator.openshift.io CR's
+	// resync-now annotation that was last honored by a sync of this credentials request. Used
+	// to detect that an admin has bumped the annotation to a new value and force a sync even if
+	// the credentials request would otherwise look recently synced.
+	// +optional
+	LastResyncNowObserved string `json:"lastResyncNowObserved,omitempty"`
+
 	// ProviderStatus contains cloud provider specific status.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	ProviderStatus *runtime.RawExtension `json:"providerStatus,omitempty"`
@@ -93,6 +204,25 @@ type CredentialsRequestStatus struct {
 	// Conditions includes detailed status for the CredentialsRequest
 	// +optional
 	Conditions []CredentialsRequestCondition `json:"conditions,omitempty"`
+
+	// TargetSecretResourceVersion is the resourceVersion of the generated Secret (Spec.SecretRef)
+	// as last observed by the controller immediately after writing it. Used together with
+	// TargetSecretHash to detect when the Secret has been modified out-of-band since then.
+	// +optional
+	TargetSecretResourceVersion string `json:"targetSecretResourceVersion,omitempty"`
+
+	// TargetSecretHash is a hash of the generated Secret's data (Spec.SecretRef) as last observed
+	// by the controller immediately after writing it. Used together with TargetSecretResourceVersion
+	// to detect when the Secret has been modified out-of-band since then.
+	// +optional
+	TargetSecretHash string `json:"targetSecretHash,omitempty"`
+
+	// LastWrittenSecretRef records the namespace/name of the Secret this controller last wrote
+	// successfully, which may differ from the current Spec.SecretRef if it has since been edited.
+	// Used to detect a changed SecretRef so the stale Secret at the old location can be deleted
+	// once the new one is written, instead of being orphaned.
+	// +optional
+	LastWrittenSecretRef *corev1.ObjectReference `json:"lastWrittenSecretRef,omitempty"`
 }
 
 // +genclient
@@ -170,6 +300,41 @@ const (
 	// OrphanedCloudResource is true when CCO was unable to delete a previously created
 	// App Registration / Service Principal while pivoting from Mint mode to Passthrough
 	OrphanedCloudResource CredentialsRequestConditionType = "OrphanedCloudResource"
+	// CredentialsRetained is true when the minted cloud user/credential was intentionally left in
+	// place, rather than deleted, because of an AnnotationDeletionPolicy of DeletionPolicyRetain.
+	CredentialsRetained CredentialsRequestConditionType = "CredentialsRetained"
+	// CredentialsDriftCorrected is true when the mint actuator detected that the live cloud policy
+	// had drifted from the CredentialsRequest's desired policy (e.g. a manual edit) and re-applied
+	// the desired policy to correct it.
+	CredentialsDriftCorrected CredentialsRequestConditionType = "CredentialsDriftCorrected"
+	// CredentialsRequestDependencyCycle is true when this CredentialsRequest's spec.dependsOn
+	// chain forms a cycle, so the controller cannot determine an order to provision them in.
+	CredentialsRequestDependencyCycle CredentialsRequestConditionType = "CredentialsRequestDependencyCycle"
+	// SecretWriteFailure is true when the cloud-side credentials were provisioned successfully but
+	// writing the resulting Secret to the cluster failed, e.g. due to a quota limit or an admission
+	// webhook rejecting the Secret. This is distinguished from CredentialsProvisionFailure so
+	// operators can tell cloud-side failures apart from Kubernetes-side ones.
+	SecretWriteFailure CredentialsRequestConditionType = "SecretWriteFailure"
+	// CloudQuotaExceeded is true when provisioning failed because the cloud account has exhausted
+	// an IAM-related quota (e.g. max IAM users/roles/policies/keys). Message includes the resource
+	// type that hit the limit, distinct from CredentialsProvisionFailure so platform teams can
+	// alert specifically on quota pressure before it cascades into broader provisioning failures.
+	CloudQuotaExceeded CredentialsRequestConditionType = "CloudQuotaExceeded"
+	// DeferredUntilWindow is true when the operator has a maintenance window configured (see
+	// utils.IsWithinMaintenanceWindow) and the current time falls outside of it, so the
+	// controller reconciled read-only and deferred the cloud mutation this CredentialsRequest
+	// otherwise needed. Not included in FailureConditionTypes, since deferring here is expected
+	// behavior rather than a failure. A rotate annotation request bypasses the window.
+	DeferredUntilWindow CredentialsRequestConditionType = "DeferredUntilWindow"
+	// CrossNamespaceTargetDisallowed is true when spec.secretRef.namespace differs from the
+	// CredentialsRequest's own namespace and that target namespace is not present in the
+	// operator's cross-namespace secret allowlist (see utils.CrossNamespaceSecretTargetAllowed),
+	// so the controller refused to write the Secret there.
+	CrossNamespaceTargetDisallowed CredentialsRequestConditionType = "CrossNamespaceTargetDisallowed"
+	// AdditionalSecretDataConfigMapNotFound is true when an AWSProviderSpec's
+	// AdditionalSecretDataConfigMapRef names a ConfigMap that does not exist, so the controller
+	// could not merge its contents into the generated Secret.
+	AdditionalSecretDataConfigMapNotFound CredentialsRequestConditionType = "AdditionalSecretDataConfigMapNotFound"
 )
 
 var (
@@ -180,5 +345,10 @@ var (
 		MissingTargetNamespace,
 		CredentialsProvisionFailure,
 		CredentialsDeprovisionFailure,
+		CredentialsRequestDependencyCycle,
+		SecretWriteFailure,
+		CloudQuotaExceeded,
+		CrossNamespaceTargetDisallowed,
+		AdditionalSecretDataConfigMapNotFound,
 	}
 )
@@ -29,6 +29,29 @@ type IBMCloudProviderSpec struct {
 
 	// Policies are a list of access policies to create for the generated credentials
 	Policies []AccessPolicy `json:"policies"`
+
+	// SecretKeys optionally overrides the default Secret .data key name CCO writes one or more
+	// generated credential fields under (see SecretKeyMapping), for consumers that expect
+	// different key names than CCO's defaults (e.g. "ibmcloud_api_key"). Fields not listed here
+	// keep their default key name. Validated against the actuator's known cloud fields when the
+	// CredentialsRequest is read.
+	// +optional
+	SecretKeys []SecretKeyMapping `json:"secretKeys,omitempty"`
+}
+
+// SecretKeyMapping declares the Secret .data key a generated credential field should be written
+// under, letting a CredentialsRequest's providerSpec override CCO's default key name for that
+// field (e.g. writing the IBM Cloud API key under "apiKey" instead of the default
+// "ibmcloud_api_key") without requiring a code change per consumer.
+type SecretKeyMapping struct {
+	// CloudField names the generated credential field to map, e.g. "apiKey", "region",
+	// "resourceGroupID", or "credentialsEnv". See the actuator for the current supported set;
+	// an unrecognized CloudField is rejected when the CredentialsRequest is read.
+	CloudField string `json:"cloudField"`
+
+	// SecretKey is the Secret .data key CloudField's value should be written under, replacing
+	// CCO's default key name for that field.
+	SecretKey string `json:"secretKey"`
 }
 
 // AccessPolicy is a definition of an IAM access policy
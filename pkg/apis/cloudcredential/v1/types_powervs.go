@@ -29,6 +29,11 @@ type IBMCloudPowerVSProviderSpec struct {
 
 	// Policies are a list of access policies to create for the generated credentials
 	Policies []AccessPolicy `json:"policies"`
+
+	// SecretKeys optionally overrides the default Secret .data key name CCO writes one or more
+	// generated credential fields under. See IBMCloudProviderSpec.SecretKeys and SecretKeyMapping.
+	// +optional
+	SecretKeys []SecretKeyMapping `json:"secretKeys,omitempty"`
 }
 
 // IBMCloudPowerVSProviderStatus contains the status of the IBM Cloud Power VS credentials request.
@@ -6,6 +6,7 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -20,6 +21,25 @@ func (in *AWSProviderSpec) DeepCopyInto(out *AWSProviderSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdditionalSecretData != nil {
+		in, out := &in.AdditionalSecretData, &out.AdditionalSecretData
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalSecrets != nil {
+		in, out := &in.AdditionalSecrets, &out.AdditionalSecrets
+		*out = make([]AdditionalSecretSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalSecretDataConfigMapRef != nil {
+		in, out := &in.AdditionalSecretDataConfigMapRef, &out.AdditionalSecretDataConfigMapRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	return
 }
 
@@ -45,6 +65,11 @@ func (in *AWSProviderSpec) DeepCopyObject() runtime.Object {
 func (in *AWSProviderStatus) DeepCopyInto(out *AWSProviderStatus) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
+	if in.AdditionalSecrets != nil {
+		in, out := &in.AdditionalSecrets, &out.AdditionalSecrets
+		*out = make([]AdditionalSecretStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -66,6 +91,47 @@ func (in *AWSProviderStatus) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalSecretSpec) DeepCopyInto(out *AdditionalSecretSpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.StatementEntries != nil {
+		in, out := &in.StatementEntries, &out.StatementEntries
+		*out = make([]StatementEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalSecretSpec.
+func (in *AdditionalSecretSpec) DeepCopy() *AdditionalSecretSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalSecretSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalSecretStatus) DeepCopyInto(out *AdditionalSecretStatus) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalSecretStatus.
+func (in *AdditionalSecretStatus) DeepCopy() *AdditionalSecretStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalSecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessPolicy) DeepCopyInto(out *AccessPolicy) {
 	*out = *in
@@ -318,6 +384,11 @@ func (in *CredentialsRequestSpec) DeepCopyInto(out *CredentialsRequestSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -350,6 +421,11 @@ func (in *CredentialsRequestStatus) DeepCopyInto(out *CredentialsRequestStatus)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastWrittenSecretRef != nil {
+		in, out := &in.LastWrittenSecretRef, &out.LastWrittenSecretRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
 	return
 }
 
@@ -429,6 +505,11 @@ func (in *IBMCloudPowerVSProviderSpec) DeepCopyInto(out *IBMCloudPowerVSProvider
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecretKeys != nil {
+		in, out := &in.SecretKeys, &out.SecretKeys
+		*out = make([]SecretKeyMapping, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -486,6 +567,11 @@ func (in *IBMCloudProviderSpec) DeepCopyInto(out *IBMCloudProviderSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecretKeys != nil {
+		in, out := &in.SecretKeys, &out.SecretKeys
+		*out = make([]SecretKeyMapping, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -714,6 +800,22 @@ func (in *RoleBinding) DeepCopy() *RoleBinding {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyMapping) DeepCopyInto(out *SecretKeyMapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyMapping.
+func (in *SecretKeyMapping) DeepCopy() *SecretKeyMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatementEntry) DeepCopyInto(out *StatementEntry) {
 	*out = *in
@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -21,12 +21,15 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
@@ -37,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	minterv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
@@ -55,18 +59,31 @@ const (
 	openshiftClusterIDKey     = "openshiftClusterID"
 	clusterVersionObjectName  = "version"
 
-	secretDataAccessKey = "aws_access_key_id"
-	secretDataSecretKey = "aws_secret_access_key"
+	secretDataAccessKey    = "aws_access_key_id"
+	secretDataSecretKey    = "aws_secret_access_key"
+	secretDataSessionToken = "aws_session_token"
+
+	// mintedKeyValidationInterval/Timeout bound how long syncMint will retry a harmless AWS API
+	// call with a newly minted access key before giving up on it, to ride out IAM's eventual
+	// consistency rather than writing a not-yet-usable key to the target Secret on the first try.
+	mintedKeyValidationInterval = 3 * time.Second
+	mintedKeyValidationTimeout  = 15 * time.Second
 )
 
+// managedSecretKeys lists the .data keys syncAccessKeySecret owns on a target Secret. Any other
+// keys present on the Secret (e.g. added by another controller sharing the same Secret) are left
+// untouched.
+var managedSecretKeys = []string{secretDataAccessKey, secretDataSecretKey, constants.AWSSecretDataCredentialsKey}
+
 var _ actuatoriface.Actuator = (*AWSActuator)(nil)
 
 // AWSActuator implements the CredentialsRequest Actuator interface to create credentials in AWS.
 type AWSActuator struct {
-	Client           client.Client
-	Codec            *minterv1.ProviderCodec
-	AWSClientBuilder func(accessKeyID, secretAccessKey []byte, c client.Client) (ccaws.Client, error)
-	Scheme           *runtime.Scheme
+	Client                   client.Client
+	Codec                    *minterv1.ProviderCodec
+	AWSClientBuilder         func(accessKeyID, secretAccessKey []byte, c client.Client) (ccaws.Client, error)
+	WebIdentityClientBuilder func(c client.Client) (ccaws.Client, error)
+	Scheme                   *runtime.Scheme
 }
 
 // NewAWSActuator creates a new AWSActuator.
@@ -78,10 +95,11 @@ func NewAWSActuator(client client.Client, scheme *runtime.Scheme) (*AWSActuator,
 	}
 
 	return &AWSActuator{
-		Codec:            codec,
-		Client:           client,
-		AWSClientBuilder: awsutils.ClientBuilder,
-		Scheme:           scheme,
+		Codec:                    codec,
+		Client:                   client,
+		AWSClientBuilder:         awsutils.ClientBuilder,
+		WebIdentityClientBuilder: awsutils.WebIdentityClientBuilder,
+		Scheme:                   scheme,
 	}, nil
 }
 
@@ -154,6 +172,11 @@ func (a *AWSActuator) needsUpdate(ctx context.Context, cr *minterv1.CredentialsR
 		return true, nil
 	}
 
+	if cr.Annotations[minterv1.AnnotationRotate] == "true" {
+		logger.Info("rotate annotation present, forcing update")
+		return true, nil
+	}
+
 	// Various checks for the kinds of reasons that would trigger a needed update
 	_, existingAccessKey, existingSecretKey, existingCredentialsKey := a.loadExistingSecret(cr)
 	awsClient, err := a.AWSClientBuilder([]byte(existingAccessKey), []byte(existingSecretKey), a.Client)
@@ -162,7 +185,7 @@ func (a *AWSActuator) needsUpdate(ctx context.Context, cr *minterv1.CredentialsR
 	}
 
 	// Make sure we update old Secrets that don't have the new "credentials" field
-	if existingCredentialsKey == "" || existingCredentialsKey != string(generateAWSCredentialsConfig(existingAccessKey, existingSecretKey)) {
+	if existingCredentialsKey == "" || existingCredentialsKey != string(generateAWSCredentialsConfig(existingAccessKey, existingSecretKey, "")) {
 		logger.Infof("Secret %s key needs updating, will update Secret contents", constants.AWSSecretDataCredentialsKey)
 		return true, nil
 	}
@@ -214,7 +237,7 @@ func (a *AWSActuator) needsUpdate(ctx context.Context, cr *minterv1.CredentialsR
 		if err != nil {
 			return true, err
 		}
-		if !userHasExpectedTags(logger, user.User, infraName, string(clusterUUID)) {
+		if !userHasExpectedTags(logger, user.User, infraName, string(clusterUUID), cr.Annotations) {
 			return true, nil
 		}
 
@@ -375,6 +398,16 @@ func (a *AWSActuator) syncPassthrough(ctx context.Context, cr *minterv1.Credenti
 	accessKeyID := string(cloudCredsSecret.Data[awsannotator.AwsAccessKeyName])
 	secretAccessKey := string(cloudCredsSecret.Data[awsannotator.AwsSecretAccessKeyName])
 
+	passthroughSpec, err := DecodeProviderSpec(a.Codec, cr)
+	if err != nil {
+		msg := "error decoding AWS ProviderSpec"
+		logger.WithError(err).Error(msg)
+		return &actuatoriface.ActuatorError{
+			ErrReason: minterv1.CredentialsProvisionFailure,
+			Message:   fmt.Sprintf("%v: %v", msg, err),
+		}
+	}
+
 	mode, _, err := utils.GetOperatorConfiguration(a.Client, logger)
 	if err != nil {
 		msg := "error getting operator configuration"
@@ -412,16 +445,7 @@ func (a *AWSActuator) syncPassthrough(ctx context.Context, cr *minterv1.Credenti
 			}
 		}
 
-		awsSpec, err := DecodeProviderSpec(a.Codec, cr)
-		if err != nil {
-			msg := "error decoding AWS ProviderSpec"
-			logger.WithError(err).Error(msg)
-			return &actuatoriface.ActuatorError{
-				ErrReason: minterv1.CredentialsProvisionFailure,
-				Message:   fmt.Sprintf("%v: %v", msg, err),
-			}
-		}
-		goodEnough, err := ccaws.CheckPermissionsAgainstStatementList(awsClient, awsSpec.StatementEntries, simParams, logger)
+		goodEnough, err := ccaws.CheckPermissionsAgainstStatementList(awsClient, passthroughSpec.StatementEntries, simParams, logger)
 		if err != nil {
 			msg := "error validating whether root creds are good enough"
 			logger.WithError(err).Error(msg)
@@ -440,8 +464,14 @@ func (a *AWSActuator) syncPassthrough(ctx context.Context, cr *minterv1.Credenti
 		}
 	}
 
-	// userPolicy param empty because in passthrough mode this doesn't really have any meaning
-	err = a.syncAccessKeySecret(cr, accessKeyID, secretAccessKey, existingSecret, "", logger)
+	additionalSecretData, err := a.resolveAdditionalSecretData(cr, passthroughSpec)
+	if err != nil {
+		return err
+	}
+
+	// userPolicy param empty because in passthrough mode this doesn't really have any meaning.
+	// No IAM user exists in passthrough mode, so there's nothing to source propagated tag labels from.
+	err = a.syncAccessKeySecret(cr, accessKeyID, secretAccessKey, existingSecret, "", additionalSecretData, nil, logger)
 	if err != nil {
 		msg := "error creating/updating secret"
 		logger.WithError(err).Error(msg)
@@ -463,6 +493,10 @@ func (a *AWSActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequ
 		return err
 	}
 
+	if err := validateUniqueSecretRefs(cr, awsSpec); err != nil {
+		return err
+	}
+
 	awsStatus, err := DecodeProviderStatus(a.Codec, cr)
 	if err != nil {
 		return err
@@ -473,6 +507,21 @@ func (a *AWSActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequ
 		return err
 	}
 
+	if rawDuration, ok := cr.Annotations[minterv1.AnnotationFederationTokenDuration]; ok && rawDuration != "" {
+		duration, err := time.ParseDuration(rawDuration)
+		if err != nil {
+			return fmt.Errorf("invalid %s annotation value %q: %v", minterv1.AnnotationFederationTokenDuration, rawDuration, err)
+		}
+		rootAWSClient, err := a.buildRootAWSClient(cr)
+		if err != nil {
+			return err
+		}
+		if rootAWSClient == nil {
+			return fmt.Errorf("no root AWS client available, cred secret may not exist: %s/%s", constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
+		}
+		return a.syncFederationToken(cr, awsSpec, infraName, rootAWSClient, duration, logger)
+	}
+
 	// Generate a randomized User for the credentials:
 	// TODO: check if the generated name is free
 	if awsStatus.User == "" {
@@ -545,12 +594,12 @@ func (a *AWSActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequ
 	}
 
 	// Check if the user has the expected tags:
-	if !userHasExpectedTags(logger, userOut, infraName, string(clusterUUID)) {
+	if !userHasExpectedTags(logger, userOut, infraName, string(clusterUUID), cr.Annotations) {
 		if rootAWSClient == nil {
 			return fmt.Errorf("no root AWS client available, cred secret may not exist: %s/%s", constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
 		}
 
-		err = a.tagUser(logger, rootAWSClient, awsStatus.User, infraName, string(clusterUUID))
+		err = a.tagUser(logger, rootAWSClient, awsStatus.User, infraName, string(clusterUUID), cr.Annotations)
 		if err != nil {
 			return err
 		}
@@ -564,14 +613,25 @@ func (a *AWSActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequ
 
 	policyEqual, err := a.awsPolicyEqualsDesiredPolicy(desiredUserPolicy, awsSpec, awsStatus, userOut, readAWSClient, logger)
 	if !policyEqual {
-		if rootAWSClient == nil {
-			return fmt.Errorf("no root AWS client available, cred secret may not exist: %s/%s", constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
-		}
-		err = a.setUserPolicy(logger, rootAWSClient, awsStatus.User, awsStatus.Policy, desiredUserPolicy)
-		if err != nil {
-			return err
+		if cr.Annotations[minterv1.AnnotationDisableDriftCorrection] == "true" {
+			logger.Warnf("user policy has drifted from the CredentialsRequest's desired policy, but %s annotation is set, leaving it as-is", minterv1.AnnotationDisableDriftCorrection)
+		} else {
+			if rootAWSClient == nil {
+				return fmt.Errorf("no root AWS client available, cred secret may not exist: %s/%s", constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
+			}
+			err = a.setUserPolicy(logger, rootAWSClient, awsStatus.User, awsStatus.Policy, desiredUserPolicy)
+			if err != nil {
+				return err
+			}
+			logger.Info("successfully set user policy")
+
+			cr.Status.Conditions = utils.SetCredentialsRequestCondition(cr.Status.Conditions, minterv1.CredentialsDriftCorrected, corev1.ConditionTrue,
+				"PolicyDriftCorrected", fmt.Sprintf("IAM user %s's policy had drifted from the CredentialsRequest's desired policy and was re-applied", awsStatus.User), utils.UpdateConditionAlways)
+			if err := a.Client.Status().Update(ctx, cr); err != nil {
+				logger.WithError(err).Error("error updating credentials request status")
+				return err
+			}
 		}
-		logger.Info("successfully set user policy")
 	}
 
 	logger.Debug("sync ListAccessKeys")
@@ -598,9 +658,14 @@ func (a *AWSActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequ
 		}
 	}
 
-	genNewAccessKey := existingSecret == nil || existingSecret.Name == "" || existingAccessKeyID == "" || !accessKeyExists
+	rotateRequested := cr.Annotations[minterv1.AnnotationRotate] == "true"
+	genNewAccessKey := existingSecret == nil || existingSecret.Name == "" || existingAccessKeyID == "" || !accessKeyExists || rotateRequested
 	if genNewAccessKey {
-		logger.Info("generating new AWS access key")
+		if rotateRequested {
+			logger.Info("rotate annotation present, rotating AWS access key")
+		} else {
+			logger.Info("generating new AWS access key")
+		}
 
 		// Users are allowed a max of two keys, if we decided we need to generate one,
 		// we should cleanup all pre-existing access keys. This will allow deleting the
@@ -618,6 +683,18 @@ func (a *AWSActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequ
 			logger.WithError(err).Error("error creating AWS access key")
 			return err
 		}
+
+		validateMintedKeys, err := utils.ValidateMintedKeysEnabled(a.Client, logger)
+		if err != nil {
+			logger.WithError(err).Error("error checking whether minted access keys should be validated")
+			return err
+		}
+		if validateMintedKeys {
+			if err := a.validateMintedAccessKey(logger, *accessKey.AccessKeyId, *accessKey.SecretAccessKey); err != nil {
+				logger.WithError(err).Error("newly minted access key failed validation, not writing it to the target secret")
+				return err
+			}
+		}
 	}
 
 	accessKeyString := ""
@@ -626,15 +703,276 @@ func (a *AWSActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequ
 		accessKeyString = *accessKey.AccessKeyId
 		secretAccessKeyString = *accessKey.SecretAccessKey
 	}
-	err = a.syncAccessKeySecret(cr, accessKeyString, secretAccessKeyString, existingSecret, desiredUserPolicy, logger)
+	additionalSecretData, err := a.resolveAdditionalSecretData(cr, awsSpec)
+	if err != nil {
+		return err
+	}
+
+	err = a.syncAccessKeySecret(cr, accessKeyString, secretAccessKeyString, existingSecret, desiredUserPolicy, additionalSecretData, iamTagsToMap(userOut.Tags), logger)
 	if err != nil {
 		log.WithError(err).Error("error saving access key to secret")
 		return err
 	}
 
+	for i, additionalSecret := range awsSpec.AdditionalSecrets {
+		if err := a.syncAdditionalSecret(ctx, cr, awsStatus, i, additionalSecret, infraName, rootAWSClient, readAWSClient, logger); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// syncFederationToken implements the AnnotationFederationTokenDuration alternative to the
+// static-access-key mint flow: rather than creating a long-lived IAM user and access key, it calls
+// STS GetFederationToken to mint short-lived, auto-expiring credentials scoped by the
+// CredentialsRequest's StatementEntries directly, with no IAM user created or needed.
+func (a *AWSActuator) syncFederationToken(cr *minterv1.CredentialsRequest, awsSpec *minterv1.AWSProviderSpec, infraName string, rootAWSClient minteraws.Client, duration time.Duration, logger log.FieldLogger) error {
+	policyDoc := PolicyDocument{Version: "2012-10-17"}
+	for _, se := range awsSpec.StatementEntries {
+		policyDoc.Statement = append(policyDoc.Statement, StatementEntry{
+			Effect:    se.Effect,
+			Action:    se.Action,
+			Resource:  se.Resource,
+			Condition: se.PolicyCondition,
+		})
+	}
+	policyJSON, err := json.Marshal(&policyDoc)
+	if err != nil {
+		return fmt.Errorf("error marshalling federation token policy: %v", err)
+	}
+
+	name, err := generateUserName(infraName, cr.Name)
+	if err != nil {
+		return err
+	}
+	// GetFederationToken session names are limited to 32 characters.
+	if len(name) > 32 {
+		name = name[:32]
+	}
+
+	out, err := rootAWSClient.GetFederationToken(&sts.GetFederationTokenInput{
+		Name:            aws.String(name),
+		Policy:          aws.String(string(policyJSON)),
+		DurationSeconds: aws.Int64(int64(duration.Seconds())),
+	})
+	if err != nil {
+		return fmt.Errorf("error getting federation token: %v", err)
+	}
+
+	additionalSecretData, err := a.resolveAdditionalSecretData(cr, awsSpec)
+	if err != nil {
+		return err
+	}
+
+	existingSecret, _, _, _ := a.loadExistingSecret(cr)
+	return a.syncFederatedAccessKeySecret(cr, cr.Spec.SecretRef, out.Credentials, existingSecret, string(policyJSON), additionalSecretData, logger)
+}
+
+// syncFederatedAccessKeySecret is syncAccessKeySecretRef's counterpart for federation-token
+// credentials: it additionally writes the session token (required alongside the access key and
+// secret key to use temporary credentials) and records the credential's expiration.
+func (a *AWSActuator) syncFederatedAccessKeySecret(cr *minterv1.CredentialsRequest, secretRef corev1.ObjectReference, creds *sts.Credentials, existingSecret *corev1.Secret, userPolicy string, additionalSecretData map[string]string, logger log.FieldLogger) error {
+	// No IAM user exists for federation-token credentials, so there's nothing to source
+	// propagated tag labels from.
+	if err := a.syncAccessKeySecretRef(cr, secretRef, *creds.AccessKeyId, *creds.SecretAccessKey, existingSecret, userPolicy, additionalSecretData, nil, logger); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	if err := a.Client.Get(context.TODO(), types.NamespacedName{Namespace: secretRef.Namespace, Name: secretRef.Name}, secret); err != nil {
+		return fmt.Errorf("error loading target secret to record federation token session data: %v", err)
+	}
+	secret.Data[secretDataSessionToken] = []byte(*creds.SessionToken)
+	secret.Data[constants.AWSSecretDataCredentialsKey] = generateAWSCredentialsConfig(*creds.AccessKeyId, *creds.SecretAccessKey, *creds.SessionToken)
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[minterv1.AnnotationCredentialsExpiration] = creds.Expiration.Format(time.RFC3339)
+	managedSecretKeys := append(append([]string{}, managedSecretKeys...), secretDataSessionToken)
+	secret.Annotations[minterv1.AnnotationManagedSecretKeys] = strings.Join(managedSecretKeys, ",")
+
+	logger.WithField("expiration", secret.Annotations[minterv1.AnnotationCredentialsExpiration]).Info("storing federation token credentials, caller is responsible for re-syncing before they expire")
+	return a.Client.Update(context.TODO(), secret)
+}
+
+// syncAdditionalSecret mints (or updates) a separate IAM user, policy, and access key for one
+// AWSProviderSpec.AdditionalSecrets entry, and syncs it into that entry's own SecretRef. It mirrors
+// the primary user/policy/access-key lifecycle in syncMint, but keyed off awsStatus.AdditionalSecrets[i]
+// instead of awsStatus.User/Policy so the additional credential persists across reconciles too.
+func (a *AWSActuator) syncAdditionalSecret(ctx context.Context, cr *minterv1.CredentialsRequest, awsStatus *minterv1.AWSProviderStatus, index int, additionalSecret minterv1.AdditionalSecretSpec, infraName string, rootAWSClient, readAWSClient minteraws.Client, logger log.FieldLogger) error {
+	secretRef := additionalSecret.SecretRef
+	logger = logger.WithField("additionalSecretRef", fmt.Sprintf("%s/%s", secretRef.Namespace, secretRef.Name))
+
+	for len(awsStatus.AdditionalSecrets) <= index {
+		awsStatus.AdditionalSecrets = append(awsStatus.AdditionalSecrets, minterv1.AdditionalSecretStatus{SecretRef: secretRef})
+	}
+	additionalStatus := &awsStatus.AdditionalSecrets[index]
+
+	if additionalStatus.User == "" {
+		username, err := generateUserName(infraName, fmt.Sprintf("%s-%s", cr.Name, secretRef.Name))
+		if err != nil {
+			return err
+		}
+		additionalStatus.User = username
+		additionalStatus.Policy = getPolicyName(username)
+		if err := a.updateProviderStatus(ctx, logger, cr, awsStatus); err != nil {
+			return err
+		}
+	}
+
+	var userOut *iam.User
+	getUserOut, err := readAWSClient.GetUser(&iam.GetUserInput{UserName: aws.String(additionalStatus.User)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case iam.ErrCodeNoSuchEntityException:
+				if rootAWSClient == nil {
+					return fmt.Errorf("no root AWS client available, cred secret may not exist: %s/%s", constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
+				}
+				createOut, err := a.createUser(logger, rootAWSClient, additionalStatus.User)
+				if err != nil {
+					return err
+				}
+				logger.WithField("userName", additionalStatus.User).Info("additional user created successfully")
+				userOut = createOut.User
+			default:
+				return formatAWSErr(aerr)
+			}
+		} else {
+			return fmt.Errorf("unknown error getting additional user from AWS: %v", err)
+		}
+	} else {
+		userOut = getUserOut.User
+	}
+
+	clusterUUID, err := a.loadClusterUUID(logger)
+	if err != nil {
+		return err
+	}
+	if !userHasExpectedTags(logger, userOut, infraName, string(clusterUUID), cr.Annotations) {
+		if rootAWSClient == nil {
+			return fmt.Errorf("no root AWS client available, cred secret may not exist: %s/%s", constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
+		}
+		if err := a.tagUser(logger, rootAWSClient, additionalStatus.User, infraName, string(clusterUUID), cr.Annotations); err != nil {
+			return err
+		}
+	}
+
+	desiredUserPolicy, err := a.getDesiredUserPolicy(additionalSecret.StatementEntries, *userOut.Arn)
+	if err != nil {
+		return err
+	}
+
+	currentUserPolicy, err := a.getCurrentUserPolicy(logger, readAWSClient, additionalStatus.User, additionalStatus.Policy)
+	if err != nil {
+		return err
+	}
+	if currentUserPolicy != desiredUserPolicy {
+		if rootAWSClient == nil {
+			return fmt.Errorf("no root AWS client available, cred secret may not exist: %s/%s", constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
+		}
+		if err := a.setUserPolicy(logger, rootAWSClient, additionalStatus.User, additionalStatus.Policy, desiredUserPolicy); err != nil {
+			return err
+		}
+		logger.Info("successfully set additional user policy")
+	}
+
+	allUserKeys, err := readAWSClient.ListAccessKeys(&iam.ListAccessKeysInput{UserName: aws.String(additionalStatus.User)})
+	if err != nil {
+		logger.WithError(err).Error("error listing all access keys for additional user")
+		return err
+	}
+
+	existingSecret, existingAccessKeyID, _, _ := a.loadExistingSecretRef(cr, secretRef)
+	accessKeyExists, err := a.accessKeyExists(logger, allUserKeys, existingAccessKeyID)
+	if err != nil {
+		return err
+	}
+
+	rotateRequested := cr.Annotations[minterv1.AnnotationRotate] == "true"
+	genNewAccessKey := existingSecret == nil || existingSecret.Name == "" || existingAccessKeyID == "" || !accessKeyExists || rotateRequested
+	var accessKey *iam.AccessKey
+	if genNewAccessKey {
+		if rootAWSClient == nil {
+			return fmt.Errorf("no root AWS client available, cred secret may not exist: %s/%s", constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
+		}
+		if err := a.deleteAllAccessKeys(logger, rootAWSClient, additionalStatus.User, allUserKeys); err != nil {
+			return err
+		}
+		accessKey, err = a.createAccessKey(logger, rootAWSClient, additionalStatus.User)
+		if err != nil {
+			logger.WithError(err).Error("error creating AWS access key for additional user")
+			return err
+		}
+	}
+
+	accessKeyString := ""
+	secretAccessKeyString := ""
+	if accessKey != nil {
+		accessKeyString = *accessKey.AccessKeyId
+		secretAccessKeyString = *accessKey.SecretAccessKey
+	}
+
+	return a.syncAccessKeySecretRef(cr, secretRef, accessKeyString, secretAccessKeyString, existingSecret, desiredUserPolicy, nil, iamTagsToMap(userOut.Tags), logger)
+}
+
+// validateUniqueSecretRefs ensures every AdditionalSecrets entry's SecretRef is distinct from the
+// CredentialsRequest's primary SecretRef and from each other, since two credentials written to the
+// same Secret would silently overwrite one another.
+func validateUniqueSecretRefs(cr *minterv1.CredentialsRequest, awsSpec *minterv1.AWSProviderSpec) error {
+	seen := map[string]bool{fmt.Sprintf("%s/%s", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name): true}
+	for _, additionalSecret := range awsSpec.AdditionalSecrets {
+		key := fmt.Sprintf("%s/%s", additionalSecret.SecretRef.Namespace, additionalSecret.SecretRef.Name)
+		if seen[key] {
+			return &actuatoriface.ActuatorError{
+				ErrReason: minterv1.CredentialsProvisionFailure,
+				Message:   fmt.Sprintf("AdditionalSecrets secretRef %s collides with another secretRef on this CredentialsRequest", key),
+			}
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// resolveAdditionalSecretData returns the additional data that should be written into the generated
+// Secret alongside the credential: awsSpec.AdditionalSecretData, plus the contents of
+// AdditionalSecretDataConfigMapRef's ConfigMap when one is set. The ConfigMap is looked up in the
+// CredentialsRequest's own namespace. A key present in both sources is treated as a configuration
+// error rather than silently picking one.
+func (a *AWSActuator) resolveAdditionalSecretData(cr *minterv1.CredentialsRequest, awsSpec *minterv1.AWSProviderSpec) (map[string]string, error) {
+	if awsSpec.AdditionalSecretDataConfigMapRef == nil {
+		return awsSpec.AdditionalSecretData, nil
+	}
+
+	cmName := awsSpec.AdditionalSecretDataConfigMapRef.Name
+	configMap := &corev1.ConfigMap{}
+	err := a.Client.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: cmName}, configMap)
+	if errors.IsNotFound(err) {
+		return nil, &actuatoriface.ActuatorError{
+			ErrReason: minterv1.AdditionalSecretDataConfigMapNotFound,
+			Message:   fmt.Sprintf("additionalSecretDataConfigMapRef %s/%s not found", cr.Namespace, cmName),
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("error fetching additionalSecretDataConfigMapRef %s/%s: %v", cr.Namespace, cmName, err)
+	}
+
+	merged := map[string]string{}
+	for k, v := range awsSpec.AdditionalSecretData {
+		merged[k] = v
+	}
+	for k, v := range configMap.Data {
+		if _, exists := merged[k]; exists {
+			return nil, &actuatoriface.ActuatorError{
+				ErrReason: minterv1.CredentialsProvisionFailure,
+				Message:   fmt.Sprintf("key %q from additionalSecretDataConfigMapRef %s/%s collides with additionalSecretData", k, cr.Namespace, cmName),
+			}
+		}
+		merged[k] = v
+	}
+	return merged, nil
+}
+
 func (a *AWSActuator) awsPolicyEqualsDesiredPolicy(desiredUserPolicy string, awsSpec *minterv1.AWSProviderSpec, awsStatus *minterv1.AWSProviderStatus, awsUser *iam.User, readAWSClient ccaws.Client, logger log.FieldLogger) (bool, error) {
 
 	currentUserPolicy, err := a.getCurrentUserPolicy(logger, readAWSClient, awsStatus.User, awsStatus.Policy)
@@ -651,7 +989,23 @@ func (a *AWSActuator) awsPolicyEqualsDesiredPolicy(desiredUserPolicy string, aws
 	return true, nil
 }
 
-func userHasExpectedTags(logger log.FieldLogger, user *iam.User, infraName, clusterUUID string) bool {
+// iamTagsToMap converts an IAM user's tags to a plain map for utils.BuildTagLabels, which expects
+// to look up tag values by key without caring about AWS's *iam.Tag representation.
+func iamTagsToMap(tags []*iam.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	tagMap := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.Key == nil || tag.Value == nil {
+			continue
+		}
+		tagMap[*tag.Key] = *tag.Value
+	}
+	return tagMap
+}
+
+func userHasExpectedTags(logger log.FieldLogger, user *iam.User, infraName, clusterUUID string, annotations map[string]string) bool {
 	// Check if the user has the expected tags:
 	if user == nil {
 		return false
@@ -675,6 +1029,13 @@ func userHasExpectedTags(logger log.FieldLogger, user *iam.User, infraName, clus
 
 	}
 
+	for key, value := range utils.BuildAnnotationTags(annotations) {
+		if !userHasTag(user, key, value) {
+			log.Warnf("user missing annotation-derived tag: %s=%s", key, value)
+			return false
+		}
+	}
+
 	return true
 }
 func (a *AWSActuator) updateProviderStatus(ctx context.Context, logger log.FieldLogger, cr *minterv1.CredentialsRequest, awsStatus *minterv1.AWSProviderStatus) error {
@@ -717,6 +1078,17 @@ func (a *AWSActuator) Delete(ctx context.Context, cr *minterv1.CredentialsReques
 	}
 	logger = logger.WithField("userName", awsStatus.User)
 
+	if cr.Annotations[minterv1.AnnotationDeletionPolicy] == minterv1.DeletionPolicyRetain {
+		logger.Info("deletion-policy annotation set to retain, leaving cloud user in place")
+		cr.Status.Conditions = utils.SetCredentialsRequestCondition(cr.Status.Conditions, minterv1.CredentialsRetained, corev1.ConditionTrue,
+			"DeletionPolicyRetain", fmt.Sprintf("cloud user %s was retained per the %s annotation", awsStatus.User, minterv1.AnnotationDeletionPolicy), utils.UpdateConditionAlways)
+		if err := a.Client.Status().Update(ctx, cr); err != nil {
+			logger.WithError(err).Error("error updating credentials request status")
+			return err
+		}
+		return nil
+	}
+
 	logger.Info("deleting credential from AWS")
 
 	awsClient, err := a.buildRootAWSClient(cr)
@@ -784,6 +1156,13 @@ func (a *AWSActuator) Delete(ctx context.Context, cr *minterv1.CredentialsReques
 }
 
 func (a *AWSActuator) loadExistingSecret(cr *minterv1.CredentialsRequest) (*corev1.Secret, string, string, string) {
+	return a.loadExistingSecretRef(cr, cr.Spec.SecretRef)
+}
+
+// loadExistingSecretRef is loadExistingSecret generalized to an arbitrary secretRef, so it can also
+// load the Secret for one of AWSProviderSpec.AdditionalSecrets rather than only the CredentialsRequest's
+// primary SecretRef.
+func (a *AWSActuator) loadExistingSecretRef(cr *minterv1.CredentialsRequest, secretRef corev1.ObjectReference) (*corev1.Secret, string, string, string) {
 	logger := a.getLogger(cr)
 	var existingAccessKeyID string
 	var existingSecretAccessKey string
@@ -791,7 +1170,7 @@ func (a *AWSActuator) loadExistingSecret(cr *minterv1.CredentialsRequest) (*core
 
 	// Check if the credentials secret exists, if not we need to inform the syncer to generate a new one:
 	existingSecret := &corev1.Secret{}
-	err := a.Client.Get(context.TODO(), types.NamespacedName{Namespace: cr.Spec.SecretRef.Namespace, Name: cr.Spec.SecretRef.Name}, existingSecret)
+	err := a.Client.Get(context.TODO(), types.NamespacedName{Namespace: secretRef.Namespace, Name: secretRef.Name}, existingSecret)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.Debug("secret does not exist")
@@ -825,7 +1204,7 @@ func (a *AWSActuator) loadExistingSecret(cr *minterv1.CredentialsRequest) (*core
 	return existingSecret, existingAccessKeyID, existingSecretAccessKey, existingCredentialsKey
 }
 
-func (a *AWSActuator) tagUser(logger log.FieldLogger, awsClient minteraws.Client, username, infraName, clusterUUID string) error {
+func (a *AWSActuator) tagUser(logger log.FieldLogger, awsClient minteraws.Client, username, infraName, clusterUUID string, annotations map[string]string) error {
 	logger.WithField("infraName", infraName).Info("tagging user with infrastructure name")
 	tags := []*iam.Tag{}
 	if infraName != "" {
@@ -840,6 +1219,13 @@ func (a *AWSActuator) tagUser(logger log.FieldLogger, awsClient minteraws.Client
 		})
 	}
 
+	for key, value := range utils.BuildAnnotationTags(annotations) {
+		tags = append(tags, &iam.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
 	_, err := awsClient.TagUser(&iam.TagUserInput{
 		UserName: aws.String(username),
 		Tags:     tags,
@@ -861,6 +1247,10 @@ func (a *AWSActuator) buildRootAWSClient(cr *minterv1.CredentialsRequest) (minte
 	// cluster, we need to load different secrets for each cluster.
 	accessKeyID, secretAccessKey, err := utils.LoadCredsFromSecret(a.Client, constants.CloudCredSecretNamespace, constants.AWSCloudCredSecretName)
 	if err != nil {
+		if errors.IsNotFound(err) && awsutils.HasWebIdentityCredentials() {
+			logger.Debug("root creds Secret not found, falling back to pod's projected web identity token")
+			return a.WebIdentityClientBuilder(a.Client)
+		}
 		return nil, err
 	}
 
@@ -929,12 +1319,34 @@ func (a *AWSActuator) getLogger(cr *minterv1.CredentialsRequest) log.FieldLogger
 	})
 }
 
-func (a *AWSActuator) syncAccessKeySecret(cr *minterv1.CredentialsRequest, accessKeyID, secretAccessKey string, existingSecret *corev1.Secret, userPolicy string, logger log.FieldLogger) error {
+func (a *AWSActuator) syncAccessKeySecret(cr *minterv1.CredentialsRequest, accessKeyID, secretAccessKey string, existingSecret *corev1.Secret, userPolicy string, additionalSecretData map[string]string, cloudTags map[string]string, logger log.FieldLogger) error {
+	return a.syncAccessKeySecretRef(cr, cr.Spec.SecretRef, accessKeyID, secretAccessKey, existingSecret, userPolicy, additionalSecretData, cloudTags, logger)
+}
+
+// syncAccessKeySecretRef is syncAccessKeySecret generalized to an arbitrary secretRef, so it can also
+// sync the Secret for one of AWSProviderSpec.AdditionalSecrets rather than only the CredentialsRequest's
+// primary SecretRef. cloudTags, if non-nil, is the set of tags found on the IAM user the access key
+// belongs to, used to populate labels per utils.PropagateTagLabelKeys().
+func (a *AWSActuator) syncAccessKeySecretRef(cr *minterv1.CredentialsRequest, secretRef corev1.ObjectReference, accessKeyID, secretAccessKey string, existingSecret *corev1.Secret, userPolicy string, additionalSecretData map[string]string, cloudTags map[string]string, logger log.FieldLogger) error {
+	tagLabels := utils.BuildTagLabels(cloudTags)
 	sLog := logger.WithFields(log.Fields{
-		"targetSecret": fmt.Sprintf("%s/%s", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name),
+		"targetSecret": fmt.Sprintf("%s/%s", secretRef.Namespace, secretRef.Name),
 		"cr":           fmt.Sprintf("%s/%s", cr.Namespace, cr.Name),
 	})
 
+	for key := range additionalSecretData {
+		for _, managedKey := range managedSecretKeys {
+			if key == managedKey {
+				msg := fmt.Sprintf("additionalSecretData key %q collides with a credential key managed by CCO", key)
+				sLog.Error(msg)
+				return &actuatoriface.ActuatorError{
+					ErrReason: minterv1.CredentialsProvisionFailure,
+					Message:   msg,
+				}
+			}
+		}
+	}
+
 	if existingSecret == nil || existingSecret.Name == "" {
 		if accessKeyID == "" || secretAccessKey == "" {
 			msg := "new access key secret needed but no key data provided"
@@ -945,26 +1357,44 @@ func (a *AWSActuator) syncAccessKeySecret(cr *minterv1.CredentialsRequest, acces
 			}
 		}
 		sLog.Info("creating secret")
+		annotations := map[string]string{
+			minterv1.AnnotationCredentialsRequest:   fmt.Sprintf("%s/%s", cr.Namespace, cr.Name),
+			minterv1.AnnotationAWSPolicyLastApplied: userPolicy,
+			minterv1.AnnotationManagedSecretKeys:    strings.Join(managedSecretKeys, ","),
+		}
+		if utils.StampMetadataEnabled() {
+			annotations[minterv1.AnnotationCredentialsCreated] = time.Now().UTC().Format(time.RFC3339)
+			annotations[minterv1.AnnotationCloudKeyID] = accessKeyID
+		}
 		secret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.Spec.SecretRef.Name,
-				Namespace: cr.Spec.SecretRef.Namespace,
-				Annotations: map[string]string{
-					minterv1.AnnotationCredentialsRequest:   fmt.Sprintf("%s/%s", cr.Namespace, cr.Name),
-					minterv1.AnnotationAWSPolicyLastApplied: userPolicy,
-				},
+				Name:        secretRef.Name,
+				Namespace:   secretRef.Namespace,
+				Labels:      tagLabels,
+				Annotations: annotations,
 			},
+			Type: cr.Spec.SecretType,
 			Data: map[string][]byte{
 				secretDataAccessKey:                   []byte(accessKeyID),
 				secretDataSecretKey:                   []byte(secretAccessKey),
-				constants.AWSSecretDataCredentialsKey: generateAWSCredentialsConfig(accessKeyID, secretAccessKey),
+				constants.AWSSecretDataCredentialsKey: generateAWSCredentialsConfig(accessKeyID, secretAccessKey, ""),
 			},
 		}
+		for key, value := range additionalSecretData {
+			secret.Data[key] = []byte(value)
+		}
+		if utils.ImmutableSecretsEnabled() {
+			secret.Immutable = aws.Bool(true)
+		}
 
 		err := a.Client.Create(context.TODO(), secret)
 		if err != nil {
-			sLog.WithError(err).Error("error creating secret")
-			return err
+			msg := "error creating secret"
+			sLog.WithError(err).Error(msg)
+			return &actuatoriface.ActuatorError{
+				ErrReason: minterv1.SecretWriteFailure,
+				Message:   fmt.Sprintf("%s: %v", msg, err),
+			}
 		}
 		sLog.Info("secret created successfully")
 		return nil
@@ -973,28 +1403,71 @@ func (a *AWSActuator) syncAccessKeySecret(cr *minterv1.CredentialsRequest, acces
 	// Update the existing secret:
 	sLog.Debug("updating secret")
 	origSecret := existingSecret.DeepCopy()
+	for key, value := range tagLabels {
+		if existingSecret.Labels == nil {
+			existingSecret.Labels = map[string]string{}
+		}
+		existingSecret.Labels[key] = value
+	}
 	if existingSecret.Annotations == nil {
 		existingSecret.Annotations = map[string]string{}
 	}
 	existingSecret.Annotations[minterv1.AnnotationCredentialsRequest] = fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)
 	existingSecret.Annotations[minterv1.AnnotationAWSPolicyLastApplied] = userPolicy
+	existingSecret.Annotations[minterv1.AnnotationManagedSecretKeys] = strings.Join(managedSecretKeys, ",")
+	if utils.StampMetadataEnabled() && accessKeyID != "" && existingSecret.Annotations[minterv1.AnnotationCloudKeyID] != accessKeyID {
+		// The access key ID changed (new key minted or rotated in), so the recorded creation time
+		// needs to move forward with it rather than being left stamped with the prior key's age.
+		existingSecret.Annotations[minterv1.AnnotationCredentialsCreated] = time.Now().UTC().Format(time.RFC3339)
+		existingSecret.Annotations[minterv1.AnnotationCloudKeyID] = accessKeyID
+	}
+	if existingSecret.Data == nil {
+		existingSecret.Data = map[string][]byte{}
+	}
 	if accessKeyID != "" && secretAccessKey != "" {
 		existingSecret.Data[secretDataAccessKey] = []byte(accessKeyID)
 		existingSecret.Data[secretDataSecretKey] = []byte(secretAccessKey)
 	}
 
 	// Make sure credentials config data is synced with the stored access key / secret key
-	existingSecret.Data[constants.AWSSecretDataCredentialsKey] = generateAWSCredentialsConfig(string(existingSecret.Data[secretDataAccessKey]), string(existingSecret.Data[secretDataSecretKey]))
+	existingSecret.Data[constants.AWSSecretDataCredentialsKey] = generateAWSCredentialsConfig(string(existingSecret.Data[secretDataAccessKey]), string(existingSecret.Data[secretDataSecretKey]), string(existingSecret.Data[secretDataSessionToken]))
+
+	for key, value := range additionalSecretData {
+		existingSecret.Data[key] = []byte(value)
+	}
 
 	if !reflect.DeepEqual(existingSecret, origSecret) {
-		sLog.Info("target secret has changed, updating")
-		err := a.Client.Update(context.TODO(), existingSecret)
-		if err != nil {
-			msg := "error updating secret"
-			sLog.WithError(err).Error(msg)
-			return &actuatoriface.ActuatorError{
-				ErrReason: minterv1.CredentialsProvisionFailure,
-				Message:   msg,
+		if origSecret.Immutable != nil && *origSecret.Immutable && !reflect.DeepEqual(existingSecret.Data, origSecret.Data) {
+			// An immutable Secret's Data can't be changed in place, so recreate it instead of
+			// updating it. The Secret keeps its name, so nothing referencing it needs to change.
+			sLog.Info("target secret data has changed but secret is immutable, deleting and recreating")
+			if err := a.Client.Delete(context.TODO(), origSecret); err != nil {
+				msg := "error deleting immutable secret for recreation"
+				sLog.WithError(err).Error(msg)
+				return &actuatoriface.ActuatorError{
+					ErrReason: minterv1.SecretWriteFailure,
+					Message:   msg,
+				}
+			}
+			existingSecret.ResourceVersion = ""
+			if err := a.Client.Create(context.TODO(), existingSecret); err != nil {
+				msg := "error recreating immutable secret"
+				sLog.WithError(err).Error(msg)
+				return &actuatoriface.ActuatorError{
+					ErrReason: minterv1.SecretWriteFailure,
+					Message:   msg,
+				}
+			}
+		} else {
+			sLog.Info("target secret has changed, updating")
+			err := a.Client.Update(context.TODO(), existingSecret)
+			if err != nil {
+				msg := "error updating secret"
+				sLog.WithError(err).Error(msg)
+				return &actuatoriface.ActuatorError{
+					ErrReason: minterv1.SecretWriteFailure,
+					Message:   msg,
+				}
 			}
 		}
 	} else {
@@ -1172,6 +1645,33 @@ func (a *AWSActuator) createAccessKey(logger log.FieldLogger, awsClient minteraw
 	return accessKeyResult.AccessKey, err
 }
 
+// validateMintedAccessKey builds a client from a newly minted access key and retries a harmless
+// read-only AWS API call (GetUser) against it until it succeeds or mintedKeyValidationTimeout
+// elapses, to ride out IAM's eventual-consistency window before the key is written to the target
+// Secret. Only called when the operator config opts into it, since it costs an extra AWS round
+// trip (or several, on retry) per newly minted key.
+func (a *AWSActuator) validateMintedAccessKey(logger log.FieldLogger, accessKeyID, secretAccessKey string) error {
+	newKeyClient, err := a.AWSClientBuilder([]byte(accessKeyID), []byte(secretAccessKey), a.Client)
+	if err != nil {
+		return fmt.Errorf("error building AWS client to validate newly minted access key: %v", err)
+	}
+
+	logger.Debug("validating newly minted access key is usable")
+	err = wait.PollImmediate(mintedKeyValidationInterval, mintedKeyValidationTimeout, func() (bool, error) {
+		if _, err := newKeyClient.GetUser(&iam.GetUserInput{}); err != nil {
+			logger.WithError(err).Debug("newly minted access key not yet usable, will retry")
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("newly minted access key was not usable within %s: %v", mintedKeyValidationTimeout, err)
+	}
+
+	logger.Debug("newly minted access key validated as usable")
+	return nil
+}
+
 func userHasTag(user *iam.User, key, val string) bool {
 	for _, t := range user.Tags {
 		if *t.Key == key && *t.Value == val {
@@ -1321,10 +1821,17 @@ func (a *AWSActuator) Upgradeable(mode operatorv1.CloudCredentialsMode) *configv
 	return utils.UpgradeableCheck(a.Client, mode, a.GetCredentialsRootSecretLocation())
 }
 
-func generateAWSCredentialsConfig(accessKeyID, secretAccessKey string) []byte {
+// generateAWSCredentialsConfig builds the AWS CLI/SDK shared config ini content for the given
+// credential. sessionToken is only needed for temporary credentials (e.g. from
+// syncFederationToken); pass "" for long-lived IAM user access keys.
+func generateAWSCredentialsConfig(accessKeyID, secretAccessKey, sessionToken string) []byte {
 	awsConfig := fmt.Sprintf(`[default]
 %s = %s
 %s = %s`, secretDataAccessKey, accessKeyID, secretDataSecretKey, secretAccessKey)
 
+	if sessionToken != "" {
+		awsConfig = fmt.Sprintf("%s\n%s = %s", awsConfig, secretDataSessionToken, sessionToken)
+	}
+
 	return []byte(awsConfig)
 }
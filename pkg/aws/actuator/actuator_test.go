@@ -19,13 +19,19 @@ package actuator
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 
@@ -43,6 +49,8 @@ import (
 	ccaws "github.com/openshift/cloud-credential-operator/pkg/aws"
 	mockaws "github.com/openshift/cloud-credential-operator/pkg/aws/mock"
 	"github.com/openshift/cloud-credential-operator/pkg/operator/constants"
+	actuatoriface "github.com/openshift/cloud-credential-operator/pkg/operator/credentialsrequest/actuator"
+	"github.com/openshift/cloud-credential-operator/pkg/operator/utils"
 	"github.com/openshift/cloud-credential-operator/pkg/util"
 )
 
@@ -457,7 +465,7 @@ func TestSecretFormat(t *testing.T) {
 
 			cr := testCredentialsRequest()
 			logger := a.getLogger(cr)
-			err := a.syncAccessKeySecret(cr, test.accessKeyID, test.secretAccessKey, test.existingSecret, "exampleAWSPolicy", logger)
+			err := a.syncAccessKeySecret(cr, test.accessKeyID, test.secretAccessKey, test.existingSecret, "exampleAWSPolicy", nil, nil, logger)
 
 			require.NoError(t, err, "unexpected error creating/updating Secret")
 
@@ -480,6 +488,256 @@ func TestSecretFormat(t *testing.T) {
 	}
 }
 
+func TestImmutableSecrets(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	os.Setenv(utils.EnvImmutableSecrets, "true")
+	defer os.Unsetenv(utils.EnvImmutableSecrets)
+
+	fakeClient := fake.NewClientBuilder().Build()
+	a := &AWSActuator{
+		Client: fakeClient,
+	}
+
+	cr := testCredentialsRequest()
+	logger := a.getLogger(cr)
+	err := a.syncAccessKeySecret(cr, "AKFIRSTKEY", "FIRSTSECRET", nil, "exampleAWSPolicy", nil, nil, logger)
+	require.NoError(t, err, "unexpected error creating Secret")
+
+	secret := &corev1.Secret{}
+	secretNSN := types.NamespacedName{Name: cr.Spec.SecretRef.Name, Namespace: cr.Spec.SecretRef.Namespace}
+	err = fakeClient.Get(context.TODO(), secretNSN, secret)
+	require.NoError(t, err, "unexpected error retriving Secret")
+
+	require.NotNil(t, secret.Immutable, "expected Immutable to be set when EnvImmutableSecrets is enabled")
+	assert.True(t, *secret.Immutable)
+}
+
+func TestSecretType(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	fakeClient := fake.NewClientBuilder().Build()
+	a := &AWSActuator{
+		Client: fakeClient,
+	}
+
+	cr := testCredentialsRequest()
+	cr.Spec.SecretType = corev1.SecretTypeBasicAuth
+	logger := a.getLogger(cr)
+	err := a.syncAccessKeySecret(cr, "AKFIRSTKEY", "FIRSTSECRET", nil, "exampleAWSPolicy", nil, nil, logger)
+	require.NoError(t, err, "unexpected error creating Secret")
+
+	secret := &corev1.Secret{}
+	secretNSN := types.NamespacedName{Name: cr.Spec.SecretRef.Name, Namespace: cr.Spec.SecretRef.Namespace}
+	err = fakeClient.Get(context.TODO(), secretNSN, secret)
+	require.NoError(t, err, "unexpected error retriving Secret")
+
+	assert.Equal(t, corev1.SecretTypeBasicAuth, secret.Type, "expected generated Secret to carry the CredentialsRequest's SecretType")
+}
+
+func TestResolveAdditionalSecretData(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	t.Run("no ConfigMapRef returns AdditionalSecretData as-is", func(t *testing.T) {
+		a := &AWSActuator{Client: fake.NewClientBuilder().Build()}
+		cr := testCredentialsRequest()
+		awsSpec := &minterv1.AWSProviderSpec{AdditionalSecretData: map[string]string{"region": "us-east-1"}}
+
+		data, err := a.resolveAdditionalSecretData(cr, awsSpec)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"region": "us-east-1"}, data)
+	})
+
+	t.Run("merges the referenced ConfigMap's data", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "extra-config", Namespace: "testnamespace"},
+			Data:       map[string]string{"ca.crt": "fake-ca-bundle"},
+		}
+		a := &AWSActuator{Client: fake.NewClientBuilder().WithObjects(configMap).Build()}
+		cr := testCredentialsRequest()
+		awsSpec := &minterv1.AWSProviderSpec{
+			AdditionalSecretData:             map[string]string{"region": "us-east-1"},
+			AdditionalSecretDataConfigMapRef: &corev1.LocalObjectReference{Name: "extra-config"},
+		}
+
+		data, err := a.resolveAdditionalSecretData(cr, awsSpec)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"region": "us-east-1", "ca.crt": "fake-ca-bundle"}, data)
+	})
+
+	t.Run("missing ConfigMap returns AdditionalSecretDataConfigMapNotFound", func(t *testing.T) {
+		a := &AWSActuator{Client: fake.NewClientBuilder().Build()}
+		cr := testCredentialsRequest()
+		awsSpec := &minterv1.AWSProviderSpec{AdditionalSecretDataConfigMapRef: &corev1.LocalObjectReference{Name: "missing-config"}}
+
+		_, err := a.resolveAdditionalSecretData(cr, awsSpec)
+		require.Error(t, err)
+		actuatorErr, ok := err.(*actuatoriface.ActuatorError)
+		require.True(t, ok, "expected an ActuatorError, got %T", err)
+		assert.Equal(t, minterv1.AdditionalSecretDataConfigMapNotFound, actuatorErr.Reason())
+	})
+
+	t.Run("colliding key between AdditionalSecretData and the ConfigMap is an error", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "extra-config", Namespace: "testnamespace"},
+			Data:       map[string]string{"region": "us-west-2"},
+		}
+		a := &AWSActuator{Client: fake.NewClientBuilder().WithObjects(configMap).Build()}
+		cr := testCredentialsRequest()
+		awsSpec := &minterv1.AWSProviderSpec{
+			AdditionalSecretData:             map[string]string{"region": "us-east-1"},
+			AdditionalSecretDataConfigMapRef: &corev1.LocalObjectReference{Name: "extra-config"},
+		}
+
+		_, err := a.resolveAdditionalSecretData(cr, awsSpec)
+		require.Error(t, err)
+	})
+}
+
+func TestStampMetadata(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	os.Setenv(utils.EnvStampMetadata, "true")
+	defer os.Unsetenv(utils.EnvStampMetadata)
+
+	fakeClient := fake.NewClientBuilder().Build()
+	a := &AWSActuator{
+		Client: fakeClient,
+	}
+
+	cr := testCredentialsRequest()
+	logger := a.getLogger(cr)
+	err := a.syncAccessKeySecret(cr, "AKFIRSTKEY", "FIRSTSECRET", nil, "exampleAWSPolicy", nil, nil, logger)
+	require.NoError(t, err, "unexpected error creating Secret")
+
+	secret := &corev1.Secret{}
+	secretNSN := types.NamespacedName{Name: cr.Spec.SecretRef.Name, Namespace: cr.Spec.SecretRef.Namespace}
+	err = fakeClient.Get(context.TODO(), secretNSN, secret)
+	require.NoError(t, err, "unexpected error retriving Secret")
+
+	assert.Equal(t, "AKFIRSTKEY", secret.Annotations[minterv1.AnnotationCloudKeyID])
+	firstCreated := secret.Annotations[minterv1.AnnotationCredentialsCreated]
+	_, err = time.Parse(time.RFC3339, firstCreated)
+	require.NoError(t, err, "expected AnnotationCredentialsCreated to be RFC3339")
+
+	// Rotating to a new access key should move the recorded creation time forward.
+	err = a.syncAccessKeySecret(cr, "AKSECONDKEY", "SECONDSECRET", secret, "exampleAWSPolicy", nil, nil, logger)
+	require.NoError(t, err, "unexpected error updating Secret")
+
+	err = fakeClient.Get(context.TODO(), secretNSN, secret)
+	require.NoError(t, err, "unexpected error retriving updated Secret")
+	assert.Equal(t, "AKSECONDKEY", secret.Annotations[minterv1.AnnotationCloudKeyID])
+}
+
+func TestPropagateTagLabels(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	os.Setenv(utils.EnvPropagateTagLabels, "cost-center, not-present")
+	defer os.Unsetenv(utils.EnvPropagateTagLabels)
+
+	fakeClient := fake.NewClientBuilder().Build()
+	a := &AWSActuator{
+		Client: fakeClient,
+	}
+
+	cr := testCredentialsRequest()
+	logger := a.getLogger(cr)
+	cloudTags := map[string]string{"cost-center": "Team Awesome!"}
+	err := a.syncAccessKeySecret(cr, "AKFIRSTKEY", "FIRSTSECRET", nil, "exampleAWSPolicy", nil, cloudTags, logger)
+	require.NoError(t, err, "unexpected error creating Secret")
+
+	secret := &corev1.Secret{}
+	secretNSN := types.NamespacedName{Name: cr.Spec.SecretRef.Name, Namespace: cr.Spec.SecretRef.Namespace}
+	err = fakeClient.Get(context.TODO(), secretNSN, secret)
+	require.NoError(t, err, "unexpected error retriving Secret")
+
+	assert.Equal(t, "Team-Awesome", secret.Labels[utils.TagLabelKey("cost-center")], "expected sanitized tag value propagated as a label")
+	assert.NotContains(t, secret.Labels, utils.TagLabelKey("not-present"), "tag absent from the cloud resource should not produce a label")
+}
+
+func TestTagUserWithAnnotationTags(t *testing.T) {
+	os.Setenv(utils.EnvAnnotationTagPrefixes, "tags.example.com/")
+	defer os.Unsetenv(utils.EnvAnnotationTagPrefixes)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockAWSClient := mockaws.NewMockClient(mockCtrl)
+	mockAWSClient.EXPECT().TagUser(gomock.Any()).DoAndReturn(func(input *iam.TagUserInput) (*iam.TagUserOutput, error) {
+		tags := map[string]string{}
+		for _, tag := range input.Tags {
+			tags[*tag.Key] = *tag.Value
+		}
+		assert.Equal(t, "owned", tags["kubernetes.io/cluster/testcluster"])
+		assert.Equal(t, "team-awesome", tags["tags.example.com/cost-center"])
+		assert.NotContains(t, tags, "kubectl.kubernetes.io/other")
+		return &iam.TagUserOutput{}, nil
+	})
+
+	a := &AWSActuator{}
+	logger := log.WithField("controller", "testing")
+	annotations := map[string]string{
+		"tags.example.com/cost-center": "team-awesome",
+		"kubectl.kubernetes.io/other":  "should-not-be-propagated",
+	}
+
+	err := a.tagUser(logger, mockAWSClient, "testuser", "testcluster", "", annotations)
+	require.NoError(t, err)
+}
+
+func TestUserHasExpectedTagsWithAnnotationTags(t *testing.T) {
+	os.Setenv(utils.EnvAnnotationTagPrefixes, "tags.example.com/")
+	defer os.Unsetenv(utils.EnvAnnotationTagPrefixes)
+
+	logger := log.WithField("controller", "testing")
+	annotations := map[string]string{"tags.example.com/cost-center": "team-awesome"}
+
+	user := &iam.User{
+		Tags: []*iam.Tag{
+			{Key: aws.String("kubernetes.io/cluster/testcluster"), Value: aws.String("owned")},
+		},
+	}
+	assert.False(t, userHasExpectedTags(logger, user, "testcluster", "", annotations), "expected missing annotation-derived tag to be detected")
+
+	user.Tags = append(user.Tags, &iam.Tag{Key: aws.String("tags.example.com/cost-center"), Value: aws.String("team-awesome")})
+	assert.True(t, userHasExpectedTags(logger, user, "testcluster", "", annotations))
+}
+
+func TestValidateMintedAccessKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger := log.WithField("controller", "testing")
+
+	t.Run("access key usable immediately", func(t *testing.T) {
+		mockAWSClient := mockaws.NewMockClient(mockCtrl)
+		mockAWSClient.EXPECT().GetUser(gomock.Any()).Return(&iam.GetUserOutput{}, nil)
+		a := &AWSActuator{
+			AWSClientBuilder: func(accessKeyID, secretAccessKey []byte, c client.Client) (ccaws.Client, error) {
+				return mockAWSClient, nil
+			},
+		}
+
+		err := a.validateMintedAccessKey(logger, "newAccessKeyID", "newSecretAccessKey")
+		require.NoError(t, err)
+	})
+
+	t.Run("access key becomes usable after IAM propagation delay", func(t *testing.T) {
+		mockAWSClient := mockaws.NewMockClient(mockCtrl)
+		gomock.InOrder(
+			mockAWSClient.EXPECT().GetUser(gomock.Any()).Return(nil, fmt.Errorf("InvalidClientTokenId")),
+			mockAWSClient.EXPECT().GetUser(gomock.Any()).Return(&iam.GetUserOutput{}, nil),
+		)
+		a := &AWSActuator{
+			AWSClientBuilder: func(accessKeyID, secretAccessKey []byte, c client.Client) (ccaws.Client, error) {
+				return mockAWSClient, nil
+			},
+		}
+
+		err := a.validateMintedAccessKey(logger, "newAccessKeyID", "newSecretAccessKey")
+		require.NoError(t, err)
+	})
+}
+
 func testReadOnlySecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
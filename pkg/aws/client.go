@@ -21,12 +21,15 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 
 	"github.com/openshift/cloud-credential-operator/pkg/version"
 )
@@ -45,10 +48,14 @@ type Client interface {
 	DeleteUserPolicy(*iam.DeleteUserPolicyInput) (*iam.DeleteUserPolicyOutput, error)
 	GetOpenIDConnectProvider(input *iam.GetOpenIDConnectProviderInput) (*iam.GetOpenIDConnectProviderOutput, error)
 	GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, error)
+	GetRolePolicy(input *iam.GetRolePolicyInput) (*iam.GetRolePolicyOutput, error)
 	ListRoles(input *iam.ListRolesInput) (*iam.ListRolesOutput, error)
 	DeleteRole(input *iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error)
 	ListRolePolicies(input *iam.ListRolePoliciesInput) (*iam.ListRolePoliciesOutput, error)
 	DeleteRolePolicy(input *iam.DeleteRolePolicyInput) (*iam.DeleteRolePolicyOutput, error)
+	CreatePolicyVersion(input *iam.CreatePolicyVersionInput) (*iam.CreatePolicyVersionOutput, error)
+	ListPolicyVersions(input *iam.ListPolicyVersionsInput) (*iam.ListPolicyVersionsOutput, error)
+	DeletePolicyVersion(input *iam.DeletePolicyVersionInput) (*iam.DeletePolicyVersionOutput, error)
 	GetUser(*iam.GetUserInput) (*iam.GetUserOutput, error)
 	GetUserPolicy(*iam.GetUserPolicyInput) (*iam.GetUserPolicyOutput, error)
 	ListAccessKeys(*iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error)
@@ -72,6 +79,11 @@ type Client interface {
 	ListObjects(input *s3.ListObjectsInput) (*s3.ListObjectsOutput, error)
 	GetObjectTagging(input *s3.GetObjectTaggingInput) (*s3.GetObjectTaggingOutput, error)
 	DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+	PutBucketVersioning(input *s3.PutBucketVersioningInput) (*s3.PutBucketVersioningOutput, error)
+	PutBucketReplication(input *s3.PutBucketReplicationInput) (*s3.PutBucketReplicationOutput, error)
+
+	//STS
+	GetFederationToken(*sts.GetFederationTokenInput) (*sts.GetFederationTokenOutput, error)
 }
 
 // ClientParams holds the various optional tunables that can be used to modify the AWS
@@ -86,6 +98,7 @@ type ClientParams struct {
 type awsClient struct {
 	iamClient iamiface.IAMAPI
 	s3Client  s3iface.S3API
+	stsClient stsiface.STSAPI
 }
 
 func (c *awsClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
@@ -131,10 +144,26 @@ func (c *awsClient) PutUserPolicy(input *iam.PutUserPolicyInput) (*iam.PutUserPo
 	return c.iamClient.PutUserPolicy(input)
 }
 
+func (c *awsClient) CreatePolicyVersion(input *iam.CreatePolicyVersionInput) (*iam.CreatePolicyVersionOutput, error) {
+	return c.iamClient.CreatePolicyVersion(input)
+}
+
+func (c *awsClient) ListPolicyVersions(input *iam.ListPolicyVersionsInput) (*iam.ListPolicyVersionsOutput, error) {
+	return c.iamClient.ListPolicyVersions(input)
+}
+
+func (c *awsClient) DeletePolicyVersion(input *iam.DeletePolicyVersionInput) (*iam.DeletePolicyVersionOutput, error) {
+	return c.iamClient.DeletePolicyVersion(input)
+}
+
 func (c *awsClient) GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
 	return c.iamClient.GetRole(input)
 }
 
+func (c *awsClient) GetRolePolicy(input *iam.GetRolePolicyInput) (*iam.GetRolePolicyOutput, error) {
+	return c.iamClient.GetRolePolicy(input)
+}
+
 func (c *awsClient) GetUserPolicy(input *iam.GetUserPolicyInput) (*iam.GetUserPolicyOutput, error) {
 	return c.iamClient.GetUserPolicy(input)
 }
@@ -223,6 +252,18 @@ func (c *awsClient) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectO
 	return c.s3Client.DeleteObject(input)
 }
 
+func (c *awsClient) PutBucketVersioning(input *s3.PutBucketVersioningInput) (*s3.PutBucketVersioningOutput, error) {
+	return c.s3Client.PutBucketVersioning(input)
+}
+
+func (c *awsClient) PutBucketReplication(input *s3.PutBucketReplicationInput) (*s3.PutBucketReplicationOutput, error) {
+	return c.s3Client.PutBucketReplication(input)
+}
+
+func (c *awsClient) GetFederationToken(input *sts.GetFederationTokenInput) (*sts.GetFederationTokenOutput, error) {
+	return c.stsClient.GetFederationToken(input)
+}
+
 // NewClient creates our client wrapper object for the actual AWS clients we use.
 func NewClient(accessKeyID, secretAccessKey []byte, params *ClientParams) (Client, error) {
 	var awsOpts session.Options
@@ -263,10 +304,53 @@ func NewClient(accessKeyID, secretAccessKey []byte, params *ClientParams) (Clien
 	return NewClientFromSession(s), nil
 }
 
+// NewClientFromWebIdentity creates our client wrapper object using the AWS STS
+// AssumeRoleWithWebIdentity credential provider, backed by the token at tokenFilePath, instead of
+// a long-lived access key pair. This lets callers operate using a projected service account
+// token (as used by IRSA and manual mode with STS) when no access key Secret is available.
+func NewClientFromWebIdentity(roleARN, tokenFilePath string, params *ClientParams) (Client, error) {
+	var awsOpts session.Options
+
+	agentText := "defaultAgent"
+
+	if params != nil {
+		if params.Region != "" {
+			awsOpts.Config.Region = aws.String(params.Region)
+		}
+
+		if params.Endpoint != "" {
+			awsOpts.Config.Endpoint = aws.String(params.Endpoint)
+		}
+
+		if params.CABundle != "" {
+			awsOpts.CustomCABundle = strings.NewReader(params.CABundle)
+		}
+
+		if params.InfraName != "" {
+			agentText = params.InfraName
+		}
+	}
+
+	s, err := session.NewSessionWithOptions(awsOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Config.Credentials = stscreds.NewWebIdentityCredentials(s, roleARN, "cloud-credential-operator", tokenFilePath)
+
+	s.Handlers.Build.PushBackNamed(request.NamedHandler{
+		Name: "openshift.io/cloud-credential-operator",
+		Fn:   request.MakeAddToUserAgentHandler("openshift.io cloud-credential-operator", version.Get().String(), agentText),
+	})
+
+	return NewClientFromSession(s), nil
+}
+
 // NewClientFromSession will return a basic Client using only the provided awsSession
 func NewClientFromSession(sess *session.Session) Client {
 	return &awsClient{
 		iamClient: iam.New(sess),
 		s3Client:  s3.New(sess),
+		stsClient: sts.New(sess),
 	}
 }
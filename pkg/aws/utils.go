@@ -146,9 +146,9 @@ func getClientDetails(awsClient Client) (*iam.User, bool, error) {
 // listed in the statementEntries. queryClient will need iam:GetUser and iam:SimulatePrincipalPolicy
 func CheckPermissionsUsingQueryClient(queryClient, targetClient Client, statementEntries []minterv1.StatementEntry,
 	params *SimulateParams, logger log.FieldLogger) (bool, error) {
-	targetUser, isRoot, err := getClientDetails(targetClient)
+	denied, isRoot, err := deniedActionsUsingQueryClient(queryClient, targetClient, statementEntries, params, logger)
 	if err != nil {
-		return false, fmt.Errorf("error gathering AWS credentials details: %v", err)
+		return false, err
 	}
 	if isRoot {
 		// warn about using the root creds, and just return that the creds are good enough
@@ -156,6 +156,28 @@ func CheckPermissionsUsingQueryClient(queryClient, targetClient Client, statemen
 		return true, nil
 	}
 
+	if len(denied) != 0 {
+		logger.Warningf("Tested creds not able to perform all requested actions")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// deniedActionsUsingQueryClient is the shared implementation behind CheckPermissionsUsingQueryClient
+// and DetectMode: it returns the subset of the actions listed in statementEntries that the
+// credentials in targetClient are not allowed to perform, and whether targetClient belongs to the
+// AWS account's root user (in which case permissions are never denied).
+func deniedActionsUsingQueryClient(queryClient, targetClient Client, statementEntries []minterv1.StatementEntry,
+	params *SimulateParams, logger log.FieldLogger) ([]string, bool, error) {
+	targetUser, isRoot, err := getClientDetails(targetClient)
+	if err != nil {
+		return nil, false, fmt.Errorf("error gathering AWS credentials details: %v", err)
+	}
+	if isRoot {
+		return nil, true, nil
+	}
+
 	allowList := []*string{}
 	for _, statement := range statementEntries {
 		for _, action := range statement.Action {
@@ -179,8 +201,7 @@ func CheckPermissionsUsingQueryClient(queryClient, targetClient Client, statemen
 		}
 	}
 
-	// Either all actions are allowed and we'll return 'true', or it's a failure
-	allClear := true
+	denied := []string{}
 
 	err = queryClient.SimulatePrincipalPolicyPages(input, func(response *iam.SimulatePolicyResponse, lastPage bool) bool {
 
@@ -189,22 +210,16 @@ func CheckPermissionsUsingQueryClient(queryClient, targetClient Client, statemen
 				// Don't bail out after the first failure, so we can log the full list
 				// of failed/denied actions
 				logger.WithField("action", *result.EvalActionName).Warning("Action not allowed with tested creds")
-				allClear = false
+				denied = append(denied, *result.EvalActionName)
 			}
 		}
 		return !lastPage
 	})
 	if err != nil {
-		return false, fmt.Errorf("error simulating policy: %v", err)
-	}
-
-	if !allClear {
-		logger.Warningf("Tested creds not able to perform all requested actions")
-		return false, nil
+		return nil, false, fmt.Errorf("error simulating policy: %v", err)
 	}
 
-	return true, nil
-
+	return denied, false, nil
 }
 
 // CheckPermissionsAgainstStatementList will test to see whether the list of actions in the provided
@@ -237,6 +252,66 @@ func CheckCloudCredPassthrough(awsClient Client, params *SimulateParams, logger
 	return CheckPermissionsAgainstActions(awsClient, credPassthroughActions, params, logger)
 }
 
+// ModeReport is the result of DetectMode: the CCO mode the tested credentials support, along
+// with the permissions that were found missing for any mode the credentials fell short of.
+type ModeReport struct {
+	// Mode is one of "mint", "passthrough" or "manual".
+	Mode string
+	// MissingMintActions are the mint-mode actions the credentials were denied, empty if Mode is "mint".
+	MissingMintActions []string
+	// MissingPassthroughActions are the passthrough-mode actions the credentials were denied,
+	// empty if Mode is "mint" or "passthrough".
+	MissingPassthroughActions []string
+}
+
+// DetectMode probes awsClient's permissions, using the same static action lists the operator's
+// secret annotator controller uses, and reports which CCO mode (mint, passthrough, or manual)
+// the credentials support, along with the reasoning (missing permissions) for any higher mode
+// they fell short of.
+func DetectMode(awsClient Client, params *SimulateParams, logger log.FieldLogger) (*ModeReport, error) {
+	missingMint, isRoot, err := deniedActionsUsingQueryClient(awsClient, awsClient, mintStatementList(), params, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed checking mint-mode permissions: %v", err)
+	}
+	if isRoot || len(missingMint) == 0 {
+		return &ModeReport{Mode: "mint"}, nil
+	}
+
+	missingPassthrough, _, err := deniedActionsUsingQueryClient(awsClient, awsClient, passthroughStatementList(), params, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed checking passthrough-mode permissions: %v", err)
+	}
+	if len(missingPassthrough) == 0 {
+		return &ModeReport{Mode: "passthrough", MissingMintActions: missingMint}, nil
+	}
+
+	return &ModeReport{
+		Mode:                      "manual",
+		MissingMintActions:        missingMint,
+		MissingPassthroughActions: missingPassthrough,
+	}, nil
+}
+
+func mintStatementList() []minterv1.StatementEntry {
+	return []minterv1.StatementEntry{
+		{
+			Action:   credMintingActions,
+			Resource: "*",
+			Effect:   "Allow",
+		},
+	}
+}
+
+func passthroughStatementList() []minterv1.StatementEntry {
+	return []minterv1.StatementEntry{
+		{
+			Action:   credPassthroughActions,
+			Resource: "*",
+			Effect:   "Allow",
+		},
+	}
+}
+
 func readCredentialRequest(cr []byte) (*minterv1.CredentialsRequest, error) {
 
 	newObj, err := runtime.Decode(credentialRequestCodec.UniversalDecoder(minterv1.SchemeGroupVersion), cr)
@@ -50,6 +50,13 @@ type Actuator struct {
 }
 
 func NewActuator(c client.Client, cloudName configv1.AzureCloudEnvironment) (*Actuator, error) {
+	return NewActuatorWithARMEndpoint(c, cloudName, "")
+}
+
+// NewActuatorWithARMEndpoint is like NewActuator, but additionally accepts the ARM endpoint
+// override published on the Infrastructure status for Azure Stack deployments, where cloudName
+// alone is not enough to resolve the cloud's endpoints.
+func NewActuatorWithARMEndpoint(c client.Client, cloudName configv1.AzureCloudEnvironment, armEndpoint string) (*Actuator, error) {
 	codec, err := minterv1.NewCodec()
 	if err != nil {
 		log.WithError(err).Error("error creating Azure codec")
@@ -61,6 +68,9 @@ func NewActuator(c client.Client, cloudName configv1.AzureCloudEnvironment) (*Ac
 		client: client,
 		codec:  codec,
 		credentialMinterBuilder: func(logger log.FieldLogger, clientID, clientSecret, tenantID, subscriptionID string) (*AzureCredentialsMinter, error) {
+			if cloudName == configv1.AzureStackCloud && armEndpoint != "" {
+				return NewAzureStackCredentialsMinter(logger, clientID, clientSecret, armEndpoint, tenantID, subscriptionID)
+			}
 			return NewAzureCredentialsMinter(logger, clientID, clientSecret, cloudName, tenantID, subscriptionID)
 		},
 	}, nil
@@ -1,5 +1,14 @@
 package azure
 
+// NOTE on exposing the Azure OIDC issuer behind Front Door / a private endpoint:
+// ccoctl has no "create-oidc-issuer" command for Azure today (unlike AWS's
+// create-identity-provider or GCP's create-workload-identity-provider) - Azure WIF clusters
+// currently get their issuer hosted and made reachable entirely outside of ccoctl, so this
+// package has nothing to hang a --use-front-door flag off of. Adding one would mean building the
+// base OIDC issuer hosting command first, plus vendoring an Azure storage and CDN/Front Door SDK
+// (only Azure/azure-sdk-for-go/services/graphrbac is vendored here, for AAD app/service principal
+// management), neither of which is in scope to fabricate for this change alone.
+
 const (
 	AzureClientID       = "azure_client_id"
 	AzureClientSecret   = "azure_client_secret"
@@ -2,7 +2,9 @@ package azure
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 
 	log "github.com/sirupsen/logrus"
 
@@ -13,6 +15,53 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 )
 
+// azureStackMetadataEndpoints is the subset of the Azure Resource Manager metadata endpoints
+// document (GET {armEndpoint}/metadata/endpoints?api-version=2019-05-01) needed to populate an
+// Environment for an Azure Stack deployment, which has no well-known name the SDK can resolve.
+type azureStackMetadataEndpoints struct {
+	GraphEndpoint  string `json:"graphEndpoint"`
+	GraphAudience  string `json:"graphAudience"`
+	Authentication struct {
+		LoginEndpoint string   `json:"loginEndpoint"`
+		Audiences     []string `json:"audiences"`
+	} `json:"authentication"`
+}
+
+// environmentFromARMEndpoint builds an Environment for an Azure Stack deployment by querying
+// its ARM metadata endpoint, since Azure Stack environments have no name the SDK recognizes.
+func environmentFromARMEndpoint(armEndpoint string) (azure.Environment, error) {
+	metadataURL := fmt.Sprintf("%s/metadata/endpoints?api-version=2019-05-01", armEndpoint)
+
+	resp, err := http.Get(metadataURL)
+	if err != nil {
+		return azure.Environment{}, fmt.Errorf("unable to query Azure Stack metadata endpoint %s: %w", metadataURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return azure.Environment{}, fmt.Errorf("unexpected status %d querying Azure Stack metadata endpoint %s", resp.StatusCode, metadataURL)
+	}
+
+	var metadata azureStackMetadataEndpoints
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return azure.Environment{}, fmt.Errorf("unable to decode Azure Stack metadata endpoint response: %w", err)
+	}
+
+	audience := metadata.GraphAudience
+	if audience == "" && len(metadata.Authentication.Audiences) > 0 {
+		audience = metadata.Authentication.Audiences[0]
+	}
+
+	return azure.Environment{
+		Name:                      "AzureStackCloud",
+		ResourceManagerEndpoint:   armEndpoint,
+		ActiveDirectoryEndpoint:   metadata.Authentication.LoginEndpoint,
+		GraphEndpoint:             metadata.GraphEndpoint,
+		ServiceManagementEndpoint: audience,
+		TokenAudience:             audience,
+	}, nil
+}
+
 func getAuthorizer(clientID, clientSecret, tenantID string, env azure.Environment, resourceEndpoint string) (autorest.Authorizer, error) {
 	config := auth.NewClientCredentialsConfig(clientID, clientSecret, tenantID)
 	config.Resource = resourceEndpoint
@@ -39,7 +88,24 @@ func NewFakeAzureCredentialsMinter(logger log.FieldLogger, clientID, clientSecre
 }
 
 func NewAzureCredentialsMinter(logger log.FieldLogger, clientID, clientSecret string, cloudName configv1.AzureCloudEnvironment, tenantID, subscriptionID string) (*AzureCredentialsMinter, error) {
-	env, err := azure.EnvironmentFromName(string(cloudName))
+	return newAzureCredentialsMinter(logger, clientID, clientSecret, cloudName, tenantID, subscriptionID, "")
+}
+
+// NewAzureStackCredentialsMinter is like NewAzureCredentialsMinter, but for Azure Stack
+// deployments, which have no well-known cloud name and instead must be resolved from the
+// cluster's ARM endpoint.
+func NewAzureStackCredentialsMinter(logger log.FieldLogger, clientID, clientSecret, armEndpoint, tenantID, subscriptionID string) (*AzureCredentialsMinter, error) {
+	return newAzureCredentialsMinter(logger, clientID, clientSecret, configv1.AzureStackCloud, tenantID, subscriptionID, armEndpoint)
+}
+
+func newAzureCredentialsMinter(logger log.FieldLogger, clientID, clientSecret string, cloudName configv1.AzureCloudEnvironment, tenantID, subscriptionID, armEndpoint string) (*AzureCredentialsMinter, error) {
+	var env azure.Environment
+	var err error
+	if cloudName == configv1.AzureStackCloud && armEndpoint != "" {
+		env, err = environmentFromARMEndpoint(armEndpoint)
+	} else {
+		env, err = azure.EnvironmentFromName(string(cloudName))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Unable to determine Azure environment: %w", err)
 	}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/cloud-credential-operator/pkg/azure"
+)
+
+func TestIsThrottlingError(t *testing.T) {
+	assert.True(t, azure.IsThrottlingError(errors.New("RequestDisallowedByPolicy: TooManyRequests")))
+	assert.False(t, azure.IsThrottlingError(errors.New("some other error")))
+	assert.False(t, azure.IsThrottlingError(nil))
+}
+
+func TestIsRoleAssignmentExistsError(t *testing.T) {
+	assert.True(t, azure.IsRoleAssignmentExistsError(errors.New("RoleAssignmentExists: the role assignment already exists")))
+	assert.False(t, azure.IsRoleAssignmentExistsError(errors.New("some other error")))
+	assert.False(t, azure.IsRoleAssignmentExistsError(nil))
+}
+
+func TestBatchCreateRoleAssignments(t *testing.T) {
+	tests := []struct {
+		name    string
+		create  func(calls *int32) azure.RoleAssignmentCreateFunc
+		wantErr bool
+	}{
+		{
+			name: "all succeed",
+			create: func(calls *int32) azure.RoleAssignmentCreateFunc {
+				return func(ctx context.Context, roleAssignmentID string) error {
+					atomic.AddInt32(calls, 1)
+					return nil
+				}
+			},
+		},
+		{
+			name: "already exists is treated as success",
+			create: func(calls *int32) azure.RoleAssignmentCreateFunc {
+				return func(ctx context.Context, roleAssignmentID string) error {
+					atomic.AddInt32(calls, 1)
+					return errors.New("RoleAssignmentExists: the role assignment already exists")
+				}
+			},
+		},
+		{
+			name: "non-throttling error is returned",
+			create: func(calls *int32) azure.RoleAssignmentCreateFunc {
+				return func(ctx context.Context, roleAssignmentID string) error {
+					atomic.AddInt32(calls, 1)
+					return errors.New("Forbidden: caller does not have permission")
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls int32
+			ids := []string{"ra1", "ra2", "ra3"}
+			err := azure.BatchCreateRoleAssignments(context.Background(), ids, test.create(&calls))
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.EqualValues(t, len(ids), calls, "expected create to be called once per role assignment ID")
+		})
+	}
+}
+
+func TestBatchCreateRoleAssignmentsRetriesThrottledCreates(t *testing.T) {
+	var mu sync.Mutex
+	attemptsByID := map[string]int{}
+
+	create := func(ctx context.Context, roleAssignmentID string) error {
+		mu.Lock()
+		attemptsByID[roleAssignmentID]++
+		attempt := attemptsByID[roleAssignmentID]
+		mu.Unlock()
+
+		if attempt < 2 {
+			return errors.New("TooManyRequests: throttled, try again later")
+		}
+		return nil
+	}
+
+	err := azure.BatchCreateRoleAssignments(context.Background(), []string{"ra1"}, create)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attemptsByID["ra1"], fmt.Sprintf("expected exactly one retry after throttling, got attempts: %v", attemptsByID))
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2019 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// roleDefinitionIDPattern matches an ARM role definition resource ID, e.g.
+// "/subscriptions/<sub-id>/providers/Microsoft.Authorization/roleDefinitions/<guid>". Role
+// assignments should reference a role definition this way instead of by (possibly ambiguous,
+// between built-in and custom) role name.
+var roleDefinitionIDPattern = regexp.MustCompile(`(?i)^/subscriptions/([^/]+)/providers/Microsoft\.Authorization/roleDefinitions/([0-9a-f-]{36})$`)
+
+// ParseRoleDefinitionID validates that roleDefinitionID is a well-formed ARM role definition
+// resource ID and extracts its subscription ID and role definition GUID.
+//
+// This only catches malformed IDs locally; it does not confirm the role definition actually
+// exists in the subscription, since that requires calling the Microsoft.Authorization
+// roleDefinitions API, which this build has no vendored client for.
+func ParseRoleDefinitionID(roleDefinitionID string) (subscriptionID, roleDefinitionGUID string, err error) {
+	matches := roleDefinitionIDPattern.FindStringSubmatch(roleDefinitionID)
+	if matches == nil {
+		return "", "", fmt.Errorf("%q is not a valid role definition ID, expected /subscriptions/<sub-id>/providers/Microsoft.Authorization/roleDefinitions/<guid>", roleDefinitionID)
+	}
+	return matches[1], matches[2], nil
+}
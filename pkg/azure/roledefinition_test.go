@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/cloud-credential-operator/pkg/azure"
+)
+
+func TestParseRoleDefinitionID(t *testing.T) {
+	cases := []struct {
+		name               string
+		roleDefinitionID   string
+		wantErr            bool
+		wantSubscriptionID string
+		wantGUID           string
+	}{
+		{
+			name:               "valid role definition ID",
+			roleDefinitionID:   "/subscriptions/11111111-1111-1111-1111-111111111111/providers/Microsoft.Authorization/roleDefinitions/22222222-2222-2222-2222-222222222222",
+			wantSubscriptionID: "11111111-1111-1111-1111-111111111111",
+			wantGUID:           "22222222-2222-2222-2222-222222222222",
+		},
+		{
+			name:             "bare role name instead of ID",
+			roleDefinitionID: "Contributor",
+			wantErr:          true,
+		},
+		{
+			name:             "missing guid",
+			roleDefinitionID: "/subscriptions/11111111-1111-1111-1111-111111111111/providers/Microsoft.Authorization/roleDefinitions/",
+			wantErr:          true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			subscriptionID, guid, err := azure.ParseRoleDefinitionID(tc.roleDefinitionID)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSubscriptionID, subscriptionID)
+			assert.Equal(t, tc.wantGUID, guid)
+		})
+	}
+}
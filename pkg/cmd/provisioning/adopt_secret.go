@@ -0,0 +1,102 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	minterv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+)
+
+type adoptSecretOptions struct {
+	CredentialsRequestFile string
+	Secret                 string
+	KubeConfigFile         string
+}
+
+var (
+	// AdoptSecretOpts captures the options that affect adopting an existing Secret into CCO management
+	AdoptSecretOpts = adoptSecretOptions{}
+)
+
+// NewAdoptSecretCmd provides the "adopt-secret" command, for bringing a hand-managed Secret under
+// CCO's management without recreating the underlying credential.
+func NewAdoptSecretCmd() *cobra.Command {
+	adoptSecretCmd := &cobra.Command{
+		Use:   "adopt-secret",
+		Short: "Bring an existing Secret under CCO management",
+		Long:  "Annotates an existing Secret as CCO-managed and links it to a CredentialsRequest, so future reconciles of that CredentialsRequest update the Secret in place instead of the admin having to recreate the credential under CCO from scratch.",
+		Run:   adoptSecretCmd,
+	}
+
+	adoptSecretCmd.PersistentFlags().StringVar(&AdoptSecretOpts.CredentialsRequestFile, "credentials-request", "", "Path to the CredentialsRequest manifest the Secret should be linked to")
+	adoptSecretCmd.MarkPersistentFlagRequired("credentials-request")
+	adoptSecretCmd.PersistentFlags().StringVar(&AdoptSecretOpts.Secret, "secret", "", "namespace/name of the existing Secret to adopt")
+	adoptSecretCmd.MarkPersistentFlagRequired("secret")
+	adoptSecretCmd.PersistentFlags().StringVar(&AdoptSecretOpts.KubeConfigFile, "kubeconfig", "", "Path to the kubeconfig file to use (defaults to the standard kubeconfig loading rules)")
+
+	return adoptSecretCmd
+}
+
+func adoptSecretCmd(cmd *cobra.Command, args []string) {
+	secretNamespace, secretName, err := parseNamespacedName(AdoptSecretOpts.Secret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cr, err := GetCredentialsRequestFromFile(AdoptSecretOpts.CredentialsRequestFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cr.Spec.SecretRef.Namespace != secretNamespace || cr.Spec.SecretRef.Name != secretName {
+		log.Fatalf("CredentialsRequest %s/%s targets secretRef %s/%s, not --secret %s/%s",
+			cr.Namespace, cr.Name, cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name, secretNamespace, secretName)
+	}
+
+	c, err := clusterClient(AdoptSecretOpts.KubeConfigFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, &minterv1.CredentialsRequest{}); err != nil {
+		log.Fatalf("CredentialsRequest %s/%s not found on the cluster; create it before adopting the Secret, so the operator's next reconcile has something to link the Secret to: %s", cr.Namespace, cr.Name, err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: secretNamespace, Name: secretName}, secret); err != nil {
+		log.Fatalf("failed to get Secret %s/%s: %s", secretNamespace, secretName, err)
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[minterv1.AnnotationCredentialsRequest] = fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)
+	managedKeys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		managedKeys = append(managedKeys, key)
+	}
+	secret.Annotations[minterv1.AnnotationManagedSecretKeys] = strings.Join(managedKeys, ",")
+
+	if err := c.Update(context.TODO(), secret); err != nil {
+		log.Fatalf("failed to update Secret %s/%s: %s", secretNamespace, secretName, err)
+	}
+
+	fmt.Printf("Secret %s/%s is now linked to CredentialsRequest %s/%s; the operator will update it in place on its next reconcile\n",
+		secretNamespace, secretName, cr.Namespace, cr.Name)
+}
+
+// parseNamespacedName splits a "namespace/name" string, as accepted by --secret.
+func parseNamespacedName(s string) (namespace, name string, err error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("%q is not of the form namespace/name", s)
+	}
+	return parts[0], parts[1], nil
+}
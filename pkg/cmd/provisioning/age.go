@@ -0,0 +1,47 @@
+package provisioning
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+)
+
+// ParseRotationAge parses a duration threshold for age-based rotation filtering, e.g. "30d" or
+// "72h". time.ParseDuration doesn't accept a "d" (day) unit, so a trailing "d" is special-cased
+// and converted to hours; anything else is delegated to time.ParseDuration.
+func ParseRotationAge(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %v", s, err)
+		}
+		return time.Duration(days * 24 * float64(time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// CredentialTooNewToRotate reports whether secret's stamped credreqv1.AnnotationCredentialsCreated
+// shows it was minted more recently than olderThan ago, so a --older-than rotation filter should
+// skip it, along with the credential's current age. A secret with no stamped creation time - the
+// age-stamping feature was never enabled, or olderThan is unset - is never considered too new,
+// since there's no age to compare against.
+func CredentialTooNewToRotate(secret *corev1.Secret, olderThan time.Duration) (tooNew bool, age time.Duration, err error) {
+	if olderThan <= 0 || secret == nil {
+		return false, 0, nil
+	}
+	stamp, ok := secret.Annotations[credreqv1.AnnotationCredentialsCreated]
+	if !ok {
+		return false, 0, nil
+	}
+	created, err := time.Parse(time.RFC3339, stamp)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid %s annotation %q on %s/%s: %v", credreqv1.AnnotationCredentialsCreated, stamp, secret.Namespace, secret.Name, err)
+	}
+	age = time.Since(created)
+	return age < olderThan, age, nil
+}
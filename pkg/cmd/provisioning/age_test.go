@@ -0,0 +1,126 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+)
+
+func TestParseRotationAge(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    time.Duration
+		expectError bool
+	}{
+		{
+			name:     "days",
+			input:    "30d",
+			expected: 30 * 24 * time.Hour,
+		},
+		{
+			name:     "fractional days",
+			input:    "1.5d",
+			expected: 36 * time.Hour,
+		},
+		{
+			name:     "hours via time.ParseDuration",
+			input:    "72h",
+			expected: 72 * time.Hour,
+		},
+		{
+			name:        "invalid days value",
+			input:       "xd",
+			expectError: true,
+		},
+		{
+			name:        "not a duration at all",
+			input:       "soon",
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseRotationAge(test.input)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestCredentialTooNewToRotate(t *testing.T) {
+	tests := []struct {
+		name         string
+		secret       *corev1.Secret
+		olderThan    time.Duration
+		expectTooNew bool
+		expectError  bool
+	}{
+		{
+			name:      "olderThan unset never skips",
+			secret:    stampedSecret(t, time.Now()),
+			olderThan: 0,
+		},
+		{
+			name:      "no stamp annotation never skips",
+			secret:    &corev1.Secret{},
+			olderThan: 24 * time.Hour,
+		},
+		{
+			name:         "created recently is too new",
+			secret:       stampedSecret(t, time.Now().Add(-time.Hour)),
+			olderThan:    24 * time.Hour,
+			expectTooNew: true,
+		},
+		{
+			name:      "created long ago is not too new",
+			secret:    stampedSecret(t, time.Now().Add(-48*time.Hour)),
+			olderThan: 24 * time.Hour,
+		},
+		{
+			name: "malformed stamp is an error",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{credreqv1.AnnotationCredentialsCreated: "not-a-timestamp"},
+				},
+			},
+			olderThan:   24 * time.Hour,
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tooNew, _, err := CredentialTooNewToRotate(test.secret, test.olderThan)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectTooNew, tooNew)
+		})
+	}
+}
+
+func stampedSecret(t *testing.T, created time.Time) *corev1.Secret {
+	t.Helper()
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				credreqv1.AnnotationCredentialsCreated: created.UTC().Format(time.RFC3339),
+			},
+		},
+	}
+}
@@ -68,7 +68,7 @@ func createRAMUsersCmd(cmd *cobra.Command, args []string) {
 //createRAMUsers will create a ram user for the given credenital request and attach the specific ram policy
 func createRAMUsers(client alibabacloud.Client, name, credReqDir, targetDir string, enableTechPreview bool) error {
 	// Process directory
-	credRequests, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview)
+	credRequests, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview, false)
 	if err != nil {
 		return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
 	}
@@ -342,7 +342,7 @@ func NewCreateRAMUsersCmd() *cobra.Command {
 
 	createRAMUsersCmd.PersistentFlags().StringVar(&CreateRAMUsersOpts.Name, "name", "", "User-defined name for all created Alibaba Cloud resources (can be separate from the cluster's infra-id)")
 	createRAMUsersCmd.MarkPersistentFlagRequired("name")
-	createRAMUsersCmd.PersistentFlags().StringVar(&CreateRAMUsersOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create RAM AK for (can be created by running 'oc adm release extract --credentials-requests --cloud=alibabacloud' against an OpenShift release image)")
+	createRAMUsersCmd.PersistentFlags().StringVar(&CreateRAMUsersOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create RAM AK for (can be created by running 'oc adm release extract --credentials-requests --cloud=alibabacloud' against an OpenShift release image). Accepts a comma-separated list of directories to merge")
 	createRAMUsersCmd.MarkPersistentFlagRequired("credentials-requests-dir")
 	createRAMUsersCmd.PersistentFlags().StringVar(&CreateRAMUsersOpts.Region, "region", "", "Alibaba Cloud region endpoint only required for GovCloud")
 	createRAMUsersCmd.PersistentFlags().StringVar(&CreateRAMUsersOpts.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
@@ -10,15 +12,58 @@ import (
 )
 
 type options struct {
-	TargetDir              string
-	PublicKeyPath          string
-	Region                 string
-	Name                   string
-	CredRequestDir         string
-	IdentityProviderARN    string
-	PermissionsBoundaryARN string
-	DryRun                 bool
-	EnableTechPreview      bool
+	TargetDir                    string
+	PublicKeyPath                string
+	Region                       string
+	Name                         string
+	CredRequestDir               string
+	IdentityProviderARN          string
+	PermissionsBoundaryARN       string
+	DryRun                       bool
+	EnableTechPreview            bool
+	SealCertPath                 string
+	SealScope                    string
+	AuditLogPath                 string
+	Only                         []string
+	FromCluster                  bool
+	KubeConfigFile               string
+	Namespace                    string
+	LabelSelector                string
+	ExternalSecretsBackend       string
+	ExternalSecretsBackendConfig string
+	Timeout                      time.Duration
+	Force                        bool
+	EmitRBAC                     bool
+	ContinueOnError              bool
+	OutputTar                    string
+	OIDCBucketReplicaRegion      string
+	PreviewNames                 bool
+	Profile                      string
+	OIDCAudience                 string
+	ReadOnly                     bool
+	ReadOnlyDenylist             []string
+	ClusterNames                 []string
+	ResourceTypes                []string
+	OtelEndpoint                 string
+	OIDCSigningKMSKeyARN         string
+	Exclude                      []string
+	ImmutableSecrets             bool
+	OutputFormat                 string
+	OIDCThumbprint               string
+	SkipThumbprintValidation     bool
+	ValuesTemplate               string
+	RegionMap                    []string
+	Yes                          bool
+	Report                       string
+	NoSecret                     bool
+	IAMPath                      string
+	BundleOutput                 string
+	StrictSubject                bool
+	OutputLayout                 string
+	AccountMap                   []string
+	SopsConfig                   string
+	EmitPoliciesDir              string
+	SecretType                   string
 }
 
 // NewAWSCmd implements the "aws" subcommand for the credentials provisioning
@@ -34,17 +79,25 @@ func NewAWSCmd() *cobra.Command {
 	createCmd.AddCommand(NewCreateIAMRolesCmd())
 	createCmd.AddCommand(NewCreateAllCmd())
 	createCmd.AddCommand(NewDeleteCmd())
+	createCmd.AddCommand(NewDetectModeCmd())
+	createCmd.AddCommand(NewDiagnoseCmd())
+	createCmd.AddCommand(NewValidateCmd())
+	createCmd.AddCommand(NewDiffCmd())
 
 	return createCmd
 }
 
-func awsSession(region string) (*session.Session, error) {
+// awsSession builds an AWS session for the given region, optionally pinning it to a named
+// profile from the shared AWS config/credentials files instead of relying on the default
+// credential chain (which silently picks up whatever profile/env vars happen to be present).
+func awsSession(region, profile string) (*session.Session, error) {
 	cfg := awssdk.Config{
 		Region: awssdk.String(region),
 	}
 
 	return session.NewSessionWithOptions(session.Options{
 		Config:            cfg,
+		Profile:           profile,
 		SharedConfigState: session.SharedConfigEnable,
 	})
 }
@@ -1,23 +1,34 @@
 package aws
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	"github.com/openshift/cloud-credential-operator/pkg/aws"
 	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning/trace"
 )
 
 const (
@@ -33,13 +44,51 @@ kind: Secret
 metadata:
   name: %s
   namespace: %s
+  annotations:
+    cloudcredential.openshift.io/managed-secret-keys: credentials
 type: Opaque`
 
 	// Generated role files
 	roleFilenameFormat       = "05-%d-%s-role.json"
 	rolePolicyFilenameFormat = "06-%d-%s-policy.json"
+	// emitPoliciesFilenameFormat names the file --emit-policies-dir writes per CredentialsRequest
+	emitPoliciesFilenameFormat = "%s-%s-policies.json"
 	// fileModeCcoctlDryRun represents a mode and permission bits of the files created by ccoctl in dry run
 	fileModeCcoctlDryRun = 0644
+
+	// outputFormatSecret is the default --output-format: a core/v1 Secret manifest.
+	outputFormatSecret = "secret"
+	// outputFormatDotenv writes a .env file of KEY=value pairs instead of a Secret manifest, for
+	// bootstrapping phases that consume credentials before the cluster (and thus a place to apply
+	// a Secret) exists.
+	outputFormatDotenv = "dotenv"
+	// outputFormatHelmValues writes a values.yaml fragment referencing the generated Secret's
+	// name/namespace instead of a Secret manifest, for downstream Helm charts that expect
+	// credential references injected as values rather than applied as a separate manifest.
+	outputFormatHelmValues = "helm-values"
+
+	// outputLayoutNamespaceName is the default --output-layout: the generated Secret/SealedSecret/
+	// ExternalSecret manifest is named after the target Secret's namespace and name
+	// (<namespace>-<name>-credentials.yaml), ccoctl's traditional internal naming scheme.
+	outputLayoutNamespaceName = "namespace-name"
+	// outputLayoutPerCredReqName names the generated manifest after its source CredentialsRequest's
+	// metadata.name instead (<credreq-name>.yaml), so GitOps repositories that expect manifests to
+	// map 1:1 onto CredentialsRequest names (e.g. "secrets/<credreq-name>.yaml") get a predictable
+	// file layout instead of ccoctl's internal namespace/name scheme.
+	outputLayoutPerCredReqName = "per-credreq-name"
+
+	// outputFormatSops writes the generated Secret manifest encrypted as a SOPS document via
+	// provisioning.EncryptWithSops, for GitOps repositories that commit credentials encrypted
+	// against age/KMS/PGP recipients instead of sealing them against a controller certificate.
+	outputFormatSops = "sops"
+
+	// defaultHelmValuesTemplate is used for --output-format=helm-values when --values-template is
+	// not set: it emits a minimal fragment under a credentialsSecrets map, keyed by CredentialsRequest name.
+	defaultHelmValuesTemplate = `credentialsSecrets:
+  {{ .CredentialsRequestName }}:
+    namespace: {{ .Namespace }}
+    secretName: {{ .SecretName }}
+`
 )
 
 var (
@@ -48,43 +97,264 @@ var (
 	CreateIAMRolesOpts = options{
 		TargetDir:         "",
 		EnableTechPreview: false,
+		OutputFormat:      outputFormatSecret,
+		OutputLayout:      outputLayoutNamespaceName,
 	}
+
+	// iamPathRegexp matches AWS IAM's path grammar: a leading and trailing "/" around zero or more
+	// "/"-separated segments of printable ASCII excluding backslash, per the IAM CreateRole API's
+	// Path parameter pattern.
+	iamPathRegexp = regexp.MustCompile(`^(/[\x21-\x5B\x5D-\x7E]+)*/$`)
+
+	// iamRoleARNRegexp matches an IAM role ARN, e.g. arn:aws:iam::123456789012:role/OrganizationAccountAccessRole,
+	// as used by --account-map to identify the role to assume in a target AWS account.
+	iamRoleARNRegexp = regexp.MustCompile(`^arn:aws[a-zA-Z-]*:iam::\d{12}:role/.+$`)
 )
 
-func createIAMRoles(client aws.Client, identityProviderARN, PermissionsBoundaryARN, name, credReqDir, targetDir string, enableTechPreview, generateOnly bool) error {
-	// Process directory
-	credRequests, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview)
+func createIAMRoles(client aws.Client, identityProviderARN, PermissionsBoundaryARN, iamPath, name, credReqDir, targetDir string, enableTechPreview, generateOnly bool, regionMap, accountMap map[string]string) error {
+	var credRequests []*credreqv1.CredentialsRequest
+	var err error
+	if CreateIAMRolesOpts.FromCluster {
+		credRequests, err = provisioning.GetListOfCredentialsRequestsFromCluster(CreateIAMRolesOpts.KubeConfigFile, CreateIAMRolesOpts.Namespace, CreateIAMRolesOpts.LabelSelector, enableTechPreview)
+		if err != nil {
+			return errors.Wrap(err, "Failed to list CredentialsRequests from cluster")
+		}
+	} else {
+		credRequests, err = provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview, true)
+		if err != nil {
+			return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
+		}
+	}
+
+	credRequests, err = provisioning.FilterCredentialsRequestsByName(credRequests, CreateIAMRolesOpts.Only)
 	if err != nil {
-		return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
+		return errors.Wrap(err, "Failed to filter CredentialsRequests by --only")
+	}
+
+	credRequests, err = provisioning.ExcludeCredentialsRequestsByName(credRequests, CreateIAMRolesOpts.Exclude)
+	if err != nil {
+		return errors.Wrap(err, "Failed to filter CredentialsRequests by --exclude")
+	}
+
+	clusterNames := CreateIAMRolesOpts.ClusterNames
+	if len(clusterNames) == 0 {
+		// Create IAM Roles (with policies)
+		if err := processCredentialsRequests(client, credRequests, identityProviderARN, PermissionsBoundaryARN, iamPath, name, targetDir, generateOnly, CreateIAMRolesOpts.SealCertPath, CreateIAMRolesOpts.SealScope, CreateIAMRolesOpts.ExternalSecretsBackend, CreateIAMRolesOpts.ExternalSecretsBackendConfig, regionMap, accountMap); err != nil {
+			return errors.Wrap(err, "Failed while processing each CredentialsRequest")
+		}
+		return nil
 	}
 
-	// Create IAM Roles (with policies)
-	if err := processCredentialsRequests(client, credRequests, identityProviderARN, PermissionsBoundaryARN, name, targetDir, generateOnly); err != nil {
-		return errors.Wrap(err, "Failed while processing each CredentialsRequest")
+	// --cluster-names reuses this one parse of the CredentialsRequests to stamp out a distinct
+	// Secret/role set per cluster name, each under its own output subdirectory, so a managed-service
+	// operator provisioning a fleet from one CredentialsRequest template set doesn't need N separate
+	// invocations.
+	for _, clusterName := range clusterNames {
+		clusterTargetDir := filepath.Join(targetDir, clusterName)
+		if err := ensureManifestsDir(clusterTargetDir); err != nil {
+			return errors.Wrapf(err, "Failed to prepare output directory for cluster %q", clusterName)
+		}
+		clusterRoleName := fmt.Sprintf("%s-%s", name, clusterName)
+		if err := processCredentialsRequests(client, credRequests, identityProviderARN, PermissionsBoundaryARN, iamPath, clusterRoleName, clusterTargetDir, generateOnly, CreateIAMRolesOpts.SealCertPath, CreateIAMRolesOpts.SealScope, CreateIAMRolesOpts.ExternalSecretsBackend, CreateIAMRolesOpts.ExternalSecretsBackendConfig, regionMap, accountMap); err != nil {
+			return errors.Wrapf(err, "Failed while processing CredentialsRequests for cluster %q", clusterName)
+		}
 	}
 
 	return nil
 }
 
-func processCredentialsRequests(awsClient aws.Client, credReqs []*credreqv1.CredentialsRequest, identityProviderARN, PermissionsBoundaryARN, name, targetDir string, generateOnly bool) error {
+// ensureManifestsDir creates targetDir and its manifests subdirectory if they do not already exist,
+// mirroring the layout initEnvForCreateIAMRolesCmd sets up for the default (single-cluster) output dir.
+func ensureManifestsDir(targetDir string) error {
+	if err := provisioning.EnsureDir(targetDir); err != nil {
+		return err
+	}
+	return provisioning.EnsureDir(filepath.Join(targetDir, provisioning.ManifestsDirName))
+}
+
+// ReportEntry links one CredentialsRequest to the concrete AWS resource and target Secret
+// createRole produced for it, for the --report output consumed by auditors and by a future
+// --report-driven delete.
+type ReportEntry struct {
+	CredentialsRequest string `json:"credentialsRequest"`
+	RoleARN            string `json:"roleARN"`
+	SecretNamespace    string `json:"secretNamespace"`
+	SecretName         string `json:"secretName"`
+}
+
+// writeReport serializes entries to path as JSON, or as CSV if path ends in ".csv".
+func writeReport(path string, entries []ReportEntry) error {
+	if strings.HasSuffix(strings.ToLower(path), ".csv") {
+		f, err := os.Create(path)
+		if err != nil {
+			return errors.Wrap(err, "Failed to create --report file")
+		}
+		defer f.Close()
+
+		w := csv.NewWriter(f)
+		if err := w.Write([]string{"credentialsRequest", "roleARN", "secretNamespace", "secretName"}); err != nil {
+			return errors.Wrap(err, "Failed to write --report CSV header")
+		}
+		for _, entry := range entries {
+			if err := w.Write([]string{entry.CredentialsRequest, entry.RoleARN, entry.SecretNamespace, entry.SecretName}); err != nil {
+				return errors.Wrap(err, "Failed to write --report CSV row")
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return errors.Wrap(err, "Failed to flush --report CSV")
+		}
+		log.Printf("Saved CredentialsRequest-to-resource report to: %s", path)
+		return nil
+	}
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal --report to JSON")
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return errors.Wrap(err, "Failed to write --report file")
+	}
+	log.Printf("Saved CredentialsRequest-to-resource report to: %s", path)
+	return nil
+}
+
+func processCredentialsRequests(awsClient aws.Client, credReqs []*credreqv1.CredentialsRequest, identityProviderARN, PermissionsBoundaryARN, iamPath, name, targetDir string, generateOnly bool, sealCertPath, sealScope, externalSecretsBackend, externalSecretsBackendConfig string, regionMap, accountMap map[string]string) error {
 
 	issuerURL, err := getIssuerURLFromIdentityProvider(awsClient, identityProviderARN)
 	if err != nil {
 		return err
 	}
 
+	checksums, err := provisioning.LoadChecksumStore(targetDir)
+	if err != nil {
+		return errors.Wrap(err, "Failed to load checksum metadata")
+	}
+
+	tracer := trace.NewTracer(CreateIAMRolesOpts.OtelEndpoint)
+
+	// regionClients caches one client per non-default region named in regionMap, so a fleet with
+	// many CredentialsRequests pinned to the same region (e.g. all of GovCloud) doesn't open a new
+	// AWS session per CredentialsRequest.
+	regionClients := map[string]aws.Client{}
+
+	// accountClients caches one client per distinct IAM role ARN named in accountMap, so multiple
+	// CredentialsRequests targeting the same member account share a single assumed-role session.
+	accountClients := map[string]aws.Client{}
+
+	var report []ReportEntry
+
+	// usedOutputFiles tracks, for this run, which CredentialsRequest claimed each generated
+	// manifest file path, so a naming collision under --output-layout=per-credreq-name (or any
+	// future layout) is caught and reported instead of one CredentialsRequest's file silently
+	// clobbering another's.
+	usedOutputFiles := map[string]string{}
+
+	skipped := 0
 	for i, cr := range credReqs {
 		// infraName-targetNamespace-targetSecretName
-		_, err = createRole(awsClient, name, cr, i, identityProviderARN, issuerURL, PermissionsBoundaryARN, targetDir, generateOnly)
+		crJSON, err := json.Marshal(cr)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal CredentialsRequest %s for checksumming", cr.Name)
+		}
+		checksum := provisioning.ComputeChecksum(string(crJSON), identityProviderARN, PermissionsBoundaryARN, iamPath, name, sealCertPath, sealScope, externalSecretsBackend, externalSecretsBackendConfig)
+
+		if !CreateIAMRolesOpts.Force && checksums.UpToDate(cr.Name, checksum) {
+			log.Printf("CredentialsRequest %s unchanged since last run, skipping (use --force to regenerate)", cr.Name)
+			skipped++
+			continue
+		}
+
+		crClient := awsClient
+		region := CreateIAMRolesOpts.Region
+		if mappedRegion, ok := regionMap[cr.Name]; ok {
+			region = mappedRegion
+			regionClient, err := regionClientFor(regionClients, region)
+			if err != nil {
+				return errors.Wrapf(err, "failed to build AWS client for CredentialsRequest %s's --region-map region %q", cr.Name, region)
+			}
+			crClient = regionClient
+		}
+		if roleARN, ok := accountMap[cr.Name]; ok {
+			accountClient, err := accountClientFor(accountClients, roleARN, region)
+			if err != nil {
+				return errors.Wrapf(err, "failed to build AWS client for CredentialsRequest %s's --account-map role %q", cr.Name, roleARN)
+			}
+			crClient = accountClient
+		}
+
+		span := tracer.StartSpan(fmt.Sprintf("ProcessCredentialsRequest:%s", cr.Name))
+
+		var roleARN string
+		i, cr := i, cr
+		err = provisioning.RunWithTimeout(CreateIAMRolesOpts.Timeout, func() error {
+			var err error
+			roleARN, err = createRole(crClient, name, cr, i, identityProviderARN, issuerURL, PermissionsBoundaryARN, iamPath, targetDir, generateOnly, sealCertPath, sealScope, externalSecretsBackend, externalSecretsBackendConfig, usedOutputFiles)
+			return err
+		})
+		span.SetError(err)
+		span.End()
 		if err != nil {
 			return err
 		}
 
+		if CreateIAMRolesOpts.Report != "" {
+			report = append(report, ReportEntry{
+				CredentialsRequest: cr.Name,
+				RoleARN:            roleARN,
+				SecretNamespace:    cr.Spec.SecretRef.Namespace,
+				SecretName:         cr.Spec.SecretRef.Name,
+			})
+		}
+
+		checksums[cr.Name] = checksum
+	}
+
+	if skipped > 0 {
+		log.Printf("Skipped %d unchanged CredentialsRequest(s) out of %d", skipped, len(credReqs))
 	}
+
+	if CreateIAMRolesOpts.Report != "" {
+		if err := writeReport(CreateIAMRolesOpts.Report, report); err != nil {
+			return err
+		}
+	}
+
+	if err := checksums.Save(targetDir); err != nil {
+		return errors.Wrap(err, "Failed to save checksum metadata")
+	}
+
 	return nil
 }
 
-func createRole(awsClient aws.Client, name string, credReq *credreqv1.CredentialsRequest, roleNum int, oidcProviderARN, issuerURL, PermissionsBoundaryARN, targetDir string, generateOnly bool) (string, error) {
+// previewIAMRoleNames prints the IAM role name that would be generated for each CredentialsRequest
+// in credReqDir, without making any AWS API calls. This lets a user check the effect of AWS's
+// naming constraints (allowed characters, 64 character limit) on their chosen --name before running
+// create-iam-roles or create-all for real.
+func previewIAMRoleNames(name, credReqDir string, enableTechPreview bool) error {
+	credRequests, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview, true)
+	if err != nil {
+		return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
+	}
+
+	for _, credReq := range credRequests {
+		roleName := fmt.Sprintf("%s-%s-%s", name, credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name)
+		sanitized, changed, err := provisioning.SanitizeResourceName("aws", "iam-role", roleName)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			fmt.Printf("%s: %s (sanitized from %s)\n", credReq.Name, sanitized, roleName)
+		} else {
+			fmt.Printf("%s: %s\n", credReq.Name, sanitized)
+		}
+	}
+
+	return nil
+}
+
+func createRole(awsClient aws.Client, name string, credReq *credreqv1.CredentialsRequest, roleNum int, oidcProviderARN, issuerURL, PermissionsBoundaryARN, iamPath, targetDir string, generateOnly bool, sealCertPath, sealScope, externalSecretsBackend, externalSecretsBackendConfig string, usedOutputFiles map[string]string) (string, error) {
 	roleName := fmt.Sprintf("%s-%s-%s", name, credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name)
 
 	// Decode AWSProviderSpec
@@ -102,15 +372,13 @@ func createRole(awsClient aws.Client, name string, credReq *credreqv1.Credential
 		return "", fmt.Errorf("CredentialsRequest %s/%s is not of type AWS", credReq.Namespace, credReq.Name)
 	}
 
-	// Ensure role name is no longer than 64 charactters
-	var shortenedRoleName string
-	if len(roleName) > 64 {
-		shortenedRoleName = roleName[0:64]
-	} else {
-		shortenedRoleName = roleName
+	// Ensure role name satisfies AWS IAM's naming constraints (allowed characters, 64 character limit)
+	shortenedRoleName, _, err := provisioning.SanitizeResourceName("aws", "iam-role", roleName)
+	if err != nil {
+		return "", err
 	}
 
-	rolePolicyDocument, err := createRolePolicyDocument(oidcProviderARN, issuerURL, credReq.Spec.SecretRef.Namespace, credReq.Spec.ServiceAccountNames)
+	rolePolicyDocument, err := createRolePolicyDocument(oidcProviderARN, issuerURL, credReq.Spec.SecretRef.Namespace, credReq.Spec.ServiceAccountNames, CreateIAMRolesOpts.StrictSubject)
 	if err != nil {
 		return "", errors.Wrapf(err, "error while creating Role policy document for %s", credReq.Name)
 	}
@@ -119,6 +387,12 @@ func createRole(awsClient aws.Client, name string, credReq *credreqv1.Credential
 
 	rolePolicy := createRolePolicy(awsProviderSpec.StatementEntries)
 
+	if CreateIAMRolesOpts.EmitPoliciesDir != "" {
+		if err := emitPoliciesForReview(credReq, shortenedRoleName, rolePolicyDocument, rolePolicy, CreateIAMRolesOpts.EmitPoliciesDir); err != nil {
+			return "", errors.Wrapf(err, "error while emitting policies for review for %s", credReq.Name)
+		}
+	}
+
 	switch generateOnly {
 	case true:
 		// Generate Role
@@ -141,6 +415,9 @@ func createRole(awsClient aws.Client, name string, credReq *credreqv1.Credential
 		if PermissionsBoundaryARN != "" {
 			roleTemplate["PermissionsBoundary"] = PermissionsBoundaryARN
 		}
+		if iamPath != "" {
+			roleTemplate["Path"] = iamPath
+		}
 		roleJSON, err := json.Marshal(&roleTemplate)
 		if err != nil {
 			return "", errors.Wrap(err, "failed to convert Role to JSON")
@@ -170,17 +447,24 @@ func createRole(awsClient aws.Client, name string, credReq *credreqv1.Credential
 			return "", errors.Wrap(err, fmt.Sprintf("Failed to save policy for %s locally at %s", roleDescription, rolePolicyFullPath))
 		}
 
-		if err := writeCredReqSecret(credReq, targetDir, ""); err != nil {
-			return "", errors.Wrap(err, "failed to save Secret for install manifests")
+		if !CreateIAMRolesOpts.NoSecret {
+			if err := writeCredReqSecret(credReq, targetDir, "", sealCertPath, sealScope, externalSecretsBackend, externalSecretsBackendConfig, CreateIAMRolesOpts.EmitRBAC, CreateIAMRolesOpts.ImmutableSecrets, CreateIAMRolesOpts.OutputFormat, CreateIAMRolesOpts.OutputLayout, CreateIAMRolesOpts.ValuesTemplate, CreateIAMRolesOpts.SopsConfig, awsProviderSpec.AdditionalSecretData, usedOutputFiles, CreateIAMRolesOpts.SecretType); err != nil {
+				return "", errors.Wrap(err, "failed to save Secret for install manifests")
+			}
 		}
 
 		return "", nil
 
 	default:
+		tracer := trace.NewTracer(CreateIAMRolesOpts.OtelEndpoint)
+
 		var role *iam.Role
+		getRoleSpan := tracer.StartSpan("aws:iam:GetRole")
 		outRole, err := awsClient.GetRole(&iam.GetRoleInput{
 			RoleName: awssdk.String(shortenedRoleName),
 		})
+		getRoleSpan.SetError(err)
+		getRoleSpan.End()
 
 		if err != nil {
 			var aerr awserr.Error
@@ -206,7 +490,14 @@ func createRole(awsClient aws.Client, name string, credReq *credreqv1.Credential
 					if PermissionsBoundaryARN != "" {
 						roleInput.PermissionsBoundary = awssdk.String(PermissionsBoundaryARN)
 					}
+					if iamPath != "" {
+						roleInput.Path = awssdk.String(iamPath)
+					}
+					createRoleSpan := tracer.StartSpan("aws:iam:CreateRole")
 					roleOutput, err := awsClient.CreateRole(roleInput)
+					createRoleSpan.SetError(err)
+					createRoleSpan.End()
+					provisioning.AuditLogCall("iam:CreateRole", shortenedRoleName, err)
 					if err != nil {
 						return "", errors.Wrap(err, "Failed to create role")
 					}
@@ -214,8 +505,10 @@ func createRole(awsClient aws.Client, name string, credReq *credreqv1.Credential
 					role = roleOutput.Role
 					log.Printf("Role %s created", *role.Arn)
 
-					if err := writeCredReqSecret(credReq, targetDir, *role.Arn); err != nil {
-						return "", errors.Wrap(err, "failed to save Secret for install manifests")
+					if !CreateIAMRolesOpts.NoSecret {
+						if err := writeCredReqSecret(credReq, targetDir, *role.Arn, sealCertPath, sealScope, externalSecretsBackend, externalSecretsBackendConfig, CreateIAMRolesOpts.EmitRBAC, CreateIAMRolesOpts.ImmutableSecrets, CreateIAMRolesOpts.OutputFormat, CreateIAMRolesOpts.OutputLayout, CreateIAMRolesOpts.ValuesTemplate, CreateIAMRolesOpts.SopsConfig, awsProviderSpec.AdditionalSecretData, usedOutputFiles, CreateIAMRolesOpts.SecretType); err != nil {
+							return "", errors.Wrap(err, "failed to save Secret for install manifests")
+						}
 					}
 
 				default:
@@ -228,11 +521,15 @@ func createRole(awsClient aws.Client, name string, credReq *credreqv1.Credential
 			log.Printf("Existing role %s found", *role.Arn)
 		}
 
+		putRolePolicySpan := tracer.StartSpan("aws:iam:PutRolePolicy")
 		_, err = awsClient.PutRolePolicy(&iam.PutRolePolicyInput{
 			PolicyName:     awssdk.String(shortenedRoleName),
 			RoleName:       role.RoleName,
 			PolicyDocument: awssdk.String(rolePolicy),
 		})
+		putRolePolicySpan.SetError(err)
+		putRolePolicySpan.End()
+		provisioning.AuditLogCall("iam:PutRolePolicy", shortenedRoleName, err)
 		if err != nil {
 			return "", errors.Wrap(err, "Failed to put role policy")
 		}
@@ -242,7 +539,84 @@ func createRole(awsClient aws.Client, name string, credReq *credreqv1.Credential
 	}
 }
 
-func createRolePolicyDocument(oidcProviderARN, issuerURL, namespace string, serviceAccountNames []string) (string, error) {
+// emitPoliciesForReview writes the trust policy and permissions policy that would be attached to
+// credReq's IAM role to a single JSON file under policiesDir, for a security team to review
+// before anything is created in AWS. It runs regardless of --dry-run, so it can pair with a normal
+// (non-dry-run) invocation to produce an audit trail alongside the roles actually created.
+func emitPoliciesForReview(credReq *credreqv1.CredentialsRequest, roleName, trustPolicyDocument, permissionsPolicyDocument, policiesDir string) error {
+	policies := map[string]interface{}{
+		"RoleName":                 roleName,
+		"AssumeRolePolicyDocument": json.RawMessage(trustPolicyDocument),
+		"PolicyDocument":           json.RawMessage(permissionsPolicyDocument),
+	}
+	policiesJSON, err := json.MarshalIndent(&policies, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to convert policies to JSON")
+	}
+
+	policiesFilename := fmt.Sprintf(emitPoliciesFilenameFormat, credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name)
+	policiesFullPath := filepath.Join(policiesDir, policiesFilename)
+	log.Printf("Saving policies for %s/%s locally at %s for review", credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name, policiesFullPath)
+	return ioutil.WriteFile(policiesFullPath, policiesJSON, fileModeCcoctlDryRun)
+}
+
+// maxManagedPolicyVersions is AWS IAM's hard cap on the number of versions a customer-managed
+// policy may retain at once; CreatePolicyVersion returns LimitExceeded once this is reached and a
+// non-default version must be deleted to make room for a new one.
+const maxManagedPolicyVersions = 5
+
+// pruneOldPolicyVersions deletes the oldest non-default versions of a customer-managed policy so
+// that a subsequent CreatePolicyVersion call has room within maxManagedPolicyVersions. ccoctl does
+// not currently create or update customer-managed policies anywhere - create-iam-roles updates
+// roles exclusively via PutRolePolicy, which replaces an unversioned inline policy document and so
+// never hits this limit - so this has no call site yet. It is kept here, alongside the role-policy
+// update path it would otherwise duplicate, so that any future customer-managed-policy support has
+// a ready-made, tested pruning step rather than re-discovering the LimitExceeded failure mode.
+func pruneOldPolicyVersions(awsClient aws.Client, policyARN string) error {
+	out, err := awsClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{
+		PolicyArn: awssdk.String(policyARN),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list policy versions")
+	}
+
+	if len(out.Versions) < maxManagedPolicyVersions {
+		return nil
+	}
+
+	versions := make([]*iam.PolicyVersion, 0, len(out.Versions))
+	for _, version := range out.Versions {
+		if version.IsDefaultVersion != nil && *version.IsDefaultVersion {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].CreateDate.Before(*versions[j].CreateDate)
+	})
+
+	// Leave room for the version CreatePolicyVersion is about to create.
+	numToPrune := len(out.Versions) - maxManagedPolicyVersions + 1
+	for i := 0; i < numToPrune && i < len(versions); i++ {
+		_, err := awsClient.DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+			PolicyArn: awssdk.String(policyARN),
+			VersionId: versions[i].VersionId,
+		})
+		provisioning.AuditLogCall("iam:DeletePolicyVersion", *versions[i].VersionId, err)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to prune policy version %s", *versions[i].VersionId)
+		}
+		log.Printf("Pruned old policy version %s for %s", *versions[i].VersionId, policyARN)
+	}
+
+	return nil
+}
+
+func createRolePolicyDocument(oidcProviderARN, issuerURL, namespace string, serviceAccountNames []string, strictSubject bool) (string, error) {
+	if strictSubject && (namespace == "" || len(serviceAccountNames) != 1) {
+		return "", fmt.Errorf("--strict-subject requires exactly one ServiceAccount name and a non-empty namespace to build a single concrete subject, got namespace %q and ServiceAccountNames %v", namespace, serviceAccountNames)
+	}
+
 	var conditionString string
 	if len(serviceAccountNames) > 0 {
 		var serviceAccountListString string
@@ -270,9 +644,12 @@ func createRolePolicyDocument(oidcProviderARN, issuerURL, namespace string, serv
 }
 
 func getIssuerURLFromIdentityProvider(awsClient aws.Client, idProviderARN string) (string, error) {
+	span := trace.NewTracer(CreateIAMRolesOpts.OtelEndpoint).StartSpan("aws:iam:GetOpenIDConnectProvider")
 	idProvider, err := awsClient.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
 		OpenIDConnectProviderArn: awssdk.String(idProviderARN),
 	})
+	span.SetError(err)
+	span.End()
 
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get IAM Identity Provider")
@@ -282,20 +659,137 @@ func getIssuerURLFromIdentityProvider(awsClient aws.Client, idProviderARN string
 }
 
 func createIAMRolesCmd(cmd *cobra.Command, args []string) {
-	s, err := awsSession(CreateIAMRolesOpts.Region)
+	if CreateIAMRolesOpts.PreviewNames {
+		if err := previewIAMRoleNames(CreateIAMRolesOpts.Name, CreateIAMRolesOpts.CredRequestDir, CreateIAMRolesOpts.EnableTechPreview); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	regionMap, err := parseRegionMap(CreateIAMRolesOpts.RegionMap)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	accountMap, err := parseAccountMap(CreateIAMRolesOpts.AccountMap)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if CreateIAMRolesOpts.EmitPoliciesDir != "" {
+		if err := provisioning.EnsureDir(CreateIAMRolesOpts.EmitPoliciesDir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	s, err := awsSession(CreateIAMRolesOpts.Region, CreateIAMRolesOpts.Profile)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	awsClient := aws.NewClientFromSession(s)
 
-	err = createIAMRoles(awsClient, CreateIAMRolesOpts.IdentityProviderARN, CreateIAMRolesOpts.PermissionsBoundaryARN, CreateIAMRolesOpts.Name,
-		CreateIAMRolesOpts.CredRequestDir, CreateIAMRolesOpts.TargetDir, CreateIAMRolesOpts.EnableTechPreview, CreateIAMRolesOpts.DryRun)
+	err = createIAMRoles(awsClient, CreateIAMRolesOpts.IdentityProviderARN, CreateIAMRolesOpts.PermissionsBoundaryARN, CreateIAMRolesOpts.IAMPath, CreateIAMRolesOpts.Name,
+		CreateIAMRolesOpts.CredRequestDir, CreateIAMRolesOpts.TargetDir, CreateIAMRolesOpts.EnableTechPreview, CreateIAMRolesOpts.DryRun, regionMap, accountMap)
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
+// parseRegionMap parses --region-map entries of the form "credReqName=region", validating that each
+// region is a recognized AWS region in some partition (aws, aws-us-gov, aws-cn). This mainly matters
+// for mixed-partition fleets, e.g. most CredentialsRequests targeting the commercial partition while
+// a handful need to land in GovCloud/China, since IAM itself has no per-region resources otherwise.
+func parseRegionMap(entries []string) (map[string]string, error) {
+	regionMap := map[string]string{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid --region-map entry %q, expected credReqName=region", entry)
+		}
+		credReqName, region := parts[0], parts[1]
+		if !isValidAWSRegion(region) {
+			return nil, errors.Errorf("invalid --region-map entry %q: %q is not a recognized AWS region", entry, region)
+		}
+		regionMap[credReqName] = region
+	}
+	return regionMap, nil
+}
+
+// parseAccountMap parses --account-map entries of the form "credReqName=roleArn", validating that
+// each value looks like an IAM role ARN. This lets a single ccoctl invocation provision
+// CredentialsRequests into different AWS Organizations member accounts by assuming a distinct role
+// per CredentialsRequest before creating its resources.
+func parseAccountMap(entries []string) (map[string]string, error) {
+	accountMap := map[string]string{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid --account-map entry %q, expected credReqName=roleArn", entry)
+		}
+		credReqName, roleARN := parts[0], parts[1]
+		if !iamRoleARNRegexp.MatchString(roleARN) {
+			return nil, errors.Errorf("invalid --account-map entry %q: %q is not an IAM role ARN", entry, roleARN)
+		}
+		accountMap[credReqName] = roleARN
+	}
+	return accountMap, nil
+}
+
+// accountClientFor returns an AWS client that has assumed roleARN in region, building and caching
+// one in cache the first time it is requested for that role. The resulting IAM/STS calls run
+// against whichever account roleARN lives in, so resources created through it (and their ARNs)
+// naturally land in, and are tagged with, that target account rather than the invoking account.
+func accountClientFor(cache map[string]aws.Client, roleARN, region string) (aws.Client, error) {
+	if client, ok := cache[roleARN]; ok {
+		return client, nil
+	}
+
+	baseSession, err := awsSession(region, CreateIAMRolesOpts.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	assumedSession, err := session.NewSession(&awssdk.Config{
+		Region:      awssdk.String(region),
+		Credentials: stscreds.NewCredentials(baseSession, roleARN),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client := aws.NewClientFromSession(assumedSession)
+	cache[roleARN] = client
+	return client, nil
+}
+
+// isValidAWSRegion reports whether region is a known region in any AWS partition.
+func isValidAWSRegion(region string) bool {
+	for _, partition := range endpoints.DefaultPartitions() {
+		if _, ok := partition.Regions()[region]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// regionClientFor returns an AWS client pinned to region, building and caching one in cache the
+// first time it is requested for that region.
+func regionClientFor(cache map[string]aws.Client, region string) (aws.Client, error) {
+	if client, ok := cache[region]; ok {
+		return client, nil
+	}
+
+	s, err := awsSession(region, CreateIAMRolesOpts.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	client := aws.NewClientFromSession(s)
+	cache[region] = client
+	return client, nil
+}
+
 // StatementEntry is a simple type used to serialize to AWS' PolicyDocument format.
 type StatementEntry struct {
 	Effect   string
@@ -319,10 +813,18 @@ func createRolePolicy(statements []credreqv1.StatementEntry) string {
 	}
 
 	for _, entry := range statements {
+		action := entry.Action
+		if CreateIAMRolesOpts.ReadOnly {
+			var removed []string
+			action, removed = stripMutatingActions(action, CreateIAMRolesOpts.ReadOnlyDenylist)
+			for _, a := range removed {
+				log.Printf("--read-only: removed mutating action %q from policy", a)
+			}
+		}
 		policyDocument.Statement = append(policyDocument.Statement,
 			StatementEntry{
 				Effect:    entry.Effect,
-				Action:    entry.Action,
+				Action:    action,
 				Resource:  entry.Resource,
 				Condition: entry.PolicyCondition,
 			})
@@ -336,15 +838,184 @@ func createRolePolicy(statements []credreqv1.StatementEntry) string {
 	return string(b)
 }
 
+// mutatingActionVerbPrefixes are the action-name verb prefixes (after the "service:" part, e.g.
+// the "Start" in "ec2:StartInstances") that --read-only treats as mutating and strips from
+// generated policies. It is a heuristic, not an exhaustive list of every AWS mutating verb, so it
+// is paired with an overridable --read-only-denylist for actions it misses.
+var mutatingActionVerbPrefixes = []string{
+	"Create", "Delete", "Put", "Update", "Attach", "Detach", "Add", "Remove", "Modify",
+	"Terminate", "Run", "Start", "Stop", "Reboot", "Associate", "Disassociate", "Revoke",
+	"Authorize", "Tag", "Untag", "Purchase", "Cancel", "Enable", "Disable", "Set", "Copy",
+	"Import", "Reset", "Restore", "Register", "Deregister", "Allocate", "Release", "Assume",
+}
+
+// stripMutatingActions removes actions that look mutating (by verb heuristic or by exact match in
+// denylist, case-insensitive) from actions, returning the remaining read-only actions and the list
+// of actions that were removed.
+func stripMutatingActions(actions []string, denylist []string) ([]string, []string) {
+	denied := sets.NewString()
+	for _, d := range denylist {
+		denied.Insert(strings.ToLower(d))
+	}
+
+	var kept, removed []string
+	for _, action := range actions {
+		verb := action
+		if idx := strings.Index(action, ":"); idx != -1 {
+			verb = action[idx+1:]
+		}
+		if denied.Has(strings.ToLower(action)) || hasMutatingVerbPrefix(verb) {
+			removed = append(removed, action)
+			continue
+		}
+		kept = append(kept, action)
+	}
+	return kept, removed
+}
+
+func hasMutatingVerbPrefix(verb string) bool {
+	for _, prefix := range mutatingActionVerbPrefixes {
+		if strings.HasPrefix(verb, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // writeCredReqSecret will take a credentialsRequest and a Role ARN and store
-// a Secret with an AWS config in the 'credentials' field of the Secret.
-func writeCredReqSecret(cr *credreqv1.CredentialsRequest, targetDir, roleARN string) error {
+// a Secret with an AWS config in the 'credentials' field of the Secret. If sealCertPath
+// is non-empty, a bitnami.com/v1alpha1 SealedSecret is written instead, so the manifest
+// is safe to commit to Git. If externalSecretsBackend is non-empty, an external-secrets.io
+// SecretStore + ExternalSecret pair is written instead, pointed at the backend under a key
+// derived from the CredentialsRequest's namespace/name; the credential itself still needs to be
+// placed there out-of-band, since this build has no backend SDKs to push it for you. If emitRBAC
+// is true, a ServiceAccount/Role/RoleBinding granting read access to the generated Secret is also
+// written alongside it. additionalSecretData, if non-empty, is included verbatim as extra keys in
+// the generated Secret (e.g. a region or endpoint a consuming operator also needs); it is rejected
+// if any key collides with "credentials", the key CCO itself manages. outputLayout picks the
+// generated manifest's file name (outputLayoutNamespaceName or outputLayoutPerCredReqName);
+// usedOutputFiles tracks which CredentialsRequest has already claimed each manifest file path
+// across the run, so two CredentialsRequests resolving to the same file under the chosen layout
+// error out instead of one silently overwriting the other's manifest.
+func writeCredReqSecret(cr *credreqv1.CredentialsRequest, targetDir, roleARN, sealCertPath, sealScope, externalSecretsBackend, externalSecretsBackendConfig string, emitRBAC, immutableSecrets bool, outputFormat, outputLayout, valuesTemplate, sopsConfigPath string, additionalSecretData map[string]string, usedOutputFiles map[string]string, defaultSecretType string) error {
 	manifestsDir := filepath.Join(targetDir, provisioning.ManifestsDirName)
 
-	fileName := fmt.Sprintf("%s-%s-credentials.yaml", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+	var fileName string
+	switch outputLayout {
+	case "", outputLayoutNamespaceName:
+		fileName = fmt.Sprintf("%s-%s-credentials.yaml", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+	case outputLayoutPerCredReqName:
+		fileName = fmt.Sprintf("%s.yaml", cr.Name)
+	default:
+		return errors.Errorf("unknown --output-layout %q", outputLayout)
+	}
 	filePath := filepath.Join(manifestsDir, fileName)
 
+	if owner, collision := usedOutputFiles[filePath]; collision && owner != cr.Name {
+		return errors.Errorf("--output-layout=%s: CredentialsRequests %s and %s both resolve to manifest file %s",
+			outputLayout, owner, cr.Name, filePath)
+	}
+	usedOutputFiles[filePath] = cr.Name
+
+	credentialsValue := fmt.Sprintf("[default]\nrole_arn = %s\nweb_identity_token_file = %s", roleARN, provisioning.OidcTokenPath)
+
+	if _, collision := additionalSecretData["credentials"]; collision {
+		return errors.New(`additionalSecretData key "credentials" collides with the key CCO uses for the generated AWS credentials`)
+	}
+
+	if (outputFormat == outputFormatDotenv || outputFormat == outputFormatHelmValues) && (sealCertPath != "" || externalSecretsBackend != "") {
+		return errors.Errorf("--output-format=%s cannot be combined with --seal-cert or --external-secrets-backend", outputFormat)
+	}
+
+	if outputFormat == outputFormatSops && (sealCertPath != "" || externalSecretsBackend != "") {
+		return errors.Errorf("--output-format=%s cannot be combined with --seal-cert or --external-secrets-backend", outputFormat)
+	}
+
+	if outputFormat == outputFormatSops && sopsConfigPath == "" {
+		return errors.New("--output-format=sops requires --sops-config")
+	}
+
+	if outputFormat == outputFormatDotenv {
+		dotenvData := map[string]string{"credentials": credentialsValue}
+		for key, value := range additionalSecretData {
+			dotenvData[key] = value
+		}
+		dotenvFileName := fmt.Sprintf("%s-%s-credentials.env", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+		dotenvFilePath := filepath.Join(manifestsDir, dotenvFileName)
+		if err := writeDotenvFile(dotenvFilePath, dotenvData); err != nil {
+			return errors.Wrap(err, "Failed to save dotenv file")
+		}
+		log.Printf("Saved credentials configuration to: %s", dotenvFilePath)
+		return nil
+	}
+
+	if outputFormat == outputFormatHelmValues {
+		return writeHelmValuesFragment(cr, manifestsDir, valuesTemplate)
+	}
+
+	if emitRBAC {
+		if err := provisioning.WriteRBACManifests(cr, targetDir); err != nil {
+			return errors.Wrap(err, "Failed to save RBAC manifests")
+		}
+	}
+
+	if externalSecretsBackend != "" {
+		backendRef := fmt.Sprintf("%s/%s", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+		storeFileName := fmt.Sprintf("%s-%s-secretstore.yaml", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+		storeFilePath := filepath.Join(manifestsDir, storeFileName)
+		secretKeys := []string{"credentials"}
+		for key := range additionalSecretData {
+			secretKeys = append(secretKeys, key)
+		}
+		if err := provisioning.WriteExternalSecretManifests(provisioning.ExternalSecretsBackend(externalSecretsBackend), externalSecretsBackendConfig,
+			cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name, backendRef, secretKeys, storeFilePath, filePath); err != nil {
+			return errors.Wrap(err, "Failed to save ExternalSecret manifests")
+		}
+		log.Printf("Saved ExternalSecret manifests to: %s and %s (ensure the credential is stored under %q in %s)", storeFilePath, filePath, backendRef, externalSecretsBackend)
+		return nil
+	}
+
+	// cr.Spec.SecretType, when set, lets an individual CredentialsRequest override the
+	// --secret-type default for this run.
+	secretType := string(cr.Spec.SecretType)
+	if secretType == "" {
+		secretType = defaultSecretType
+	}
+	if secretType == "" {
+		secretType = "Opaque"
+	}
+
+	if sealCertPath != "" {
+		sealedData := map[string]string{"credentials": credentialsValue}
+		for key, value := range additionalSecretData {
+			sealedData[key] = value
+		}
+		if err := provisioning.WriteSealedSecret(sealCertPath, provisioning.SealScope(sealScope), cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name, secretType,
+			sealedData, filePath, immutableSecrets); err != nil {
+			return errors.Wrap(err, "Failed to save SealedSecret file")
+		}
+		log.Printf("Saved sealed credentials configuration to: %s", filePath)
+		return nil
+	}
+
 	fileData := fmt.Sprintf(secretManifestsTemplate, roleARN, provisioning.OidcTokenPath, cr.Spec.SecretRef.Name, cr.Spec.SecretRef.Namespace)
+	if len(additionalSecretData) > 0 {
+		var extraStringData strings.Builder
+		for key, value := range additionalSecretData {
+			fmt.Fprintf(&extraStringData, "\n  %s: %s", key, value)
+		}
+		// additionalSecretData is inserted right after the "stringData:" line so the extra keys
+		// live alongside "credentials" rather than after the "type: Opaque" scalar at the end.
+		fileData = strings.Replace(fileData, "stringData:", "stringData:"+extraStringData.String(), 1)
+	}
+
+	if immutableSecrets {
+		fileData = strings.Replace(fileData, "kind: Secret", "kind: Secret\nimmutable: true", 1)
+	}
+
+	if secretType != "Opaque" {
+		fileData = strings.Replace(fileData, "type: Opaque", "type: "+secretType, 1)
+	}
 
 	// roleARN would be an empty string if ccoctl was in --dry-run mode
 	// so lets make sure we have an invalide Secret until the user
@@ -353,6 +1024,18 @@ func writeCredReqSecret(cr *credreqv1.CredentialsRequest, targetDir, roleARN str
 		fileData = fileData + "\nPOPULATE ROLE ARN AND DELETE THIS LINE"
 	}
 
+	if outputFormat == outputFormatSops {
+		encrypted, err := provisioning.EncryptWithSops(sopsConfigPath, []byte(fileData))
+		if err != nil {
+			return errors.Wrap(err, "Failed to encrypt Secret file with SOPS")
+		}
+		if err := ioutil.WriteFile(filePath, encrypted, 0600); err != nil {
+			return errors.Wrap(err, "Failed to save SOPS-encrypted Secret file")
+		}
+		log.Printf("Saved SOPS-encrypted credentials configuration to: %s", filePath)
+		return nil
+	}
+
 	if err := ioutil.WriteFile(filePath, []byte(fileData), 0600); err != nil {
 		return errors.Wrap(err, "Failed to save Secret file")
 	}
@@ -362,9 +1045,80 @@ func writeCredReqSecret(cr *credreqv1.CredentialsRequest, targetDir, roleARN str
 	return nil
 }
 
+// helmValuesTemplateData is passed to --values-template (or defaultHelmValuesTemplate) when
+// rendering a Helm values.yaml fragment for a CredentialsRequest's generated Secret.
+type helmValuesTemplateData struct {
+	CredentialsRequestName string
+	Namespace              string
+	SecretName             string
+}
+
+// writeHelmValuesFragment renders a Helm values.yaml fragment for cr's generated Secret, using
+// valuesTemplatePath if set or defaultHelmValuesTemplate otherwise, so downstream Helm charts can
+// pick up the Secret name/namespace as values instead of the Secret being applied directly.
+func writeHelmValuesFragment(cr *credreqv1.CredentialsRequest, manifestsDir, valuesTemplatePath string) error {
+	tmplText := defaultHelmValuesTemplate
+	if valuesTemplatePath != "" {
+		raw, err := ioutil.ReadFile(valuesTemplatePath)
+		if err != nil {
+			return errors.Wrap(err, "Failed to read --values-template")
+		}
+		tmplText = string(raw)
+	}
+
+	tmpl, err := template.New("helm-values").Parse(tmplText)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse --values-template")
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, helmValuesTemplateData{
+		CredentialsRequestName: cr.Name,
+		Namespace:              cr.Spec.SecretRef.Namespace,
+		SecretName:             cr.Spec.SecretRef.Name,
+	}); err != nil {
+		return errors.Wrap(err, "Failed to render --values-template")
+	}
+
+	valuesFileName := fmt.Sprintf("%s-%s-values.yaml", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+	valuesFilePath := filepath.Join(manifestsDir, valuesFileName)
+	if err := ioutil.WriteFile(valuesFilePath, rendered.Bytes(), 0600); err != nil {
+		return errors.Wrap(err, "Failed to save Helm values fragment")
+	}
+	log.Printf("Saved Helm values fragment to: %s", valuesFilePath)
+	return nil
+}
+
+// writeDotenvFile writes data as a "KEY=value" file, for consumers (e.g. bootstrap scripts) that
+// need the credential before the cluster exists to apply a Secret to. Values are double-quoted
+// with embedded newlines escaped, since the generated AWS credentials value is multi-line.
+func writeDotenvFile(path string, data map[string]string) error {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(data[key])
+		fmt.Fprintf(&sb, "%s=\"%s\"\n", strings.ToUpper(key), value)
+	}
+
+	return ioutil.WriteFile(path, []byte(sb.String()), 0600)
+}
+
 // initEnvForCreateIAMRolesCmd will ensure the destination directory is ready to receive the generated
 // files, and will create the directory if necessary.
 func initEnvForCreateIAMRolesCmd(cmd *cobra.Command, args []string) {
+	if CreateIAMRolesOpts.FromCluster {
+		if CreateIAMRolesOpts.CredRequestDir != "" {
+			log.Fatal("--credentials-requests-dir cannot be used together with --from-cluster")
+		}
+	} else if CreateIAMRolesOpts.CredRequestDir == "" {
+		log.Fatal("one of --credentials-requests-dir or --from-cluster is required")
+	}
+
 	if CreateIAMRolesOpts.TargetDir == "" {
 		pwd, err := os.Getwd()
 		if err != nil {
@@ -391,6 +1145,35 @@ func initEnvForCreateIAMRolesCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		log.Fatalf("failed to create manifests directory at %s", manifestsDir)
 	}
+
+	if err := provisioning.InitAuditLog(CreateIAMRolesOpts.AuditLogPath); err != nil {
+		log.Fatalf("failed to open audit log: %s", err)
+	}
+
+	if CreateIAMRolesOpts.NoSecret && (CreateIAMRolesOpts.SealCertPath != "" || CreateIAMRolesOpts.ExternalSecretsBackend != "") {
+		log.Fatal("--no-secret cannot be combined with --seal-cert or --external-secrets-backend, since those options operate on the Secret manifest --no-secret skips writing")
+	}
+
+	if err := validateIAMPath(CreateIAMRolesOpts.IAMPath); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// validateIAMPath enforces AWS IAM's path format (must begin and end with "/", and contain only
+// printable ASCII other than backslash) so a malformed --iam-path fails fast here instead of as an
+// opaque IAM API error after CredentialsRequests have already been partially processed. An empty
+// path is valid and means "use IAM's default path of /".
+func validateIAMPath(iamPath string) error {
+	if iamPath == "" {
+		return nil
+	}
+	if !strings.HasPrefix(iamPath, "/") || !strings.HasSuffix(iamPath, "/") {
+		return fmt.Errorf("--iam-path %q must begin and end with '/'", iamPath)
+	}
+	if !iamPathRegexp.MatchString(iamPath) {
+		return fmt.Errorf("--iam-path %q is not a valid IAM path", iamPath)
+	}
+	return nil
 }
 
 // NewCreateIAMRolesCmd provides the "create-iam-roles" subcommand
@@ -404,15 +1187,47 @@ func NewCreateIAMRolesCmd() *cobra.Command {
 
 	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.Name, "name", "", "User-define name for all created AWS resources (can be separate from the cluster's infra-id)")
 	createIAMRolesCmd.MarkPersistentFlagRequired("name")
-	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=aws' against an OpenShift release image)")
-	createIAMRolesCmd.MarkPersistentFlagRequired("credentials-requests-dir")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=aws' against an OpenShift release image). Accepts a comma-separated list of directories to merge. Cannot be combined with --from-cluster")
+	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.FromCluster, "from-cluster", false, "List CredentialsRequests from a live cluster via the Kubernetes API instead of --credentials-requests-dir")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.KubeConfigFile, "kubeconfig", "", "Path to the kubeconfig file to use when --from-cluster is set (defaults to the standard kubeconfig loading rules)")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.Namespace, "namespace", "", "Only list CredentialsRequests in this namespace when --from-cluster is set (defaults to all namespaces)")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.LabelSelector, "selector", "", "Only list CredentialsRequests matching this label selector when --from-cluster is set")
 	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.IdentityProviderARN, "identity-provider-arn", "", "ARN of IAM Identity provider for IAM Role trust relationship (can be created with the 'create identity-provider' sub-command)")
 	createIAMRolesCmd.MarkPersistentFlagRequired("identity-provider-arn")
 	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.PermissionsBoundaryARN, "permissions-boundary-arn", "", "ARN of IAM policy to use as the permissions boundary for created roles")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.IAMPath, "iam-path", "", "IAM path (e.g. /openshift/mycluster/) under which to create roles, for orgs that organize IAM roles by path for IAM-governance/SCP purposes. Must begin and end with '/'. The role's inline policy has no IAM path of its own and implicitly inherits the role's path")
 	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.Region, "region", "", "AWS region endpoint only required for GovCloud")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.Profile, "aws-profile", "", "Name of an AWS profile from the shared credentials/config files to use, overriding the default credential chain")
+	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.ReadOnly, "read-only", false, "Strip mutating actions from every generated IAM policy (by verb heuristic plus --read-only-denylist), for provisioning safe, throwaway audit clusters")
+	createIAMRolesCmd.PersistentFlags().StringSliceVar(&CreateIAMRolesOpts.ReadOnlyDenylist, "read-only-denylist", nil, "Additional action names to strip when --read-only is set, for mutating actions the verb heuristic misses")
 	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.DryRun, "dry-run", false, "Skip creating objects, and just save what would have been created into files")
 	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
 	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.EnableTechPreview, "enable-tech-preview", false, "Opt into processing CredentialsRequests marked as tech-preview")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.SealCertPath, "seal-cert", "", "Path to a sealed-secrets controller public certificate. When set, generated Secrets are written as bitnami.com/v1alpha1 SealedSecrets encrypted against this certificate instead of plaintext Secrets")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.SealScope, "seal-scope", "strict", "Sealed-secrets scope to encrypt against when --seal-cert is set (strict, namespace-wide, or cluster-wide)")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.AuditLogPath, "audit-log", "", "Path to a JSONL file to append an audit record (timestamp, operation, target, result) to for every mutating AWS API call made")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.ExternalSecretsBackend, "external-secrets-backend", "", "Emit external-secrets.io SecretStore/ExternalSecret manifests backed by this secret manager (aws-secrets-manager, gcp-secret-manager, or azure-key-vault) instead of a plaintext Secret. Not yet implemented: pushing the credential into the backend, since no backend SDK is vendored in this build; store it at the printed reference out-of-band before the ExternalSecret can resolve")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.ExternalSecretsBackendConfig, "external-secrets-backend-config", "", "Backend-specific addressing required by --external-secrets-backend: the AWS region, the GCP project ID, or the Azure Key Vault URL")
+	createIAMRolesCmd.PersistentFlags().StringSliceVar(&CreateIAMRolesOpts.Only, "only", []string{}, "Only process the named CredentialsRequests (comma-separated metadata.name values), leaving all others untouched")
+	createIAMRolesCmd.PersistentFlags().StringSliceVar(&CreateIAMRolesOpts.Exclude, "exclude", []string{}, "Process every CredentialsRequest except the named ones (comma-separated metadata.name values). Composes with --only; --exclude wins on overlap")
+	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.ImmutableSecrets, "immutable-secrets", false, "Mark each generated Secret manifest immutable: true, to prevent accidental edits")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.SecretType, "secret-type", "", "Secret.type to set on each generated Secret manifest, for consumers that expect something other than the default Opaque (e.g. kubernetes.io/basic-auth). Accepts any well-known corev1.SecretType value or a custom type. A CredentialsRequest's own spec.secretType, when set, overrides this default")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.OutputFormat, "output-format", outputFormatSecret, "Format to write each generated credential in: \"secret\" (a Secret manifest), \"dotenv\" (a .env file of KEY=value pairs, for use before the cluster exists to apply a Secret to), \"helm-values\" (a values.yaml fragment referencing the generated Secret, for Helm-based deployment pipelines), or \"sops\" (the Secret manifest encrypted as a SOPS document per --sops-config, for committing credentials to a GitOps repository). Not yet implemented: \"sops\" always fails with an explicit error, since none of go.mozilla.org/sops's encryption backends are vendored in this build; encrypt the generated Secret manifest out-of-band with the sops CLI instead. Not compatible with --seal-cert or --external-secrets-backend")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.SopsConfig, "sops-config", "", "Path to a .sops.yaml (or equivalent) file whose creation_rules configure the age/KMS/PGP recipients to encrypt against when --output-format=sops is set")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.OutputLayout, "output-layout", outputLayoutNamespaceName, "How to name each generated Secret/SealedSecret/ExternalSecret manifest file within the manifests dir: \"namespace-name\" (ccoctl's traditional <namespace>-<name>-credentials.yaml scheme), or \"per-credreq-name\" (<credreq-name>.yaml, for GitOps repositories that expect one file per CredentialsRequest name). Two CredentialsRequests resolving to the same file under the chosen layout is an error")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.ValuesTemplate, "values-template", "", "Path to a Go template file to render a Helm values.yaml fragment from when --output-format=helm-values is set, instead of the built-in default. Receives .CredentialsRequestName, .Namespace, and .SecretName")
+	createIAMRolesCmd.PersistentFlags().DurationVar(&CreateIAMRolesOpts.Timeout, "timeout", 0, "Maximum time to wait on each CredentialsRequest's AWS API calls before failing with a timeout error (e.g. 30s, 2m). Applies per CredentialsRequest, not to the whole command. Zero disables the timeout")
+	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.Force, "force", false, "Regenerate the Role and Secret for every CredentialsRequest even if its inputs are unchanged since the last run")
+	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.EmitRBAC, "emit-rbac", false, "Also emit a ServiceAccount, Role, and RoleBinding granting read access to each CredentialsRequest's generated Secret")
+	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.PreviewNames, "preview-names", false, "Print the IAM role name that would be generated for each CredentialsRequest and exit, without creating anything or contacting AWS")
+	createIAMRolesCmd.PersistentFlags().StringSliceVar(&CreateIAMRolesOpts.ClusterNames, "cluster-names", nil, "Comma-separated list of cluster names to stamp out a distinct Secret/role set for, one set per name under its own subdirectory of --output-dir, reusing a single parse of the CredentialsRequests")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.OtelEndpoint, "otel-endpoint", "", "OTLP collector endpoint to trace each CredentialsRequest's processing and AWS API call against, for diagnosing slow runs. Disabled (zero overhead) when unset")
+	createIAMRolesCmd.PersistentFlags().StringSliceVar(&CreateIAMRolesOpts.RegionMap, "region-map", nil, "Comma-separated credReqName=region pairs pinning specific CredentialsRequests to an AWS region other than --region, for mixed-partition fleets (e.g. a handful of CredentialsRequests that must land in GovCloud or China while the rest use the default partition)")
+	createIAMRolesCmd.PersistentFlags().StringSliceVar(&CreateIAMRolesOpts.AccountMap, "account-map", nil, "Comma-separated credReqName=roleArn pairs: before provisioning the named CredentialsRequest's resources, assume the given IAM role (typically in a different AWS Organizations member account) instead of using the caller's own credentials. The generated role's ARN (and --report output) naturally reflects whichever account it was created in")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.Report, "report", "", "Path to write a CredentialsRequest-to-resource report to, linking each CredentialsRequest to its generated IAM role ARN and target Secret. Written as JSON, or as CSV if the path ends in \".csv\". Useful for audits and for scripting precise cleanup")
+	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.NoSecret, "no-secret", false, "Create/update the IAM roles only, without writing Secret manifests. For STS/WIF workflows where the installer assembles the final Secret itself. Combine with --report to recover the generated role ARNs")
+	createIAMRolesCmd.PersistentFlags().BoolVar(&CreateIAMRolesOpts.StrictSubject, "strict-subject", false, "Fail a CredentialsRequest that cannot produce a single concrete \"sub\" value (i.e. does not specify exactly one ServiceAccount name) instead of building a trust policy that matches more than one service account")
+	createIAMRolesCmd.PersistentFlags().StringVar(&CreateIAMRolesOpts.EmitPoliciesDir, "emit-policies-dir", "", "Write each CredentialsRequest's computed trust policy and permissions policy to a JSON file in this directory for review, independent of --dry-run. Makes no additional AWS API calls; pairs well with 'validate' for a review-then-apply workflow")
 
 	return createIAMRolesCmd
 }
@@ -1,11 +1,13 @@
 package aws
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +16,10 @@ import (
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	mockaws "github.com/openshift/cloud-credential-operator/pkg/aws/mock"
 	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
 )
@@ -76,6 +81,8 @@ func TestIAMRoles(t *testing.T) {
 		cleanup       func(*testing.T)
 		generateOnly  bool
 		expectError   bool
+		emitRBAC      bool
+		noSecret      bool
 	}{
 		{
 			name:         "No CredReqs",
@@ -237,6 +244,64 @@ func TestIAMRoles(t *testing.T) {
 			},
 			verify: func(t *testing.T, targetDir, manifestsDir string) {},
 		},
+		{
+			name:         "Emit RBAC for one CredReq",
+			generateOnly: true,
+			emitRBAC:     true,
+			mockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetOpenIDConnectProvider(mockAWSClient)
+				return mockAWSClient
+			},
+			setup: func(t *testing.T) string {
+				tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+				require.NoError(t, err, "Failed to create temp directory")
+
+				err = testCredentialsRequest(t, "firstcredreq", "namespace1", "secretName1", tempDirName, false)
+				require.NoError(t, err, "errored while setting up test CredReq files")
+
+				return tempDirName
+			},
+			verify: func(t *testing.T, targetDir string, manifestsDir string) {
+				files, err := ioutil.ReadDir(manifestsDir)
+				require.NoError(t, err, "unexpected error listing files in manifestsDir")
+				assert.Equal(t, 2, provisioning.CountNonDirectoryFiles(files), "Should be exactly 1 secret and 1 RBAC manifest in manifestsDir")
+
+				rbacContent, err := ioutil.ReadFile(filepath.Join(manifestsDir, "namespace1-secretName1-rbac.yaml"))
+				require.NoError(t, err, "expected RBAC manifest to have been written")
+				assert.Contains(t, string(rbacContent), "kind: ServiceAccount")
+				assert.Contains(t, string(rbacContent), "kind: Role")
+				assert.Contains(t, string(rbacContent), "kind: RoleBinding")
+			},
+		},
+		{
+			name:         "No secret written for one CredReq",
+			generateOnly: true,
+			noSecret:     true,
+			mockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetOpenIDConnectProvider(mockAWSClient)
+				return mockAWSClient
+			},
+			setup: func(t *testing.T) string {
+				tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+				require.NoError(t, err, "Failed to create temp directory")
+
+				err = testCredentialsRequest(t, "firstcredreq", "namespace1", "secretName1", tempDirName, false)
+				require.NoError(t, err, "errored while setting up test CredReq files")
+
+				return tempDirName
+			},
+			verify: func(t *testing.T, targetDir string, manifestsDir string) {
+				files, err := ioutil.ReadDir(targetDir)
+				require.NoError(t, err, "unexpected error listing files in targetDir")
+				assert.Equal(t, 2, provisioning.CountNonDirectoryFiles(files), "Should be exactly 1 IAM Role JSON and 1 IAM Role Policy file for each CredReq")
+
+				files, err = ioutil.ReadDir(manifestsDir)
+				require.NoError(t, err, "unexpected error listing files in manifestsDir")
+				assert.Zero(t, provisioning.CountNonDirectoryFiles(files), "Should be no Secret manifest in manifestsDir when --no-secret is set")
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -258,7 +323,13 @@ func TestIAMRoles(t *testing.T) {
 			require.NoError(t, err, "unexpected error creating manifests dir for test")
 			defer os.RemoveAll(manifestsDir)
 
-			err = createIAMRoles(mockAWSClient, testIdentityProviderARN, testPermissionsBoundaryARN, testNamePrefix, credReqDir, targetDir, false, test.generateOnly)
+			CreateIAMRolesOpts.EmitRBAC = test.emitRBAC
+			defer func() { CreateIAMRolesOpts.EmitRBAC = false }()
+
+			CreateIAMRolesOpts.NoSecret = test.noSecret
+			defer func() { CreateIAMRolesOpts.NoSecret = false }()
+
+			err = createIAMRoles(mockAWSClient, testIdentityProviderARN, testPermissionsBoundaryARN, "", testNamePrefix, credReqDir, targetDir, false, test.generateOnly, nil, nil)
 
 			if test.expectError {
 				require.Error(t, err, "expected error returned")
@@ -339,3 +410,535 @@ func mockUpdateAssumeRolePolicy(mockAWSClient *mockaws.MockClient) {
 		&iam.UpdateAssumeRolePolicyOutput{}, nil,
 	).Times(1)
 }
+
+func TestStripMutatingActions(t *testing.T) {
+	actions := []string{
+		"ec2:DescribeInstances",
+		"ec2:CreateTags",
+		"s3:GetObject",
+		"s3:PutObject",
+		"iam:PassRole",
+	}
+
+	kept, removed := stripMutatingActions(actions, []string{"iam:PassRole"})
+
+	assert.Equal(t, []string{"ec2:DescribeInstances", "s3:GetObject"}, kept)
+	assert.ElementsMatch(t, []string{"ec2:CreateTags", "s3:PutObject", "iam:PassRole"}, removed)
+}
+
+func TestWriteDotenvFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "dotenvtest")
+	require.NoError(t, err, "unexpected error creating temp dir")
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "test.env")
+	err = writeDotenvFile(path, map[string]string{
+		"credentials": "[default]\nrole_arn = arn:aws:iam::123456789012:role/test",
+		"extra":       "value",
+	})
+	require.NoError(t, err, "unexpected error writing dotenv file")
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err, "unexpected error reading dotenv file")
+
+	assert.Equal(t, "CREDENTIALS=\"[default]\\nrole_arn = arn:aws:iam::123456789012:role/test\"\nEXTRA=\"value\"\n", string(content))
+}
+
+func TestWriteHelmValuesFragment(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "helmvaluestest")
+	require.NoError(t, err, "unexpected error creating temp dir")
+	defer os.RemoveAll(tempDir)
+
+	cr := &credreqv1.CredentialsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "testcr",
+		},
+		Spec: credreqv1.CredentialsRequestSpec{
+			SecretRef: corev1.ObjectReference{
+				Name:      "testcr-creds",
+				Namespace: "testns",
+			},
+		},
+	}
+
+	t.Run("default template", func(t *testing.T) {
+		err := writeHelmValuesFragment(cr, tempDir, "")
+		require.NoError(t, err, "unexpected error writing helm values fragment")
+
+		content, err := ioutil.ReadFile(filepath.Join(tempDir, "testns-testcr-creds-values.yaml"))
+		require.NoError(t, err, "unexpected error reading helm values fragment")
+
+		assert.Equal(t, "credentialsSecrets:\n  testcr:\n    namespace: testns\n    secretName: testcr-creds\n", string(content))
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		templatePath := filepath.Join(tempDir, "custom.tmpl")
+		err := ioutil.WriteFile(templatePath, []byte("{{ .SecretName }}.{{ .Namespace }}\n"), 0600)
+		require.NoError(t, err, "unexpected error writing custom template")
+
+		err = writeHelmValuesFragment(cr, tempDir, templatePath)
+		require.NoError(t, err, "unexpected error writing helm values fragment")
+
+		content, err := ioutil.ReadFile(filepath.Join(tempDir, "testns-testcr-creds-values.yaml"))
+		require.NoError(t, err, "unexpected error reading helm values fragment")
+
+		assert.Equal(t, "testcr-creds.testns\n", string(content))
+	})
+}
+
+func TestEmitPoliciesForReview(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "emitpoliciestest")
+	require.NoError(t, err, "unexpected error creating temp dir")
+	defer os.RemoveAll(tempDir)
+
+	cr := &credreqv1.CredentialsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "testcr",
+		},
+		Spec: credreqv1.CredentialsRequestSpec{
+			SecretRef: corev1.ObjectReference{
+				Name:      "testcr-creds",
+				Namespace: "testns",
+			},
+		},
+	}
+
+	err = emitPoliciesForReview(cr, "test-role", `{"trust":"policy"}`, `{"permissions":"policy"}`, tempDir)
+	require.NoError(t, err, "unexpected error emitting policies for review")
+
+	content, err := ioutil.ReadFile(filepath.Join(tempDir, "testns-testcr-creds-policies.json"))
+	require.NoError(t, err, "unexpected error reading emitted policies file")
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, "test-role", got["RoleName"])
+	assert.Equal(t, map[string]interface{}{"trust": "policy"}, got["AssumeRolePolicyDocument"])
+	assert.Equal(t, map[string]interface{}{"permissions": "policy"}, got["PolicyDocument"])
+}
+
+func TestWriteCredReqSecretOutputLayout(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "outputlayouttest")
+	require.NoError(t, err, "unexpected error creating temp dir")
+	defer os.RemoveAll(tempDir)
+	require.NoError(t, provisioning.EnsureDir(filepath.Join(tempDir, provisioning.ManifestsDirName)))
+
+	newCR := func(crName, secretNamespace, secretName string) *credreqv1.CredentialsRequest {
+		return &credreqv1.CredentialsRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: crName},
+			Spec: credreqv1.CredentialsRequestSpec{
+				SecretRef: corev1.ObjectReference{Namespace: secretNamespace, Name: secretName},
+			},
+		}
+	}
+
+	t.Run("namespace-name is the default layout", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		cr := newCR("firstcredreq", "namespace1", "secretName1")
+		err := writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, outputLayoutNamespaceName, "", "", nil, usedOutputFiles, "")
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(tempDir, provisioning.ManifestsDirName, "namespace1-secretName1-credentials.yaml"))
+		assert.NoError(t, err, "expected manifest named after namespace/secret name")
+	})
+
+	t.Run("per-credreq-name names the file after the CredentialsRequest", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		cr := newCR("firstcredreq", "namespace1", "secretName1")
+		err := writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, outputLayoutPerCredReqName, "", "", nil, usedOutputFiles, "")
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(tempDir, provisioning.ManifestsDirName, "firstcredreq.yaml"))
+		assert.NoError(t, err, "expected manifest named after the CredentialsRequest")
+	})
+
+	t.Run("colliding CredentialsRequests under namespace-name error", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		first := newCR("firstcredreq", "namespace1", "secretName1")
+		second := newCR("secondcredreq", "namespace1", "secretName1")
+		require.NoError(t, writeCredReqSecret(first, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, outputLayoutNamespaceName, "", "", nil, usedOutputFiles, ""))
+		err := writeCredReqSecret(second, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, outputLayoutNamespaceName, "", "", nil, usedOutputFiles, "")
+		assert.Error(t, err, "expected a naming collision error")
+	})
+
+	t.Run("unknown layout errors", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		cr := newCR("firstcredreq", "namespace1", "secretName1")
+		err := writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, "bogus-layout", "", "", nil, usedOutputFiles, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestWriteCredReqSecretType(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "secrettypetest")
+	require.NoError(t, err, "unexpected error creating temp dir")
+	defer os.RemoveAll(tempDir)
+	require.NoError(t, provisioning.EnsureDir(filepath.Join(tempDir, provisioning.ManifestsDirName)))
+
+	newCR := func(secretType corev1.SecretType) *credreqv1.CredentialsRequest {
+		return &credreqv1.CredentialsRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "testcr"},
+			Spec: credreqv1.CredentialsRequestSpec{
+				SecretRef:  corev1.ObjectReference{Namespace: "testns", Name: "testsecret"},
+				SecretType: secretType,
+			},
+		}
+	}
+
+	t.Run("defaults to Opaque when unset", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		cr := newCR("")
+		require.NoError(t, writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, outputLayoutPerCredReqName, "", "", nil, usedOutputFiles, ""))
+		data, err := ioutil.ReadFile(filepath.Join(tempDir, provisioning.ManifestsDirName, "testcr.yaml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "type: Opaque")
+	})
+
+	t.Run("honors a custom SecretType", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		cr := newCR(corev1.SecretTypeBasicAuth)
+		require.NoError(t, writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, outputLayoutPerCredReqName, "", "", nil, usedOutputFiles, ""))
+		data, err := ioutil.ReadFile(filepath.Join(tempDir, provisioning.ManifestsDirName, "testcr.yaml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "type: "+string(corev1.SecretTypeBasicAuth))
+		assert.NotContains(t, string(data), "type: Opaque")
+	})
+
+	t.Run("--secret-type default is used when the CredentialsRequest doesn't set its own", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		cr := newCR("")
+		require.NoError(t, writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, outputLayoutPerCredReqName, "", "", nil, usedOutputFiles, "kubernetes.io/basic-auth"))
+		data, err := ioutil.ReadFile(filepath.Join(tempDir, provisioning.ManifestsDirName, "testcr.yaml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "type: kubernetes.io/basic-auth")
+	})
+
+	t.Run("CredentialsRequest SecretType overrides the --secret-type default", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		cr := newCR(corev1.SecretTypeBasicAuth)
+		require.NoError(t, writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSecret, outputLayoutPerCredReqName, "", "", nil, usedOutputFiles, "kubernetes.io/dockerconfigjson"))
+		data, err := ioutil.ReadFile(filepath.Join(tempDir, provisioning.ManifestsDirName, "testcr.yaml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "type: "+string(corev1.SecretTypeBasicAuth))
+	})
+}
+
+func TestWriteCredReqSecretOutputFormatSops(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "sopsoutputtest")
+	require.NoError(t, err, "unexpected error creating temp dir")
+	defer os.RemoveAll(tempDir)
+	require.NoError(t, provisioning.EnsureDir(filepath.Join(tempDir, provisioning.ManifestsDirName)))
+
+	cr := &credreqv1.CredentialsRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcr"},
+		Spec: credreqv1.CredentialsRequestSpec{
+			SecretRef: corev1.ObjectReference{Namespace: "testns", Name: "testsecret"},
+		},
+	}
+
+	t.Run("requires --sops-config", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		err := writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSops, outputLayoutNamespaceName, "", "", nil, usedOutputFiles, "")
+		assert.Error(t, err, "expected --output-format=sops without --sops-config to error")
+	})
+
+	t.Run("errors because no SOPS encryption backend is vendored in this build", func(t *testing.T) {
+		usedOutputFiles := map[string]string{}
+		err := writeCredReqSecret(cr, tempDir, "test-role-arn", "", "", "", "", false, false, outputFormatSops, outputLayoutNamespaceName, "", "/path/to/.sops.yaml", nil, usedOutputFiles, "")
+		assert.Error(t, err, "expected --output-format=sops to surface the unimplemented-backend error")
+	})
+}
+
+func TestParseRegionMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		entries   []string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name:     "empty",
+			entries:  nil,
+			expected: map[string]string{},
+		},
+		{
+			name:     "valid entries",
+			entries:  []string{"cr-one=us-east-1", "cr-two=us-gov-west-1"},
+			expected: map[string]string{"cr-one": "us-east-1", "cr-two": "us-gov-west-1"},
+		},
+		{
+			name:      "missing equals sign",
+			entries:   []string{"cr-one"},
+			expectErr: true,
+		},
+		{
+			name:      "unrecognized region",
+			entries:   []string{"cr-one=not-a-real-region"},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			regionMap, err := parseRegionMap(test.entries)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, regionMap)
+		})
+	}
+}
+
+func TestParseAccountMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		entries   []string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name:     "empty",
+			entries:  nil,
+			expected: map[string]string{},
+		},
+		{
+			name:    "valid entries",
+			entries: []string{"cr-one=arn:aws:iam::123456789012:role/AdminRole", "cr-two=arn:aws-us-gov:iam::210987654321:role/GovRole"},
+			expected: map[string]string{
+				"cr-one": "arn:aws:iam::123456789012:role/AdminRole",
+				"cr-two": "arn:aws-us-gov:iam::210987654321:role/GovRole",
+			},
+		},
+		{
+			name:      "missing equals sign",
+			entries:   []string{"cr-one"},
+			expectErr: true,
+		},
+		{
+			name:      "not an IAM role ARN",
+			entries:   []string{"cr-one=not-an-arn"},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			accountMap, err := parseAccountMap(test.entries)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, accountMap)
+		})
+	}
+}
+
+func TestCreateRolePolicyDocument(t *testing.T) {
+	tests := []struct {
+		name                string
+		namespace           string
+		serviceAccountNames []string
+		strictSubject       bool
+		expectErr           bool
+		expectedSub         string
+		expectedSubs        []string
+	}{
+		{
+			name:                "single service account",
+			namespace:           "openshift-image-registry",
+			serviceAccountNames: []string{"registry"},
+			expectedSub:         `"system:serviceaccount:openshift-image-registry:registry"`,
+		},
+		{
+			name:                "multiple service accounts all appear as trust subjects",
+			namespace:           "openshift-image-registry",
+			serviceAccountNames: []string{"registry", "registry-canary"},
+			expectedSubs: []string{
+				`"system:serviceaccount:openshift-image-registry:registry"`,
+				`"system:serviceaccount:openshift-image-registry:registry-canary"`,
+			},
+		},
+		{
+			name:                "strict subject with single service account succeeds",
+			namespace:           "openshift-image-registry",
+			serviceAccountNames: []string{"registry"},
+			strictSubject:       true,
+			expectedSub:         `"system:serviceaccount:openshift-image-registry:registry"`,
+		},
+		{
+			name:                "strict subject with multiple service accounts fails",
+			namespace:           "openshift-image-registry",
+			serviceAccountNames: []string{"registry", "registry-canary"},
+			strictSubject:       true,
+			expectErr:           true,
+		},
+		{
+			name:          "strict subject with no service accounts fails",
+			namespace:     "openshift-image-registry",
+			strictSubject: true,
+			expectErr:     true,
+		},
+		{
+			name:      "no service accounts fails regardless of strict subject",
+			namespace: "openshift-image-registry",
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := createRolePolicyDocument("arn:aws:iam::123456789012:oidc-provider/s3.example.com", "s3.example.com", test.namespace, test.serviceAccountNames, test.strictSubject)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if test.expectedSub != "" {
+				assert.Contains(t, policy, test.expectedSub)
+			}
+			for _, expectedSub := range test.expectedSubs {
+				assert.Contains(t, policy, expectedSub)
+			}
+		})
+	}
+}
+
+func TestValidateIAMPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		iamPath   string
+		expectErr bool
+	}{
+		{
+			name:    "empty is valid",
+			iamPath: "",
+		},
+		{
+			name:    "valid single segment",
+			iamPath: "/openshift/",
+		},
+		{
+			name:    "valid multi segment",
+			iamPath: "/openshift/mycluster/",
+		},
+		{
+			name:      "missing leading slash",
+			iamPath:   "openshift/",
+			expectErr: true,
+		},
+		{
+			name:      "missing trailing slash",
+			iamPath:   "/openshift",
+			expectErr: true,
+		},
+		{
+			name:      "contains backslash",
+			iamPath:   "/open\\shift/",
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateIAMPath(test.iamPath)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "reporttest")
+	require.NoError(t, err, "unexpected error creating temp dir")
+	defer os.RemoveAll(tempDir)
+
+	entries := []ReportEntry{
+		{
+			CredentialsRequest: "testcr",
+			RoleARN:            "arn:aws:iam::123456789012:role/test",
+			SecretNamespace:    "testns",
+			SecretName:         "testcr-creds",
+		},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(tempDir, "report.json")
+		err := writeReport(path, entries)
+		require.NoError(t, err, "unexpected error writing report")
+
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err, "unexpected error reading report")
+
+		var got []ReportEntry
+		require.NoError(t, json.Unmarshal(content, &got))
+		assert.Equal(t, entries, got)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		path := filepath.Join(tempDir, "report.csv")
+		err := writeReport(path, entries)
+		require.NoError(t, err, "unexpected error writing report")
+
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err, "unexpected error reading report")
+
+		assert.Equal(t, "credentialsRequest,roleARN,secretNamespace,secretName\ntestcr,arn:aws:iam::123456789012:role/test,testns,testcr-creds\n", string(content))
+	})
+}
+
+func TestPruneOldPolicyVersions(t *testing.T) {
+	policyARN := "arn:aws:iam::123456789012:policy/test-policy"
+	baseTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	policyVersion := func(id string, ageDays int, isDefault bool) *iam.PolicyVersion {
+		return &iam.PolicyVersion{
+			VersionId:        awssdk.String(id),
+			CreateDate:       awssdk.Time(baseTime.AddDate(0, 0, -ageDays)),
+			IsDefaultVersion: awssdk.Bool(isDefault),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		versions       []*iam.PolicyVersion
+		expectedPruned []string
+	}{
+		{
+			name: "below limit, nothing pruned",
+			versions: []*iam.PolicyVersion{
+				policyVersion("v1", 10, true),
+				policyVersion("v2", 5, false),
+			},
+		},
+		{
+			name: "at limit, oldest non-default versions pruned to make room",
+			versions: []*iam.PolicyVersion{
+				policyVersion("v1", 50, false),
+				policyVersion("v2", 40, false),
+				policyVersion("v3", 30, false),
+				policyVersion("v4", 20, false),
+				policyVersion("v5", 10, true),
+			},
+			expectedPruned: []string{"v1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockAWSClient := mockaws.NewMockClient(mockCtrl)
+			mockAWSClient.EXPECT().ListPolicyVersions(gomock.Any()).Return(&iam.ListPolicyVersionsOutput{
+				Versions: test.versions,
+			}, nil)
+			for _, versionID := range test.expectedPruned {
+				mockAWSClient.EXPECT().DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+					PolicyArn: awssdk.String(policyARN),
+					VersionId: awssdk.String(versionID),
+				}).Return(&iam.DeletePolicyVersionOutput{}, nil)
+			}
+
+			err := pruneOldPolicyVersions(mockAWSClient, policyARN)
+			require.NoError(t, err)
+		})
+	}
+}
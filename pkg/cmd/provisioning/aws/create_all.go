@@ -21,7 +21,7 @@ var (
 )
 
 func createAllCmd(cmd *cobra.Command, args []string) {
-	s, err := awsSession(CreateAllOpts.Region)
+	s, err := awsSession(CreateAllOpts.Region, CreateAllOpts.Profile)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -37,16 +37,67 @@ func createAllCmd(cmd *cobra.Command, args []string) {
 		log.Fatalf("Failed to create public/private key pair: %s", err)
 	}
 
-	identityProviderARN, err := createIdentityProvider(awsClient, CreateAllOpts.Name, CreateAllOpts.Region, publicKeyPath, CreateAllOpts.TargetDir, false)
+	CreateIdentityProviderOpts.OIDCAudience = CreateAllOpts.OIDCAudience
+	identityProviderARN, err := createIdentityProvider(awsClient, CreateAllOpts.Name, CreateAllOpts.Region, publicKeyPath, CreateAllOpts.TargetDir, CreateAllOpts.OIDCBucketReplicaRegion, false)
 	if err != nil {
 		log.Fatalf("Failed to create Identity provider: %s", err)
 	}
 
-	err = createIAMRoles(awsClient, identityProviderARN, CreateAllOpts.PermissionsBoundaryARN, CreateAllOpts.Name,
-		CreateAllOpts.CredRequestDir, CreateAllOpts.TargetDir, CreateAllOpts.EnableTechPreview, false)
+	CreateIAMRolesOpts.SealCertPath = CreateAllOpts.SealCertPath
+	CreateIAMRolesOpts.SealScope = CreateAllOpts.SealScope
+	CreateIAMRolesOpts.AuditLogPath = CreateAllOpts.AuditLogPath
+	CreateIAMRolesOpts.Report = CreateAllOpts.Report
+	err = createIAMRoles(awsClient, identityProviderARN, CreateAllOpts.PermissionsBoundaryARN, CreateAllOpts.IAMPath, CreateAllOpts.Name,
+		CreateAllOpts.CredRequestDir, CreateAllOpts.TargetDir, CreateAllOpts.EnableTechPreview, false, nil, nil)
 	if err != nil {
 		log.Fatalf("Failed to process IAM Roles: %s", err)
 	}
+
+	if CreateAllOpts.OutputTar != "" {
+		manifestsDir := filepath.Join(CreateAllOpts.TargetDir, provisioning.ManifestsDirName)
+		if err := provisioning.WriteDeterministicTar(manifestsDir, CreateAllOpts.OutputTar); err != nil {
+			log.Fatalf("Failed to write manifests tarball: %s", err)
+		}
+	}
+
+	if CreateAllOpts.BundleOutput != "" {
+		// createIdentityProvider uploaded the JSON web key set to the OIDC bucket rather than
+		// writing it to the output directory, so save a local copy of it for the bundle.
+		jwks, err := provisioning.BuildJsonWebKeySet(publicKeyPath)
+		if err != nil {
+			log.Fatalf("Failed to build JSON web key set for the bundle: %s", err)
+		}
+		if err := os.WriteFile(filepath.Join(CreateAllOpts.TargetDir, oidcKeysFilename), jwks, fileModeCcoctlDryRun); err != nil {
+			log.Fatalf("Failed to save JSON web key set for the bundle: %s", err)
+		}
+		if err := writeCombinedBundle(CreateAllOpts.TargetDir, CreateAllOpts.Report, CreateAllOpts.BundleOutput); err != nil {
+			log.Fatalf("Failed to write combined bundle: %s", err)
+		}
+	}
+}
+
+// writeCombinedBundle packages the generated manifests, OIDC TLS keys, JSON web key set (when
+// present in targetDir, as createAllCmd ensures before calling this), and the CredentialsRequest-to-resource
+// report (when --report was given) into a single bundleOutput tarball with a manifest index, so
+// the whole of what create-all produced can be moved across a disconnected environment's air gap
+// as one artifact. ccoctl does not itself emit a CloudCredentialsMode config manifest - that is
+// owned by the installer/CVO, not generated by any ccoctl command in this tree - so it has nothing
+// to contribute to the bundle here.
+func writeCombinedBundle(targetDir, report, bundleOutput string) error {
+	entries := []provisioning.BundleEntry{
+		{Name: provisioning.ManifestsDirName, Path: filepath.Join(targetDir, provisioning.ManifestsDirName)},
+		{Name: provisioning.TLSDirName, Path: filepath.Join(targetDir, provisioning.TLSDirName)},
+		{Name: oidcKeysFilename, Path: filepath.Join(targetDir, oidcKeysFilename)},
+	}
+	if report != "" {
+		entries = append(entries, provisioning.BundleEntry{Name: filepath.Base(report), Path: report})
+	}
+
+	if err := provisioning.WriteBundle(entries, bundleOutput); err != nil {
+		return err
+	}
+	log.Printf("Saved combined disconnected-install bundle to %s", bundleOutput)
+	return nil
 }
 
 // initEnvForCreateAllCmd will ensure the destination directory is ready to receive the generated
@@ -85,6 +136,14 @@ func initEnvForCreateAllCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		log.Fatalf("failed to create tls directory at %s", tlsDir)
 	}
+
+	if err := provisioning.InitAuditLog(CreateAllOpts.AuditLogPath); err != nil {
+		log.Fatalf("failed to open audit log: %s", err)
+	}
+
+	if err := validateIAMPath(CreateAllOpts.IAMPath); err != nil {
+		log.Fatal(err)
+	}
 }
 
 // NewCreateAllCmd provides the "create-all" subcommand
@@ -101,10 +160,19 @@ func NewCreateAllCmd() *cobra.Command {
 	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.Region, "region", "", "AWS region where the S3 OpenID Connect endpoint will be created")
 	createAllCmd.MarkPersistentFlagRequired("region")
 	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.PermissionsBoundaryARN, "permissions-boundary-arn", "", "ARN of IAM policy to use as the permissions boundary for created roles")
-	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=aws' against an OpenShift release image)")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.IAMPath, "iam-path", "", "IAM path (e.g. /openshift/mycluster/) under which to create roles, for orgs that organize IAM roles by path for IAM-governance/SCP purposes. Must begin and end with '/'")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=aws' against an OpenShift release image). Accepts a comma-separated list of directories to merge")
 	createAllCmd.MarkPersistentFlagRequired("credentials-requests-dir")
 	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
 	createAllCmd.PersistentFlags().BoolVar(&CreateAllOpts.EnableTechPreview, "enable-tech-preview", false, "Opt into processing CredentialsRequests marked as tech-preview")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.SealCertPath, "seal-cert", "", "Path to a sealed-secrets controller public certificate. When set, generated Secrets are written as bitnami.com/v1alpha1 SealedSecrets encrypted against this certificate instead of plaintext Secrets")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.SealScope, "seal-scope", "strict", "Sealed-secrets scope to encrypt against when --seal-cert is set (strict, namespace-wide, or cluster-wide)")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.AuditLogPath, "audit-log", "", "Path to a JSONL file to append an audit record (timestamp, operation, target, result) to for every mutating AWS API call made")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.OutputTar, "output-tar", "", "Path to write a reproducible tar archive of the generated manifests directory to, for transfer into disconnected environments")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.OIDCBucketReplicaRegion, "oidc-bucket-replica-region", "", "AWS region to replicate the OIDC S3 bucket into, so the discovery endpoint survives an outage of --region")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.OIDCAudience, "oidc-audience", defaultOIDCAudience, "Audience to register as an allowed ClientID on the IAM Identity Provider, matching the 'aud' claim bound-SA tokens are issued with")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.Report, "report", "", "Path to write a CredentialsRequest-to-resource report to, linking each CredentialsRequest to its generated IAM role ARN and target Secret. Written as JSON, or as CSV if the path ends in \".csv\"")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.BundleOutput, "bundle-output", "", "Path to write a single gzip-compressed tarball (with a manifest index) combining the generated manifests, OIDC TLS keys, JSON web key set, and --report output, for transfer into disconnected environments as one artifact")
 
 	return createAllCmd
 }
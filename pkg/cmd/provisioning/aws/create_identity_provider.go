@@ -47,18 +47,20 @@ var (
 	"Bucket": "%s"
 }`
 
-	// iam identity provider with "openshift" and "sts.amazonaws.com" as static audiences
+	// iam identity provider with "openshift" and the configured --oidc-audience (sts.amazonaws.com by
+	// default) as static audiences
 	iamIdentityProviderTemplate = `{
 	"Url": "%s",
 	"ClientIDList": [
 		"openshift",
-		"sts.amazonaws.com"
-	],
-	"ThumbprintList": [
 		"%s"
-	]
+	],
+	"ThumbprintList": [%s]
 }
 `
+	// defaultOIDCAudience is the audience AWS STS expects in the 'aud' claim of bound-SA tokens
+	// presented to AssumeRoleWithWebIdentity when no --oidc-audience is specified.
+	defaultOIDCAudience = "sts.amazonaws.com"
 	// ccoctlAWSResourceTagKeyPrefix is the prefix of the tag key applied to the AWS resources created/shared by ccoctl
 	ccoctlAWSResourceTagKeyPrefix = "openshift.io/cloud-credential-operator"
 	// ownedCcoctlAWSResourceTagValue is the value of the tag applied to the AWS resources created by ccoctl
@@ -66,17 +68,41 @@ var (
 	// nameTagKey is the key of the "Name" tag applied to the AWS resources created by ccoctl
 	nameTagKey = "Name"
 	// Generated identity provider files
-	oidcBucketFilename          = "01-oidc-bucket.json"
-	oidcConfigurationFilename   = "02-openid-configuration"
-	oidcKeysFilename            = "03-keys.json"
-	iamIdentityProviderFilename = "04-iam-identity-provider.json"
+	oidcBucketFilename              = "01-oidc-bucket.json"
+	oidcBucketReplicaFilename       = "01a-oidc-bucket-replica.json"
+	oidcBucketReplicationFilename   = "01b-oidc-bucket-replication.json"
+	oidcConfigurationFilename       = "02-openid-configuration"
+	oidcKeysFilename                = "03-keys.json"
+	iamIdentityProviderFilename     = "04-iam-identity-provider.json"
+	oidcBucketReplicationRoleSuffix = "oidc-replication"
+
+	replicationAssumeRolePolicyTemplate = `{ "Version": "2012-10-17", "Statement": [ { "Effect": "Allow", "Principal": { "Service": "s3.amazonaws.com" }, "Action": "sts:AssumeRole" } ] }`
+
+	replicationRolePolicyTemplate = `{ "Version": "2012-10-17", "Statement": [ { "Effect": "Allow", "Action": [ "s3:GetReplicationConfiguration", "s3:ListBucket" ], "Resource": [ "arn:aws:s3:::%s" ] }, { "Effect": "Allow", "Action": [ "s3:GetObjectVersionForReplication", "s3:GetObjectVersionAcl", "s3:GetObjectVersionTagging" ], "Resource": [ "arn:aws:s3:::%s/*" ] }, { "Effect": "Allow", "Action": [ "s3:ReplicateObject", "s3:ReplicateDelete", "s3:ReplicateTags" ], "Resource": "arn:aws:s3:::%s/*" } ] }`
+
+	// oidcBucketReplicationTemplate is the S3 bucket replication config (usable with aws CLI --cli-input-json param)
+	oidcBucketReplicationTemplate = `{
+	"Bucket": "%s",
+	"ReplicationConfiguration": {
+		"Role": "%s",
+		"Rules": [
+			{
+				"ID": "%s",
+				"Status": "Enabled",
+				"Filter": {},
+				"DeleteMarkerReplication": { "Status": "Enabled" },
+				"Destination": { "Bucket": "arn:aws:s3:::%s" }
+			}
+		]
+	}
+}`
 )
 
 type JSONWebKeySet struct {
 	Keys []jose.JSONWebKey `json:"keys"`
 }
 
-func createIdentityProvider(client aws.Client, name, region, publicKeyPath, targetDir string, generateOnly bool) (string, error) {
+func createIdentityProvider(client aws.Client, name, region, publicKeyPath, targetDir, replicaRegion string, generateOnly bool) (string, error) {
 	// Create the S3 bucket
 	bucketName := fmt.Sprintf("%s-oidc", name)
 	if err := createOIDCBucket(client, bucketName, name, region, targetDir, generateOnly); err != nil {
@@ -84,18 +110,25 @@ func createIdentityProvider(client aws.Client, name, region, publicKeyPath, targ
 	}
 	issuerURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucketName, region)
 
+	if replicaRegion != "" {
+		if err := setupOIDCBucketReplication(client, bucketName, name, replicaRegion, targetDir, generateOnly); err != nil {
+			return "", errors.Wrap(err, "failed to set up cross-region replication for the OIDC bucket")
+		}
+	}
+
 	// Create the OIDC config file
 	if err := createOIDCConfiguration(client, bucketName, issuerURL, name, targetDir, generateOnly); err != nil {
 		return "", err
 	}
 
 	// Create the OIDC key list
-	if err := createJSONWebKeySet(client, publicKeyPath, bucketName, name, targetDir, generateOnly); err != nil {
+	if err := createJSONWebKeySet(client, publicKeyPath, bucketName, name, targetDir, CreateIdentityProviderOpts.OIDCSigningKMSKeyARN, generateOnly); err != nil {
 		return "", err
 	}
 
 	// Create the IAM Identity Provider
-	identityProviderARN, err := createIAMIdentityProvider(client, issuerURL, name, targetDir, generateOnly)
+	identityProviderARN, err := createIAMIdentityProvider(client, issuerURL, name, targetDir, CreateIdentityProviderOpts.OIDCAudience,
+		CreateIdentityProviderOpts.OIDCThumbprint, CreateIdentityProviderOpts.SkipThumbprintValidation, generateOnly)
 	if err != nil {
 		return "", err
 	}
@@ -132,16 +165,43 @@ func getTLSFingerprint(bucketURL string) (string, error) {
 	return buf.String(), nil
 }
 
-func createIAMIdentityProvider(client aws.Client, issuerURL, name, targetDir string, generateOnly bool) (string, error) {
+// resolveThumbprint returns the thumbprint(s) to register on the IAM OIDC identity provider.
+// By default it is computed from the issuer's TLS certificate chain, but that breaks when the
+// issuer is fronted by a CA that rotates intermediates. explicitThumbprint lets the caller
+// override it, and skipValidation registers the provider with no thumbprint at all, which AWS
+// now accepts for well-known OIDC providers.
+func resolveThumbprint(issuerURL, explicitThumbprint string, skipValidation bool) ([]string, error) {
+	if skipValidation {
+		return nil, nil
+	}
+	if explicitThumbprint != "" {
+		return []string{explicitThumbprint}, nil
+	}
+	fingerprint, err := getTLSFingerprint(issuerURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get fingerprint")
+	}
+	return []string{fingerprint}, nil
+}
+
+func createIAMIdentityProvider(client aws.Client, issuerURL, name, targetDir, audience, explicitThumbprint string, skipThumbprintValidation, generateOnly bool) (string, error) {
 	var providerARN string
 
-	fingerprint, err := getTLSFingerprint(issuerURL)
+	if audience == "" {
+		audience = defaultOIDCAudience
+	}
+
+	thumbprints, err := resolveThumbprint(issuerURL, explicitThumbprint, skipThumbprintValidation)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get fingerprint")
+		return "", err
 	}
 
 	if generateOnly {
-		oidcIdentityProviderJSON := fmt.Sprintf(iamIdentityProviderTemplate, issuerURL, fingerprint)
+		quotedThumbprints := make([]string, len(thumbprints))
+		for i, t := range thumbprints {
+			quotedThumbprints[i] = fmt.Sprintf("%q", t)
+		}
+		oidcIdentityProviderJSON := fmt.Sprintf(iamIdentityProviderTemplate, issuerURL, audience, strings.Join(quotedThumbprints, ", "))
 
 		iamIdentityProviderFullPath := filepath.Join(targetDir, iamIdentityProviderFilename)
 		log.Printf("Saving AWS IAM Identity Provider locally at %s", iamIdentityProviderFullPath)
@@ -172,13 +232,12 @@ func createIAMIdentityProvider(client aws.Client, issuerURL, name, targetDir str
 			oidcOutput, err := client.CreateOpenIDConnectProvider(&iam.CreateOpenIDConnectProviderInput{
 				ClientIDList: []*string{
 					awssdk.String("openshift"),
-					awssdk.String("sts.amazonaws.com"),
+					awssdk.String(audience),
 				},
-				ThumbprintList: []*string{
-					awssdk.String(fingerprint),
-				},
-				Url: awssdk.String(issuerURL),
+				ThumbprintList: awssdk.StringSlice(thumbprints),
+				Url:            awssdk.String(issuerURL),
 			})
+			provisioning.AuditLogCall("iam:CreateOpenIDConnectProvider", issuerURL, err)
 			if err != nil {
 				return "", errors.Wrap(err, "failed to create Identity Provider")
 			}
@@ -198,6 +257,7 @@ func createIAMIdentityProvider(client aws.Client, issuerURL, name, targetDir str
 					},
 				},
 			})
+			provisioning.AuditLogCall("iam:TagOpenIDConnectProvider", providerARN, err)
 			if err != nil {
 				return "", errors.Wrapf(err, "failed to tag the identity provider with arn: %s", providerARN)
 			}
@@ -208,7 +268,17 @@ func createIAMIdentityProvider(client aws.Client, issuerURL, name, targetDir str
 	return providerARN, nil
 }
 
-func createJSONWebKeySet(client aws.Client, publicKeyFilepath, bucketName, name, targetDir string, generateOnly bool) error {
+func createJSONWebKeySet(client aws.Client, publicKeyFilepath, bucketName, name, targetDir, kmsKeyARN string, generateOnly bool) error {
+	if kmsKeyARN != "" {
+		// Deriving the JWKS from an asymmetric KMS (or CloudHSM-backed custom key store) public
+		// key, via kms:GetPublicKey, requires the AWS KMS SDK client
+		// (github.com/aws/aws-sdk-go/service/kms), which is not vendored in this tree. Signing the
+		// tokens themselves is the kube-apiserver service-account-signing-key's responsibility, not
+		// ccoctl's, so that half of --oidc-signing-kms-key-arn is out of scope for this command
+		// regardless; only the JWKS derivation below would be ccoctl's to do.
+		return errors.Errorf("--oidc-signing-kms-key-arn is not yet supported: deriving the JWKS from a KMS public key requires vendoring github.com/aws/aws-sdk-go/service/kms; pass --public-key-file instead")
+	}
+
 	jwks, err := provisioning.BuildJsonWebKeySet(publicKeyFilepath)
 	if err != nil {
 		return errors.Wrap(err, "failed to build JSON web key set from the public key")
@@ -228,7 +298,7 @@ func createJSONWebKeySet(client aws.Client, publicKeyFilepath, bucketName, name,
 			Key:     awssdk.String(provisioning.KeysURI),
 			Tagging: awssdk.String(fmt.Sprintf("%s/%s=%s&%s=%s", ccoctlAWSResourceTagKeyPrefix, name, ownedCcoctlAWSResourceTagValue, nameTagKey, name)),
 		})
-
+		provisioning.AuditLogCall("s3:PutObject", fmt.Sprintf("%s/%s", bucketName, provisioning.KeysURI), err)
 		if err != nil {
 			return errors.Wrapf(err, "failed to upload JSON web key set (JWKS) in the S3 bucket %s", bucketName)
 		}
@@ -253,6 +323,7 @@ func createOIDCConfiguration(client aws.Client, bucketName, issuerURL, name, tar
 			Key:     awssdk.String(provisioning.DiscoveryDocumentURI),
 			Tagging: awssdk.String(fmt.Sprintf("%s/%s=%s&%s=%s", ccoctlAWSResourceTagKeyPrefix, name, ownedCcoctlAWSResourceTagValue, nameTagKey, name)),
 		})
+		provisioning.AuditLogCall("s3:PutObject", fmt.Sprintf("%s/%s", bucketName, provisioning.DiscoveryDocumentURI), err)
 		if err != nil {
 			return errors.Wrapf(err, "failed to upload discovery document in the S3 bucket %s", bucketName)
 		}
@@ -289,6 +360,7 @@ func createOIDCBucket(client aws.Client, bucketName, name, region, targetDir str
 		}
 
 		_, err := client.CreateBucket(s3BucketInput)
+		provisioning.AuditLogCall("s3:CreateBucket", bucketName, err)
 		if err != nil {
 			var aerr awserr.Error
 			if errors.As(err, &aerr) {
@@ -318,6 +390,7 @@ func createOIDCBucket(client aws.Client, bucketName, name, region, targetDir str
 					},
 				},
 			})
+			provisioning.AuditLogCall("s3:PutBucketTagging", bucketName, err)
 			if err != nil {
 				return errors.Wrapf(err, "failed to tag the bucket %s", bucketName)
 			}
@@ -327,6 +400,139 @@ func createOIDCBucket(client aws.Client, bucketName, name, region, targetDir str
 	return nil
 }
 
+// setupOIDCBucketReplication creates a replica of the OIDC bucket in replicaRegion and configures
+// cross-region replication from bucketName to it, so the discovery endpoint survives an outage of
+// bucketName's region. The replica bucket is named bucketName+"-replica" and tagged the same way
+// as the primary bucket, so it is picked up by the existing tag-based delete flow; the IAM role
+// created for replication is likewise tagged and cleaned up by the existing deleteIAMRoles pass.
+func setupOIDCBucketReplication(client aws.Client, bucketName, name, replicaRegion, targetDir string, generateOnly bool) error {
+	replicaBucketName := bucketName + "-replica"
+	roleName := fmt.Sprintf("%s-%s", name, oidcBucketReplicationRoleSuffix)
+
+	if generateOnly {
+		oidcBucketReplicaJSON := fmt.Sprintf(oidcBucketTemplateWithLocation, replicaBucketName, replicaRegion)
+		oidcBucketReplicaFullPath := filepath.Join(targetDir, oidcBucketReplicaFilename)
+		log.Printf("Saving OIDC S3 bucket replica locally at %s", oidcBucketReplicaFullPath)
+		if err := ioutil.WriteFile(oidcBucketReplicaFullPath, []byte(oidcBucketReplicaJSON), fileModeCcoctlDryRun); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Failed to save OIDC S3 bucket replica locally at %s", oidcBucketReplicaFullPath))
+		}
+
+		// The replication role does not exist yet in generate-only mode, so the Role field is left
+		// as a placeholder for the operator to fill in with the ARN of a role created from
+		// replicationAssumeRolePolicyTemplate/replicationRolePolicyTemplate before applying this file.
+		placeholderRoleARN := fmt.Sprintf("arn:aws:iam::<AWS_ACCOUNT_ID>:role/%s", roleName)
+		oidcBucketReplicationJSON := fmt.Sprintf(oidcBucketReplicationTemplate, bucketName, placeholderRoleARN, roleName, replicaBucketName)
+		oidcBucketReplicationFullPath := filepath.Join(targetDir, oidcBucketReplicationFilename)
+		log.Printf("Saving OIDC S3 bucket replication configuration locally at %s", oidcBucketReplicationFullPath)
+		return ioutil.WriteFile(oidcBucketReplicationFullPath, []byte(oidcBucketReplicationJSON), fileModeCcoctlDryRun)
+	}
+
+	replicaSession, err := awsSession(replicaRegion, CreateIdentityProviderOpts.Profile)
+	if err != nil {
+		return errors.Wrap(err, "failed to create AWS session for the replica region")
+	}
+	replicaClient := aws.NewClientFromSession(replicaSession)
+
+	if err := createOIDCBucket(replicaClient, replicaBucketName, name, replicaRegion, targetDir, false); err != nil {
+		return errors.Wrap(err, "failed to create replica OIDC bucket")
+	}
+
+	_, err = client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket:                  awssdk.String(bucketName),
+		VersioningConfiguration: &s3.VersioningConfiguration{Status: awssdk.String(s3.BucketVersioningStatusEnabled)},
+	})
+	provisioning.AuditLogCall("s3:PutBucketVersioning", bucketName, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable versioning on the bucket %s", bucketName)
+	}
+
+	_, err = replicaClient.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket:                  awssdk.String(replicaBucketName),
+		VersioningConfiguration: &s3.VersioningConfiguration{Status: awssdk.String(s3.BucketVersioningStatusEnabled)},
+	})
+	provisioning.AuditLogCall("s3:PutBucketVersioning", replicaBucketName, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable versioning on the replica bucket %s", replicaBucketName)
+	}
+
+	createdRoleARN, err := createOIDCBucketReplicationRole(client, roleName, bucketName, name)
+	if err != nil {
+		return errors.Wrap(err, "failed to create IAM role for OIDC bucket replication")
+	}
+
+	_, err = client.PutBucketReplication(&s3.PutBucketReplicationInput{
+		Bucket: awssdk.String(bucketName),
+		ReplicationConfiguration: &s3.ReplicationConfiguration{
+			Role: awssdk.String(createdRoleARN),
+			Rules: []*s3.ReplicationRule{
+				{
+					ID:                      awssdk.String(roleName),
+					Status:                  awssdk.String(s3.ReplicationRuleStatusEnabled),
+					Filter:                  &s3.ReplicationRuleFilter{},
+					DeleteMarkerReplication: &s3.DeleteMarkerReplication{Status: awssdk.String(s3.DeleteMarkerReplicationStatusEnabled)},
+					Destination: &s3.Destination{
+						Bucket: awssdk.String(fmt.Sprintf("arn:aws:s3:::%s", replicaBucketName)),
+					},
+				},
+			},
+		},
+	})
+	provisioning.AuditLogCall("s3:PutBucketReplication", bucketName, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure replication from bucket %s to %s", bucketName, replicaBucketName)
+	}
+	log.Printf("Replication from bucket %s to %s in region %s configured", bucketName, replicaBucketName, replicaRegion)
+
+	return nil
+}
+
+// createOIDCBucketReplicationRole creates (or reuses, if already created by a previous run) the
+// IAM role S3 assumes to replicate objects out of bucketName, tagged the same way as other ccoctl
+// AWS resources so it is removed by the existing tag-based IAM role deletion pass.
+func createOIDCBucketReplicationRole(client aws.Client, roleName, bucketName, name string) (string, error) {
+	roleOutput, err := client.CreateRole(&iam.CreateRoleInput{
+		RoleName:                 awssdk.String(roleName),
+		AssumeRolePolicyDocument: awssdk.String(replicationAssumeRolePolicyTemplate),
+		Tags: []*iam.Tag{
+			{
+				Key:   awssdk.String(fmt.Sprintf("%s/%s", ccoctlAWSResourceTagKeyPrefix, name)),
+				Value: awssdk.String(ownedCcoctlAWSResourceTagValue),
+			},
+			{
+				Key:   awssdk.String(nameTagKey),
+				Value: awssdk.String(roleName),
+			},
+		},
+	})
+	provisioning.AuditLogCall("iam:CreateRole", roleName, err)
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == iam.ErrCodeEntityAlreadyExistsException {
+			existing, getErr := client.GetRole(&iam.GetRoleInput{RoleName: awssdk.String(roleName)})
+			if getErr != nil {
+				return "", errors.Wrapf(getErr, "failed to look up existing replication role %s", roleName)
+			}
+			return *existing.Role.Arn, nil
+		}
+		return "", errors.Wrapf(err, "failed to create replication role %s", roleName)
+	}
+	roleARN := *roleOutput.Role.Arn
+
+	replicationPolicy := fmt.Sprintf(replicationRolePolicyTemplate, bucketName, bucketName, bucketName)
+	_, err = client.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       awssdk.String(roleName),
+		PolicyName:     awssdk.String(roleName),
+		PolicyDocument: awssdk.String(replicationPolicy),
+	})
+	provisioning.AuditLogCall("iam:PutRolePolicy", roleName, err)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to attach replication policy to role %s", roleName)
+	}
+
+	log.Printf("IAM Role %s created for OIDC bucket replication", roleName)
+	return roleARN, nil
+}
+
 // isExistingIdentifyProvider checks if given identity provider is owned by given name prefix
 func isExistingIdentifyProvider(client aws.Client, providerARN, namePrefix string) (bool, error) {
 	provider, err := client.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
@@ -345,7 +551,11 @@ func isExistingIdentifyProvider(client aws.Client, providerARN, namePrefix strin
 }
 
 func createIdentityProviderCmd(cmd *cobra.Command, args []string) {
-	s, err := awsSession(CreateIdentityProviderOpts.Region)
+	if CreateIdentityProviderOpts.OIDCThumbprint != "" && CreateIdentityProviderOpts.SkipThumbprintValidation {
+		log.Fatal("--oidc-thumbprint and --skip-thumbprint-validation are mutually exclusive")
+	}
+
+	s, err := awsSession(CreateIdentityProviderOpts.Region, CreateIdentityProviderOpts.Profile)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -357,7 +567,7 @@ func createIdentityProviderCmd(cmd *cobra.Command, args []string) {
 		publicKeyPath = filepath.Join(CreateIdentityProviderOpts.TargetDir, provisioning.PublicKeyFile)
 	}
 
-	_, err = createIdentityProvider(awsClient, CreateIdentityProviderOpts.Name, CreateIdentityProviderOpts.Region, publicKeyPath, CreateIdentityProviderOpts.TargetDir, CreateIdentityProviderOpts.DryRun)
+	_, err = createIdentityProvider(awsClient, CreateIdentityProviderOpts.Name, CreateIdentityProviderOpts.Region, publicKeyPath, CreateIdentityProviderOpts.TargetDir, CreateIdentityProviderOpts.OIDCBucketReplicaRegion, CreateIdentityProviderOpts.DryRun)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -392,6 +602,10 @@ func initEnvForCreateIdentityProviderCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		log.Fatalf("failed to create manifests directory at %s", manifestsDir)
 	}
+
+	if err := provisioning.InitAuditLog(CreateIdentityProviderOpts.AuditLogPath); err != nil {
+		log.Fatalf("failed to open audit log: %s", err)
+	}
 }
 
 // NewCreateIdentityProviderCmd provides the "create-identity-provider" subcommand
@@ -410,6 +624,12 @@ func NewCreateIdentityProviderCmd() *cobra.Command {
 	createIdentityProviderCmd.PersistentFlags().StringVar(&CreateIdentityProviderOpts.PublicKeyPath, "public-key-file", "", "Path to public ServiceAccount signing key")
 	createIdentityProviderCmd.PersistentFlags().BoolVar(&CreateIdentityProviderOpts.DryRun, "dry-run", false, "Skip creating objects, and just save what would have been created into files")
 	createIdentityProviderCmd.PersistentFlags().StringVar(&CreateIdentityProviderOpts.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
+	createIdentityProviderCmd.PersistentFlags().StringVar(&CreateIdentityProviderOpts.OIDCBucketReplicaRegion, "oidc-bucket-replica-region", "", "AWS region to replicate the OIDC S3 bucket into, so the discovery endpoint survives an outage of --region")
+	createIdentityProviderCmd.PersistentFlags().StringVar(&CreateIdentityProviderOpts.OIDCAudience, "oidc-audience", defaultOIDCAudience, "Audience to register as an allowed ClientID on the IAM Identity Provider, matching the 'aud' claim bound-SA tokens are issued with")
+	createIdentityProviderCmd.PersistentFlags().StringVar(&CreateIdentityProviderOpts.OIDCSigningKMSKeyARN, "oidc-signing-kms-key-arn", "", "ARN of an asymmetric AWS KMS key holding the ServiceAccount signing key, instead of --public-key-file. Not yet implemented: fails with an explicit error, since deriving the JWKS from a KMS public key requires the AWS KMS SDK, which this build does not vendor")
+	createIdentityProviderCmd.PersistentFlags().StringVar(&CreateIdentityProviderOpts.OIDCThumbprint, "oidc-thumbprint", "", "SHA-1 thumbprint to register on the IAM OIDC identity provider, instead of one computed from the issuer's TLS certificate chain. Useful when the issuer is fronted by a CA that rotates intermediates. Mutually exclusive with --skip-thumbprint-validation")
+	createIdentityProviderCmd.PersistentFlags().BoolVar(&CreateIdentityProviderOpts.SkipThumbprintValidation, "skip-thumbprint-validation", false, "Register the IAM OIDC identity provider without a thumbprint, which AWS now accepts for well-known OIDC providers. Mutually exclusive with --oidc-thumbprint")
+	createIdentityProviderCmd.PersistentFlags().StringVar(&CreateIdentityProviderOpts.AuditLogPath, "audit-log", "", "Path to a JSONL file to append an audit record (timestamp, operation, target, result) to for every mutating AWS API call made")
 
 	return createIdentityProviderCmd
 }
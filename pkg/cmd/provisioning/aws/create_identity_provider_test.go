@@ -163,7 +163,7 @@ func TestCreateIdentityProvider(t *testing.T) {
 
 			testPublicKeyPath := filepath.Join(tempDirName, testPublicKeyFile)
 
-			_, err := createIdentityProvider(mockAWSClient, testInfraName, testRegionName, testPublicKeyPath, tempDirName, test.generateOnly)
+			_, err := createIdentityProvider(mockAWSClient, testInfraName, testRegionName, testPublicKeyPath, tempDirName, "", test.generateOnly)
 
 			if test.expectError {
 				require.Error(t, err, "expected error returned")
@@ -207,3 +207,52 @@ func mockTagOpenIDConnectProvider(mockAWSClient *mockaws.MockClient) {
 	mockAWSClient.EXPECT().TagOpenIDConnectProvider(gomock.Any()).Return(
 		&iam.TagOpenIDConnectProviderOutput{}, nil).AnyTimes()
 }
+
+func TestSetupOIDCBucketReplicationGenerateOnly(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAWSClient := mockaws.NewMockClient(mockCtrl)
+
+	tempDirName, err := ioutil.TempDir("", testDirPrefix)
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDirName)
+
+	err = setupOIDCBucketReplication(mockAWSClient, testInfraName+"-oidc", testInfraName, "test-replica-region", tempDirName, true)
+	require.NoError(t, err)
+
+	replicaBucketContents, err := ioutil.ReadFile(filepath.Join(tempDirName, oidcBucketReplicaFilename))
+	require.NoError(t, err)
+	assert.Contains(t, string(replicaBucketContents), testInfraName+"-oidc-replica")
+	assert.Contains(t, string(replicaBucketContents), "test-replica-region")
+
+	replicationContents, err := ioutil.ReadFile(filepath.Join(tempDirName, oidcBucketReplicationFilename))
+	require.NoError(t, err)
+	assert.Contains(t, string(replicationContents), testInfraName+"-oidc-replica")
+}
+
+func TestResolveThumbprint(t *testing.T) {
+	tests := []struct {
+		name               string
+		explicitThumbprint string
+		skipValidation     bool
+		expected           []string
+	}{
+		{
+			name:               "explicit thumbprint overrides computed fingerprint",
+			explicitThumbprint: "AAAABBBBCCCCDDDDEEEEFFFF00001111222233334444",
+			expected:           []string{"AAAABBBBCCCCDDDDEEEEFFFF00001111222233334444"},
+		},
+		{
+			name:           "skip validation registers no thumbprint",
+			skipValidation: true,
+			expected:       nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			thumbprints, err := resolveThumbprint("https://issuer.example.com", test.explicitThumbprint, test.skipValidation)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, thumbprints)
+		})
+	}
+}
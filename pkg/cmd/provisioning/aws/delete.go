@@ -1,26 +1,49 @@
 package aws
 
 import (
+	"bufio"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/openshift/cloud-credential-operator/pkg/aws"
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
+)
+
+const (
+	// resourceTypeRoles covers the IAM roles (and their inline policies) created by create-iam-roles
+	resourceTypeRoles = "roles"
+	// resourceTypeBucket covers the OIDC S3 bucket (and its replica, if any) created by create-identity-provider
+	resourceTypeBucket = "bucket"
+	// resourceTypeOIDC covers the IAM Identity Provider created by create-identity-provider
+	resourceTypeOIDC = "oidc"
 )
 
+// allResourceTypes is the --resource-types default: deleting everything, preserving the behavior
+// from before the flag existed.
+var allResourceTypes = []string{resourceTypeRoles, resourceTypeBucket, resourceTypeOIDC}
+
 var (
 	// DeleteOpts captures the options that affect deletion
 	// of the generated objects.
 	DeleteOpts = options{}
 )
 
-// deleteOIDCObjectsFromBucket deletes the OIDC objects from the S3 bucket
-func deleteOIDCObjectsFromBucket(client aws.Client, bucketName, namePrefix string) error {
+// deleteOIDCObjectsFromBucket deletes the OIDC objects from the S3 bucket. If continueOnError is
+// true, a failure to delete one object does not stop the rest from being attempted; all errors
+// are returned together at the end.
+func deleteOIDCObjectsFromBucket(client aws.Client, bucketName, namePrefix string, continueOnError bool) error {
 	objectsMetadata, err := client.ListObjects(&s3.ListObjectsInput{
 		Bucket: awssdk.String(bucketName),
 	})
@@ -28,13 +51,19 @@ func deleteOIDCObjectsFromBucket(client aws.Client, bucketName, namePrefix strin
 		return errors.Wrapf(err, "failed to fetch list of Identity Provider objects in the bucket %s", bucketName)
 	}
 
+	var errs []error
 	for _, objectMetadata := range objectsMetadata.Contents {
 		objectTags, err := client.GetObjectTagging(&s3.GetObjectTaggingInput{
 			Key:    objectMetadata.Key,
 			Bucket: awssdk.String(bucketName),
 		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to fetch tags of Identity Provider object %s in the bucket %s", *objectMetadata.Key, bucketName)
+			err = errors.Wrapf(err, "failed to fetch tags of Identity Provider object %s in the bucket %s", *objectMetadata.Key, bucketName)
+			if !continueOnError {
+				return err
+			}
+			errs = append(errs, err)
+			continue
 		}
 
 		for _, tag := range objectTags.TagSet {
@@ -43,8 +72,14 @@ func deleteOIDCObjectsFromBucket(client aws.Client, bucketName, namePrefix strin
 					Key:    objectMetadata.Key,
 					Bucket: awssdk.String(bucketName),
 				})
+				provisioning.AuditLogCall("s3:DeleteObject", *objectMetadata.Key, err)
 				if err != nil {
-					return errors.Wrapf(err, "failed to delete Identity Provider object %s in the bucket %s", *objectMetadata.Key, bucketName)
+					err = errors.Wrapf(err, "failed to delete Identity Provider object %s in the bucket %s", *objectMetadata.Key, bucketName)
+					if !continueOnError {
+						return err
+					}
+					errs = append(errs, err)
+					break
 				}
 				log.Printf("Identity Provider object %s deleted from the bucket %s", *objectMetadata.Key, bucketName)
 				break
@@ -52,7 +87,7 @@ func deleteOIDCObjectsFromBucket(client aws.Client, bucketName, namePrefix strin
 		}
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
 }
 
 // deleteOIDCBucket deletes the OIDC S3 bucket
@@ -69,6 +104,7 @@ func deleteOIDCBucket(client aws.Client, bucketName, namePrefix string) error {
 			_, err := client.DeleteBucket(&s3.DeleteBucketInput{
 				Bucket: awssdk.String(bucketName),
 			})
+			provisioning.AuditLogCall("s3:DeleteBucket", bucketName, err)
 			if err != nil {
 				return errors.Wrapf(err, "failed to delete the Identity Provider bucket %s", bucketName)
 			}
@@ -80,36 +116,56 @@ func deleteOIDCBucket(client aws.Client, bucketName, namePrefix string) error {
 	return nil
 }
 
-// deleteIAMRoles deletes the IAM Roles created by ccoctl
-func deleteIAMRoles(client aws.Client, namePrefix string, paginationMarker *string) error {
+// deleteIAMRoles deletes the IAM Roles created by ccoctl. If continueOnError is true, a failure
+// to delete one role does not stop the remaining roles (including later pages) from being
+// attempted; all errors are returned together at the end.
+func deleteIAMRoles(client aws.Client, namePrefix, iamPath string, paginationMarker *string, continueOnError bool) error {
 	// iam.ListRolesInput results are paginated to 100 items by default, if result is truncated we need to
 	// fetch next set of items and perform delete operation
 	roleList, err := client.ListRoles(&iam.ListRolesInput{
-		Marker: paginationMarker,
+		Marker:     paginationMarker,
+		PathPrefix: pathPrefixOrNil(iamPath),
 	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to fetch a list of IAM roles, pagination marker: %v", paginationMarker)
 	}
 
+	var errs []error
 	for _, roleMetadata := range roleList.Roles {
 		roleOutput, err := client.GetRole(&iam.GetRoleInput{
 			RoleName: roleMetadata.RoleName,
 		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to fetch IAM role %s", *roleMetadata.RoleName)
+			err = errors.Wrapf(err, "failed to fetch IAM role %s", *roleMetadata.RoleName)
+			if !continueOnError {
+				return err
+			}
+			errs = append(errs, err)
+			continue
 		}
 
 		for _, tag := range roleOutput.Role.Tags {
 			if *tag.Key == fmt.Sprintf("%s/%s", ccoctlAWSResourceTagKeyPrefix, namePrefix) {
-				if err := deleteRolePolicies(client, *roleOutput.Role.RoleName); err != nil {
-					return errors.Wrapf(err, "failed to delete policies associated with IAM Role %s", *roleOutput.Role.RoleName)
+				if err := deleteRolePolicies(client, *roleOutput.Role.RoleName, continueOnError); err != nil {
+					err = errors.Wrapf(err, "failed to delete policies associated with IAM Role %s", *roleOutput.Role.RoleName)
+					if !continueOnError {
+						return err
+					}
+					errs = append(errs, err)
+					break
 				}
 
 				_, err := client.DeleteRole(&iam.DeleteRoleInput{
 					RoleName: roleOutput.Role.RoleName,
 				})
+				provisioning.AuditLogCall("iam:DeleteRole", *roleOutput.Role.RoleName, err)
 				if err != nil {
-					return errors.Wrapf(err, "failed to delete IAM Role %s", *roleOutput.Role.RoleName)
+					err = errors.Wrapf(err, "failed to delete IAM Role %s", *roleOutput.Role.RoleName)
+					if !continueOnError {
+						return err
+					}
+					errs = append(errs, err)
+					break
 				}
 				log.Printf("IAM Role %s deleted", *roleOutput.Role.RoleName)
 				break
@@ -118,14 +174,21 @@ func deleteIAMRoles(client aws.Client, namePrefix string, paginationMarker *stri
 	}
 
 	if *roleList.IsTruncated {
-		return deleteIAMRoles(client, namePrefix, roleList.Marker)
+		if err := deleteIAMRoles(client, namePrefix, iamPath, roleList.Marker, continueOnError); err != nil {
+			if !continueOnError {
+				return err
+			}
+			errs = append(errs, err)
+		}
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
 }
 
-// deleteRolePolicies deletes the Polices associated with IAM Role created by ccoctl
-func deleteRolePolicies(client aws.Client, roleName string) error {
+// deleteRolePolicies deletes the Polices associated with IAM Role created by ccoctl. If
+// continueOnError is true, a failure to delete one policy does not stop the rest from being
+// attempted; all errors are returned together at the end.
+func deleteRolePolicies(client aws.Client, roleName string, continueOnError bool) error {
 	policies, err := client.ListRolePolicies(&iam.ListRolePoliciesInput{
 		RoleName: awssdk.String(roleName),
 	})
@@ -133,18 +196,113 @@ func deleteRolePolicies(client aws.Client, roleName string) error {
 		return errors.Wrapf(err, "failed to fetch a list of policies associated with IAM role %s", roleName)
 	}
 
+	var errs []error
 	for _, policyName := range policies.PolicyNames {
 		_, err := client.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
 			RoleName:   awssdk.String(roleName),
 			PolicyName: policyName,
 		})
+		provisioning.AuditLogCall("iam:DeleteRolePolicy", fmt.Sprintf("%s/%s", roleName, *policyName), err)
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete policy %s associated with IAM Role %s", *policyName, roleName)
+			err = errors.Wrapf(err, "failed to delete policy %s associated with IAM Role %s", *policyName, roleName)
+			if !continueOnError {
+				return err
+			}
+			errs = append(errs, err)
+			continue
 		}
 		log.Printf("Policy %s associated with IAM Role %s deleted", *policyName, roleName)
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
+}
+
+// maxDeleteVerificationAttempts bounds how many times verifyRolesDeleted retries deleting
+// roles that unexpectedly still exist after the initial delete pass, e.g. because dependency
+// ordering meant a policy detach hadn't propagated yet when the role delete was attempted.
+const maxDeleteVerificationAttempts = 3
+
+// listTaggedRoleNames returns the names of all IAM roles tagged as owned by namePrefix,
+// walking pagination itself so callers get a single flat list.
+func listTaggedRoleNames(client aws.Client, namePrefix, iamPath string, paginationMarker *string) ([]string, error) {
+	roleList, err := client.ListRoles(&iam.ListRolesInput{
+		Marker:     paginationMarker,
+		PathPrefix: pathPrefixOrNil(iamPath),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch a list of IAM roles, pagination marker: %v", paginationMarker)
+	}
+
+	var names []string
+	for _, roleMetadata := range roleList.Roles {
+		roleOutput, err := client.GetRole(&iam.GetRoleInput{
+			RoleName: roleMetadata.RoleName,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch IAM role %s", *roleMetadata.RoleName)
+		}
+		for _, tag := range roleOutput.Role.Tags {
+			if *tag.Key == fmt.Sprintf("%s/%s", ccoctlAWSResourceTagKeyPrefix, namePrefix) {
+				names = append(names, *roleOutput.Role.RoleName)
+				break
+			}
+		}
+	}
+
+	if *roleList.IsTruncated {
+		more, err := listTaggedRoleNames(client, namePrefix, iamPath, roleList.Marker)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, more...)
+	}
+
+	return names, nil
+}
+
+// pathPrefixOrNil returns iamPath as a *string for use as an iam.ListRolesInput.PathPrefix, or nil
+// when iamPath is empty so ListRoles keeps scanning every path (ccoctl's original, --iam-path-less
+// behavior) instead of being scoped to a prefix of "".
+func pathPrefixOrNil(iamPath string) *string {
+	if iamPath == "" {
+		return nil
+	}
+	return awssdk.String(iamPath)
+}
+
+// verifyRolesDeleted re-queries for any IAM roles tagged as owned by namePrefix that should
+// have already been removed by deleteIAMRoles, and retries their deletion (detaching any
+// policies again first) up to maxDeleteVerificationAttempts times. This covers the case where a
+// role's policies weren't fully detached before the role delete was attempted. Roles that are
+// still present after all attempts are reported in the returned error rather than silently
+// dropped, so the caller knows cleanup is incomplete.
+func verifyRolesDeleted(client aws.Client, namePrefix, iamPath string, continueOnError bool) error {
+	var remaining []string
+	for attempt := 1; attempt <= maxDeleteVerificationAttempts; attempt++ {
+		var err error
+		remaining, err = listTaggedRoleNames(client, namePrefix, iamPath, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to verify IAM role deletion")
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		log.Printf("verification pass %d/%d: %d IAM role(s) still present after delete, retrying: %v", attempt, maxDeleteVerificationAttempts, len(remaining), remaining)
+		if err := deleteIAMRoles(client, namePrefix, iamPath, nil, continueOnError); err != nil && !continueOnError {
+			return err
+		}
+	}
+
+	var err error
+	remaining, err = listTaggedRoleNames(client, namePrefix, iamPath, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify IAM role deletion")
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+	return errors.Errorf("the following IAM role(s) still exist after %d deletion attempts and may need to be removed manually: %v", maxDeleteVerificationAttempts, remaining)
 }
 
 // deleteIAMIdentityProvider deletes the IAM Identity Provider
@@ -164,6 +322,7 @@ func deleteIAMIdentityProvider(client aws.Client, namePrefix string) error {
 			_, err := client.DeleteOpenIDConnectProvider(&iam.DeleteOpenIDConnectProviderInput{
 				OpenIDConnectProviderArn: awssdk.String(*provider.Arn),
 			})
+			provisioning.AuditLogCall("iam:DeleteOpenIDConnectProvider", *provider.Arn, err)
 			if err != nil {
 				return errors.Wrapf(err, "failed to delete Identity Provider with ARN %s", *provider.Arn)
 			}
@@ -175,8 +334,60 @@ func deleteIAMIdentityProvider(client aws.Client, namePrefix string) error {
 	return nil
 }
 
+// confirmDeletion guards against fat-fingered destroys: unless --yes was passed, it lists the
+// resource types about to be deleted and requires the operator to type back --name to proceed. If
+// stdin isn't a terminal and --yes wasn't passed, it refuses rather than hanging on a read that will
+// never be answered (e.g. when run from a script or CI job that forgot the flag).
+func confirmDeletion(name string, resourceTypes []string, yes bool) error {
+	if yes {
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return errors.New("refusing to delete without --yes: stdin is not a terminal to prompt for confirmation")
+	}
+
+	fmt.Printf("This will delete the following AWS resource type(s) for %q: %v\n", name, resourceTypes)
+	fmt.Printf("Type the resource name (%q) to confirm deletion: ", name)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return errors.Wrap(err, "failed to read confirmation input")
+	}
+
+	if strings.TrimSpace(response) != name {
+		return errors.New("confirmation did not match --name, aborting deletion")
+	}
+
+	return nil
+}
+
+func initEnvForDeleteCmd(cmd *cobra.Command, args []string) {
+	if err := provisioning.InitAuditLog(DeleteOpts.AuditLogPath); err != nil {
+		log.Fatalf("failed to open audit log: %s", err)
+	}
+}
+
 func deleteCmd(cmd *cobra.Command, args []string) {
-	s, err := awsSession(DeleteOpts.Region)
+	resourceTypes := DeleteOpts.ResourceTypes
+	if len(resourceTypes) == 0 {
+		resourceTypes = allResourceTypes
+	}
+	selected := sets.NewString(resourceTypes...)
+	if unknown := selected.Difference(sets.NewString(allResourceTypes...)); unknown.Len() > 0 {
+		log.Fatalf("unknown --resource-types value(s): %v (must be one of %v)", unknown.List(), allResourceTypes)
+	}
+
+	if err := validateIAMPath(DeleteOpts.IAMPath); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := confirmDeletion(DeleteOpts.Name, resourceTypes, DeleteOpts.Yes); err != nil {
+		log.Fatal(err)
+	}
+
+	s, err := awsSession(DeleteOpts.Region, DeleteOpts.Profile)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -184,36 +395,86 @@ func deleteCmd(cmd *cobra.Command, args []string) {
 	awsClient := aws.NewClientFromSession(s)
 	bucketName := fmt.Sprintf("%s-oidc", DeleteOpts.Name)
 
-	if err := deleteOIDCObjectsFromBucket(awsClient, bucketName, DeleteOpts.Name); err != nil {
-		log.Print(err)
+	var errs []error
+
+	if selected.Has(resourceTypeBucket) {
+		if err := deleteOIDCObjectsFromBucket(awsClient, bucketName, DeleteOpts.Name, DeleteOpts.ContinueOnError); err != nil {
+			log.Print(err)
+			errs = append(errs, err)
+		}
+
+		if err := deleteOIDCBucket(awsClient, bucketName, DeleteOpts.Name); err != nil {
+			log.Print(err)
+			errs = append(errs, err)
+		}
 	}
 
-	if err := deleteOIDCBucket(awsClient, bucketName, DeleteOpts.Name); err != nil {
-		log.Print(err)
+	if selected.Has(resourceTypeRoles) {
+		if err := deleteIAMRoles(awsClient, DeleteOpts.Name, DeleteOpts.IAMPath, nil, DeleteOpts.ContinueOnError); err != nil {
+			log.Print(err)
+			errs = append(errs, err)
+		}
+		if err := verifyRolesDeleted(awsClient, DeleteOpts.Name, DeleteOpts.IAMPath, DeleteOpts.ContinueOnError); err != nil {
+			log.Print(err)
+			errs = append(errs, err)
+		}
 	}
 
-	if err := deleteIAMRoles(awsClient, DeleteOpts.Name, nil); err != nil {
-		log.Print(err)
+	if selected.Has(resourceTypeOIDC) {
+		if err := deleteIAMIdentityProvider(awsClient, DeleteOpts.Name); err != nil {
+			log.Print(err)
+			errs = append(errs, err)
+		}
 	}
 
-	if err := deleteIAMIdentityProvider(awsClient, DeleteOpts.Name); err != nil {
-		log.Print(err)
+	if selected.Has(resourceTypeBucket) && DeleteOpts.OIDCBucketReplicaRegion != "" {
+		replicaSession, err := awsSession(DeleteOpts.OIDCBucketReplicaRegion, DeleteOpts.Profile)
+		if err != nil {
+			log.Print(errors.Wrap(err, "failed to create AWS session for the replica region"))
+			errs = append(errs, err)
+		} else {
+			replicaClient := aws.NewClientFromSession(replicaSession)
+			replicaBucketName := bucketName + "-replica"
+
+			if err := deleteOIDCObjectsFromBucket(replicaClient, replicaBucketName, DeleteOpts.Name, DeleteOpts.ContinueOnError); err != nil {
+				log.Print(err)
+				errs = append(errs, err)
+			}
+
+			if err := deleteOIDCBucket(replicaClient, replicaBucketName, DeleteOpts.Name); err != nil {
+				log.Print(err)
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if DeleteOpts.ContinueOnError {
+		if err := utilerrors.NewAggregate(errs); err != nil {
+			os.Exit(1)
+		}
 	}
 }
 
 // NewDeleteCmd implements the "delete" command for the credentials provisioning
 func NewDeleteCmd() *cobra.Command {
 	deleteCmd := &cobra.Command{
-		Use:   "delete",
-		Short: "Delete credentials objects",
-		Long:  "Deleting objects related to cloud credentials",
-		Run:   deleteCmd,
+		Use:              "delete",
+		Short:            "Delete credentials objects",
+		Long:             "Deleting objects related to cloud credentials",
+		Run:              deleteCmd,
+		PersistentPreRun: initEnvForDeleteCmd,
 	}
 
 	deleteCmd.PersistentFlags().StringVar(&DeleteOpts.Name, "name", "", "User-defined name for all created AWS resources (can be separate from the cluster's infra-id)")
 	deleteCmd.MarkPersistentFlagRequired("name")
 	deleteCmd.PersistentFlags().StringVar(&DeleteOpts.Region, "region", "", "AWS region where the resources were created")
 	deleteCmd.MarkPersistentFlagRequired("region")
+	deleteCmd.PersistentFlags().BoolVar(&DeleteOpts.ContinueOnError, "continue-on-error", false, "Continue deleting remaining resources when a failure is encountered, reporting all failures at the end")
+	deleteCmd.PersistentFlags().StringVar(&DeleteOpts.OIDCBucketReplicaRegion, "oidc-bucket-replica-region", "", "AWS region the OIDC S3 bucket was replicated into via --oidc-bucket-replica-region of create-identity-provider, so its replica bucket is also deleted")
+	deleteCmd.PersistentFlags().StringSliceVar(&DeleteOpts.ResourceTypes, "resource-types", nil, fmt.Sprintf("Comma-separated list of resource types to delete, instead of all of them. One or more of: %v. Defaults to all of them", allResourceTypes))
+	deleteCmd.PersistentFlags().BoolVar(&DeleteOpts.Yes, "yes", false, "Skip the interactive confirmation prompt and delete immediately. Required when stdin is not a terminal (e.g. scripted/CI use)")
+	deleteCmd.PersistentFlags().StringVar(&DeleteOpts.IAMPath, "iam-path", "", "IAM path (e.g. /openshift/mycluster/) the roles were created under via --iam-path of create-iam-roles, so deletion scans that path instead of every role in the account")
+	deleteCmd.PersistentFlags().StringVar(&DeleteOpts.AuditLogPath, "audit-log", "", "Path to a JSONL file to append an audit record (timestamp, operation, target, result) to for every mutating AWS API call made")
 
 	return deleteCmd
 }
@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/cloud-credential-operator/pkg/aws"
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
+)
+
+var (
+	// DetectModeOpts captures the options that affect mode detection
+	DetectModeOpts = options{
+		Region: "us-east-1",
+	}
+)
+
+// NewDetectModeCmd provides the "detect-mode" subcommand
+func NewDetectModeCmd() *cobra.Command {
+	detectModeCmd := &cobra.Command{
+		Use:   "detect-mode",
+		Short: "Detect which CCO mode the provided AWS credentials support",
+		Long:  "Probes the AWS credentials found in the environment (AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY or a shared credentials file) to report whether they are sufficient for mint, passthrough, or only manual mode, using the same permission checks the operator performs.",
+		Run:   detectModeCmd,
+	}
+
+	detectModeCmd.PersistentFlags().StringVar(&DetectModeOpts.Region, "region", "us-east-1", "AWS region used when simulating region-specific permissions")
+
+	return detectModeCmd
+}
+
+func detectModeCmd(cmd *cobra.Command, args []string) {
+	s, err := awsSession(DetectModeOpts.Region, DetectModeOpts.Profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	awsClient := aws.NewClientFromSession(s)
+
+	report, err := aws.DetectMode(awsClient, &aws.SimulateParams{Region: DetectModeOpts.Region}, log.StandardLogger())
+	if err != nil {
+		if isAWSAuthError(err) {
+			provisioning.Fatal(provisioning.NewAuthFailureError(err))
+		}
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Detected mode: %s\n", report.Mode)
+	if len(report.MissingMintActions) != 0 {
+		fmt.Println("Missing permissions for mint mode:")
+		for _, action := range report.MissingMintActions {
+			fmt.Printf("  %s\n", action)
+		}
+	}
+	if len(report.MissingPassthroughActions) != 0 {
+		fmt.Println("Missing permissions for passthrough mode:")
+		for _, action := range report.MissingPassthroughActions {
+			fmt.Printf("  %s\n", action)
+		}
+	}
+}
+
+// isAWSAuthError reports whether err is an AWS API error indicating the configured credentials
+// were rejected or lack permission to simulate the policies, as opposed to a transient failure or
+// local misconfiguration (e.g. a bad --region).
+func isAWSAuthError(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	switch awsErr.Code() {
+	case "AccessDenied", "AuthFailure", "InvalidClientTokenId", "UnrecognizedClientException", "ExpiredToken":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAWSAuthError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "AccessDenied is an auth error",
+			err:      awserr.New("AccessDenied", "not authorized", nil),
+			expected: true,
+		},
+		{
+			name:     "InvalidClientTokenId is an auth error",
+			err:      awserr.New("InvalidClientTokenId", "invalid access key", nil),
+			expected: true,
+		},
+		{
+			name:     "ExpiredToken is an auth error",
+			err:      awserr.New("ExpiredToken", "token expired", nil),
+			expected: true,
+		},
+		{
+			name:     "wrapped auth error is still detected",
+			err:      fmt.Errorf("while simulating policy: %w", awserr.New("AccessDenied", "not authorized", nil)),
+			expected: true,
+		},
+		{
+			name:     "an unrelated AWS error code is not an auth error",
+			err:      awserr.New("Throttling", "rate exceeded", nil),
+			expected: false,
+		},
+		{
+			name:     "a non-AWS error is not an auth error",
+			err:      errors.New("dial tcp: lookup iam.amazonaws.com: no such host"),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isAWSAuthError(test.err))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+	"github.com/openshift/cloud-credential-operator/pkg/aws"
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
+	"github.com/openshift/cloud-credential-operator/pkg/operator/constants"
+)
+
+var (
+	// DiagnoseOpts captures the options that affect diagnosing a CredentialsRequest
+	DiagnoseOpts = options{
+		Region: "us-east-1",
+	}
+
+	roleARNPattern = regexp.MustCompile(`(?m)^\s*role_arn\s*=\s*(\S+)\s*$`)
+)
+
+// NewDiagnoseCmd provides the "diagnose" subcommand
+func NewDiagnoseCmd() *cobra.Command {
+	diagnoseCmd := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Diagnose why a CredentialsRequest is not provisioned",
+		Long:  "Correlates a CredentialsRequest's status conditions, its target Secret, and (with AWS credentials available) the IAM role the Secret points at, to print an actionable diagnosis instead of requiring an SRE to check all three by hand.",
+		Run:   diagnoseCmd,
+	}
+
+	diagnoseCmd.PersistentFlags().StringVar(&DiagnoseOpts.Name, "credentials-request-name", "", "Name of the CredentialsRequest to diagnose")
+	diagnoseCmd.MarkPersistentFlagRequired("credentials-request-name")
+	diagnoseCmd.PersistentFlags().StringVar(&DiagnoseOpts.Namespace, "namespace", "", "Namespace of the CredentialsRequest to diagnose")
+	diagnoseCmd.MarkPersistentFlagRequired("namespace")
+	diagnoseCmd.PersistentFlags().StringVar(&DiagnoseOpts.KubeConfigFile, "kubeconfig", "", "Path to the kubeconfig file to use (defaults to the standard kubeconfig loading rules)")
+	diagnoseCmd.PersistentFlags().StringVar(&DiagnoseOpts.Region, "region", "us-east-1", "AWS region used when probing IAM (IAM itself is global, but the SDK still requires one)")
+
+	return diagnoseCmd
+}
+
+func diagnoseCmd(cmd *cobra.Command, args []string) {
+	cr, secret, err := provisioning.GetCredentialsRequestFromCluster(DiagnoseOpts.KubeConfigFile, DiagnoseOpts.Namespace, DiagnoseOpts.Name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("CredentialsRequest %s/%s\n", cr.Namespace, cr.Name)
+
+	if len(cr.Status.Conditions) == 0 {
+		fmt.Println("  no status conditions reported yet")
+	}
+	for _, cond := range cr.Status.Conditions {
+		if cond.Status == "True" && cond.Type != credreqv1.StaleCredentials {
+			fmt.Printf("  condition %s=True (%s): %s\n", cond.Type, cond.Reason, cond.Message)
+		}
+	}
+
+	if secret == nil {
+		fmt.Printf("  DIAGNOSIS: target Secret %s/%s does not exist yet\n", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+		return
+	}
+	fmt.Printf("  target Secret %s/%s exists\n", secret.Namespace, secret.Name)
+
+	credentialsValue, ok := secret.Data[constants.AWSSecretDataCredentialsKey]
+	if !ok {
+		fmt.Printf("  DIAGNOSIS: Secret is missing its %q key\n", constants.AWSSecretDataCredentialsKey)
+		return
+	}
+
+	match := roleARNPattern.FindSubmatch(credentialsValue)
+	if match == nil {
+		fmt.Println("  Secret holds long-lived access key credentials, not an assumed role; skipping IAM role probe")
+		return
+	}
+	roleARN := string(match[1])
+	fmt.Printf("  Secret references IAM role: %s\n", roleARN)
+
+	s, err := awsSession(DiagnoseOpts.Region, DiagnoseOpts.Profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	awsClient := aws.NewClientFromSession(s)
+
+	_, err = awsClient.GetRole(&iam.GetRoleInput{RoleName: roleNameFromARN(roleARN)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			fmt.Println("  DIAGNOSIS: the IAM role no longer exists in AWS; re-run 'ccoctl aws create-iam-roles' to recreate it")
+			return
+		}
+		fmt.Printf("  DIAGNOSIS: unable to verify the IAM role (%v); check that the credentials used here can call iam:GetRole\n", err)
+		return
+	}
+
+	fmt.Println("  IAM role exists; if the CredentialsRequest still isn't working, check the role's trust policy and attached permissions")
+}
+
+// roleNameFromARN extracts the role name from an IAM role ARN (arn:aws:iam::<account>:role/<name>).
+func roleNameFromARN(roleARN string) *string {
+	name := roleARN
+	if idx := strings.LastIndex(roleARN, "/"); idx != -1 {
+		name = roleARN[idx+1:]
+	}
+	return &name
+}
@@ -0,0 +1,222 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+	"github.com/openshift/cloud-credential-operator/pkg/aws"
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
+)
+
+var (
+	// DiffOpts captures the options that affect diffing CredentialsRequests against live AWS state
+	DiffOpts = options{}
+)
+
+// NewDiffCmd provides the "diff" subcommand
+func NewDiffCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare desired IAM role state against what currently exists in AWS",
+		Long:  "For every CredentialsRequest in --credentials-requests-dir, computes the trust policy and permissions policy 'create-iam-roles' would produce and compares it against the role currently in AWS, printing a unified diff per CredentialsRequest. Read-only: makes no IAM API calls other than GetOpenIDConnectProvider/GetRole/GetRolePolicy, and writes nothing. Useful for drift audits, to detect manual tampering or roles that predate a CredentialsRequest change.",
+		Run:   diffCmd,
+	}
+
+	diffCmd.PersistentFlags().StringVar(&DiffOpts.Name, "name", "", "User-defined name used when the IAM roles were created (must match the --name passed to 'create-iam-roles')")
+	diffCmd.MarkPersistentFlagRequired("name")
+	diffCmd.PersistentFlags().StringVar(&DiffOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to diff. Accepts a comma-separated list of directories to merge")
+	diffCmd.MarkPersistentFlagRequired("credentials-requests-dir")
+	diffCmd.PersistentFlags().StringVar(&DiffOpts.IdentityProviderARN, "identity-provider-arn", "", "ARN of the IAM Identity provider the roles were created to trust")
+	diffCmd.MarkPersistentFlagRequired("identity-provider-arn")
+	diffCmd.PersistentFlags().StringVar(&DiffOpts.Region, "region", "", "AWS region endpoint only required for GovCloud")
+	diffCmd.PersistentFlags().StringVar(&DiffOpts.Profile, "aws-profile", "", "Name of an AWS profile from the shared credentials/config files to use, overriding the default credential chain")
+	diffCmd.PersistentFlags().BoolVar(&DiffOpts.EnableTechPreview, "enable-tech-preview", false, "Also diff CredentialsRequests annotated as tech-preview only")
+
+	return diffCmd
+}
+
+// roleDiff is one CredentialsRequest's comparison result between desired and actual IAM role state.
+type roleDiff struct {
+	credReqName string
+	roleName    string
+	missing     bool
+	trustDiff   string
+	policyDiff  string
+}
+
+func (d roleDiff) isDrifted() bool {
+	return d.missing || d.trustDiff != "" || d.policyDiff != ""
+}
+
+// diffRole computes the desired trust policy and permissions policy for credReq the same way
+// createRole does, fetches the role's actual state from AWS, and returns a structured diff.
+func diffRole(awsClient aws.Client, name, issuerURL, identityProviderARN string, credReq *credreqv1.CredentialsRequest) (*roleDiff, error) {
+	codec, err := credreqv1.NewCodec()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create credReq codec")
+	}
+
+	awsProviderSpec := credreqv1.AWSProviderSpec{}
+	if err := codec.DecodeProviderSpec(credReq.Spec.ProviderSpec, &awsProviderSpec); err != nil {
+		return nil, errors.Wrapf(err, "%s/%s: failed to decode providerSpec", credReq.Namespace, credReq.Name)
+	}
+
+	roleName := fmt.Sprintf("%s-%s-%s", name, credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name)
+	shortenedRoleName, _, err := provisioning.SanitizeResourceName("aws", "iam-role", roleName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s/%s: failed to sanitize role name", credReq.Namespace, credReq.Name)
+	}
+
+	desiredTrustPolicy, err := createRolePolicyDocument(identityProviderARN, issuerURL, credReq.Spec.SecretRef.Namespace, credReq.Spec.ServiceAccountNames, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s/%s: failed to compute desired trust policy", credReq.Namespace, credReq.Name)
+	}
+	desiredPermissionsPolicy := createRolePolicy(awsProviderSpec.StatementEntries)
+
+	result := &roleDiff{
+		credReqName: credReq.Name,
+		roleName:    shortenedRoleName,
+	}
+
+	outRole, err := awsClient.GetRole(&iam.GetRoleInput{RoleName: awssdk.String(shortenedRoleName)})
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			result.missing = true
+			return result, nil
+		}
+		return nil, errors.Wrapf(err, "%s/%s: failed to get role %s", credReq.Namespace, credReq.Name, shortenedRoleName)
+	}
+
+	actualTrustPolicy, err := url.QueryUnescape(awssdk.StringValue(outRole.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s/%s: failed to decode actual trust policy", credReq.Namespace, credReq.Name)
+	}
+	if trustDiff, err := jsonUnifiedDiff("desired-trust-policy", "actual-trust-policy", desiredTrustPolicy, actualTrustPolicy); err != nil {
+		return nil, errors.Wrapf(err, "%s/%s: failed to diff trust policy", credReq.Namespace, credReq.Name)
+	} else {
+		result.trustDiff = trustDiff
+	}
+
+	outRolePolicy, err := awsClient.GetRolePolicy(&iam.GetRolePolicyInput{
+		RoleName:   awssdk.String(shortenedRoleName),
+		PolicyName: awssdk.String(shortenedRoleName),
+	})
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			result.policyDiff = "permissions policy does not exist in AWS"
+			return result, nil
+		}
+		return nil, errors.Wrapf(err, "%s/%s: failed to get role policy for %s", credReq.Namespace, credReq.Name, shortenedRoleName)
+	}
+	actualPermissionsPolicy, err := url.QueryUnescape(awssdk.StringValue(outRolePolicy.PolicyDocument))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s/%s: failed to decode actual permissions policy", credReq.Namespace, credReq.Name)
+	}
+	if policyDiff, err := jsonUnifiedDiff("desired-permissions-policy", "actual-permissions-policy", desiredPermissionsPolicy, actualPermissionsPolicy); err != nil {
+		return nil, errors.Wrapf(err, "%s/%s: failed to diff permissions policy", credReq.Namespace, credReq.Name)
+	} else {
+		result.policyDiff = policyDiff
+	}
+
+	return result, nil
+}
+
+// jsonUnifiedDiff re-serializes both JSON documents with consistent indentation before diffing, so
+// the comparison reflects meaningful structural differences instead of incidental whitespace or key
+// ordering introduced by AWS round-tripping the policy through its own storage.
+func jsonUnifiedDiff(desiredLabel, actualLabel, desired, actual string) (string, error) {
+	desiredNormalized, err := normalizeJSON(desired)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to normalize desired policy")
+	}
+	actualNormalized, err := normalizeJSON(actual)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to normalize actual policy")
+	}
+	if desiredNormalized == actualNormalized {
+		return "", nil
+	}
+
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(desiredNormalized),
+		B:        difflib.SplitLines(actualNormalized),
+		FromFile: desiredLabel,
+		ToFile:   actualLabel,
+		Context:  3,
+	}
+	return difflib.GetUnifiedDiffString(diff)
+}
+
+func normalizeJSON(doc string) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+func diffCmd(cmd *cobra.Command, args []string) {
+	credReqs, err := provisioning.GetListOfCredentialsRequests(DiffOpts.CredRequestDir, DiffOpts.EnableTechPreview, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s, err := awsSession(DiffOpts.Region, DiffOpts.Profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	awsClient := aws.NewClientFromSession(s)
+
+	issuerURL, err := getIssuerURLFromIdentityProvider(awsClient, DiffOpts.IdentityProviderARN)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	drifted := 0
+	for _, credReq := range credReqs {
+		result, err := diffRole(awsClient, DiffOpts.Name, issuerURL, DiffOpts.IdentityProviderARN, credReq)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if !result.isDrifted() {
+			fmt.Printf("%s: role %s matches desired state\n", credReq.Name, result.roleName)
+			continue
+		}
+
+		drifted++
+		if result.missing {
+			fmt.Printf("%s: role %s does not exist in AWS\n", credReq.Name, result.roleName)
+			continue
+		}
+		fmt.Printf("%s: role %s has drifted\n", credReq.Name, result.roleName)
+		if result.trustDiff != "" {
+			fmt.Print(result.trustDiff)
+		}
+		if result.policyDiff != "" {
+			fmt.Println(result.policyDiff)
+		}
+	}
+
+	if drifted > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d CredentialsRequest(s) have drifted from their desired IAM role state\n", drifted, len(credReqs))
+		os.Exit(1)
+	}
+}
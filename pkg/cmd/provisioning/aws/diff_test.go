@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONUnifiedDiffNoDrift(t *testing.T) {
+	desired := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	actual := `{
+		"Statement": [ { "Resource": "*", "Action": "s3:GetObject", "Effect": "Allow" } ],
+		"Version": "2012-10-17"
+	}`
+
+	diff, err := jsonUnifiedDiff("desired", "actual", desired, actual)
+	require.NoError(t, err)
+	assert.Empty(t, diff, "semantically identical policies (different key order/whitespace) should not diff")
+}
+
+func TestJSONUnifiedDiffDetectsDrift(t *testing.T) {
+	desired := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	actual := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"*"}]}`
+
+	diff, err := jsonUnifiedDiff("desired", "actual", desired, actual)
+	require.NoError(t, err)
+	assert.NotEmpty(t, diff)
+	assert.Contains(t, diff, "s3:PutObject")
+}
+
+func TestJSONUnifiedDiffInvalidJSON(t *testing.T) {
+	_, err := jsonUnifiedDiff("desired", "actual", "not json", `{}`)
+	assert.Error(t, err)
+}
+
+func TestRoleDiffIsDrifted(t *testing.T) {
+	assert.False(t, roleDiff{}.isDrifted())
+	assert.True(t, roleDiff{missing: true}.isDrifted())
+	assert.True(t, roleDiff{trustDiff: "some diff"}.isDrifted())
+	assert.True(t, roleDiff{policyDiff: "some diff"}.isDrifted())
+}
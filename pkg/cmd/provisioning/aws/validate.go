@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
+)
+
+var (
+	// ValidateOpts captures the options that affect validating CredentialsRequests
+	ValidateOpts = options{}
+)
+
+// NewValidateCmd provides the "validate" subcommand
+func NewValidateCmd() *cobra.Command {
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Structurally validate CredentialsRequests without cloud access",
+		Long:  "Parses every CredentialsRequest in --credentials-requests-dir and checks that it is well-formed (correct kind, required fields, a valid secretRef, and a syntactically valid IAM policy) and reports every problem found. Unlike 'create-iam-roles --dry-run', this makes no AWS API calls and writes nothing to disk, so it can run as a fast, credential-free CI gate over manifest PRs.",
+		Run:   validateCmd,
+	}
+
+	validateCmd.PersistentFlags().StringVar(&ValidateOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to validate. Accepts a comma-separated list of directories to merge")
+	validateCmd.MarkPersistentFlagRequired("credentials-requests-dir")
+	validateCmd.PersistentFlags().BoolVar(&ValidateOpts.EnableTechPreview, "enable-tech-preview", false, "Also validate CredentialsRequests annotated as tech-preview only")
+
+	return validateCmd
+}
+
+// validateAWSCredentialsRequest checks the fields of credReq that createRole would otherwise fail
+// on partway through a live run, without calling AWS or writing anything to disk.
+func validateAWSCredentialsRequest(credReq *credreqv1.CredentialsRequest) []error {
+	var problems []error
+
+	if credReq.Spec.SecretRef.Namespace == "" {
+		problems = append(problems, fmt.Errorf("%s/%s: secretRef.namespace is required", credReq.Namespace, credReq.Name))
+	}
+	if credReq.Spec.SecretRef.Name == "" {
+		problems = append(problems, fmt.Errorf("%s/%s: secretRef.name is required", credReq.Namespace, credReq.Name))
+	}
+
+	codec, err := credreqv1.NewCodec()
+	if err != nil {
+		problems = append(problems, errors.Wrap(err, "failed to create credReq codec"))
+		return problems
+	}
+
+	awsProviderSpec := credreqv1.AWSProviderSpec{}
+	if err := codec.DecodeProviderSpec(credReq.Spec.ProviderSpec, &awsProviderSpec); err != nil {
+		problems = append(problems, fmt.Errorf("%s/%s: failed to decode providerSpec: %v", credReq.Namespace, credReq.Name, err))
+		return problems
+	}
+
+	if awsProviderSpec.Kind != "AWSProviderSpec" {
+		problems = append(problems, fmt.Errorf("%s/%s: providerSpec.kind %q is not AWSProviderSpec", credReq.Namespace, credReq.Name, awsProviderSpec.Kind))
+		return problems
+	}
+
+	if len(awsProviderSpec.StatementEntries) == 0 {
+		problems = append(problems, fmt.Errorf("%s/%s: providerSpec.statementEntries is empty", credReq.Namespace, credReq.Name))
+		return problems
+	}
+
+	// createRolePolicy only marshals the statements to JSON; it makes no cloud calls, so it is
+	// safe to call here purely to confirm the statements produce a syntactically valid policy.
+	if createRolePolicy(awsProviderSpec.StatementEntries) == "" {
+		problems = append(problems, fmt.Errorf("%s/%s: providerSpec produced an empty IAM policy document", credReq.Namespace, credReq.Name))
+	}
+
+	return problems
+}
+
+func validateCmd(cmd *cobra.Command, args []string) {
+	credReqs, err := provisioning.GetListOfCredentialsRequests(ValidateOpts.CredRequestDir, ValidateOpts.EnableTechPreview, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var problems []error
+	for _, credReq := range credReqs {
+		problems = append(problems, validateAWSCredentialsRequest(credReq)...)
+	}
+
+	if len(problems) == 0 {
+		fmt.Printf("all %d CredentialsRequests are valid\n", len(credReqs))
+		return
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, problem)
+	}
+	provisioning.Fatal(provisioning.NewValidationError(fmt.Errorf("%d CredentialsRequest(s) failed validation", len(problems))))
+}
@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
+)
+
+func TestValidateAWSCredentialsRequest(t *testing.T) {
+	tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tempDirName)
+
+	err = testCredentialsRequest(t, "validcredreq", "namespace1", "secretName1", tempDirName, false)
+	require.NoError(t, err, "errored while setting up test CredReq files")
+
+	credReqs, err := provisioning.GetListOfCredentialsRequests(tempDirName, false, true)
+	require.NoError(t, err, "unexpected error reading CredReqs")
+	require.Len(t, credReqs, 1)
+
+	problems := validateAWSCredentialsRequest(credReqs[0])
+	assert.Empty(t, problems, "expected a well-formed CredentialsRequest to have no validation problems")
+}
+
+func TestValidateAWSCredentialsRequestMissingSecretRef(t *testing.T) {
+	credReq := &credreqv1.CredentialsRequest{}
+	credReq.Name = "badcredreq"
+	credReq.Namespace = "openshift-cloud-credential-operator"
+	codec, err := credreqv1.NewCodec()
+	require.NoError(t, err)
+	providerSpec, err := codec.EncodeProviderSpec(&credreqv1.AWSProviderSpec{
+		StatementEntries: []credreqv1.StatementEntry{
+			{
+				Effect:   "Allow",
+				Action:   []string{"ec2:DescribeInstances"},
+				Resource: "*",
+			},
+		},
+	})
+	require.NoError(t, err)
+	credReq.Spec.ProviderSpec = providerSpec
+
+	problems := validateAWSCredentialsRequest(credReq)
+	require.Len(t, problems, 2, "expected a problem for each missing secretRef field")
+	assert.Contains(t, problems[0].Error(), "secretRef.namespace is required")
+	assert.Contains(t, problems[1].Error(), "secretRef.name is required")
+}
+
+func TestValidateAWSCredentialsRequestEmptyStatementEntries(t *testing.T) {
+	credReq := &credreqv1.CredentialsRequest{}
+	credReq.Name = "emptystatements"
+	credReq.Namespace = "openshift-cloud-credential-operator"
+	credReq.Spec.SecretRef.Namespace = "namespace1"
+	credReq.Spec.SecretRef.Name = "secretName1"
+	codec, err := credreqv1.NewCodec()
+	require.NoError(t, err)
+	providerSpec, err := codec.EncodeProviderSpec(&credreqv1.AWSProviderSpec{})
+	require.NoError(t, err)
+	credReq.Spec.ProviderSpec = providerSpec
+
+	problems := validateAWSCredentialsRequest(credReq)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Error(), "statementEntries is empty")
+}
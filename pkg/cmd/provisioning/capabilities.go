@@ -0,0 +1,139 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"text/tabwriter"
+
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// ProviderCapabilities describes, for a single cloud provider, what ccoctl can do for it: which
+// CredentialsRequest modes its actuator supports, which ccoctl subcommands exist for it, and any
+// notable flags operators frequently need to know about when scripting against it. This is
+// hand-maintained alongside the provider packages (pkg/cmd/provisioning/<provider>) rather than
+// introspected from cobra command metadata, since mode support (mint/passthrough/manual/STS) is an
+// actuator-level property, not something recorded on the command tree itself.
+type ProviderCapabilities struct {
+	Provider     string   `json:"provider"`
+	Modes        []string `json:"modes"`
+	Commands     []string `json:"commands"`
+	NotableFlags []string `json:"notableFlags"`
+}
+
+// capabilitiesRegistry is the source of truth for "ccoctl capabilities". Keep it up to date when
+// adding or removing a provisioning subcommand or actuator mode.
+var capabilitiesRegistry = []ProviderCapabilities{
+	{
+		Provider: "aws",
+		Modes:    []string{"mint", "passthrough", "manual", "sts"},
+		Commands: []string{"create-key-pair", "create-identity-provider", "create-iam-roles", "create-all", "delete", "detect-mode", "diagnose", "validate", "diff"},
+		NotableFlags: []string{
+			"--resource-types (delete: select a subset of resources to delete)",
+			"--otel-endpoint (create-iam-roles: trace CredentialsRequest processing)",
+			"--oidc-signing-kms-key-arn (create-identity-provider: not yet supported)",
+		},
+	},
+	{
+		Provider: "gcp",
+		Modes:    []string{"mint", "passthrough", "manual", "sts"},
+		Commands: []string{"create-key-pair", "create-workload-identity-pool", "create-workload-identity-provider", "create-service-accounts", "create-all", "delete"},
+		NotableFlags: []string{
+			"--sa-display-name-template (create-service-accounts: customize generated service account display names)",
+		},
+	},
+	{
+		Provider:     "ibmcloud",
+		Modes:        []string{"manual"},
+		Commands:     []string{"create-service-id", "delete-service-id", "refresh-keys"},
+		NotableFlags: []string{},
+	},
+	{
+		Provider:     "alibabacloud",
+		Modes:        []string{"manual"},
+		Commands:     []string{"create-ram-users", "delete-ram-users"},
+		NotableFlags: []string{},
+	},
+}
+
+type capabilitiesOptions struct {
+	Provider string
+	Output   string
+}
+
+var (
+	// CapabilitiesOpts captures the options for the "capabilities" command.
+	CapabilitiesOpts = capabilitiesOptions{}
+)
+
+func capabilitiesCmd(cmd *cobra.Command, args []string) {
+	matrix := capabilitiesRegistry
+	if CapabilitiesOpts.Provider != "" {
+		matrix = nil
+		for _, pc := range capabilitiesRegistry {
+			if pc.Provider == CapabilitiesOpts.Provider {
+				matrix = append(matrix, pc)
+			}
+		}
+		if len(matrix) == 0 {
+			log.Fatalf("unknown provider %q, must be one of %v", CapabilitiesOpts.Provider, knownProviderNames())
+		}
+	}
+
+	switch CapabilitiesOpts.Output {
+	case "json":
+		raw, err := json.MarshalIndent(matrix, "", "  ")
+		if err != nil {
+			log.Fatalf("failed to marshal capabilities: %s", err)
+		}
+		fmt.Println(string(raw))
+	case "", "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "PROVIDER\tMODES\tCOMMANDS")
+		for _, pc := range matrix {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", pc.Provider, joinComma(pc.Modes), joinComma(pc.Commands))
+		}
+		w.Flush()
+	default:
+		log.Fatalf("unknown --output %q, must be one of: table, json", CapabilitiesOpts.Output)
+	}
+}
+
+func joinComma(items []string) string {
+	out := ""
+	for i, item := range items {
+		if i > 0 {
+			out += ","
+		}
+		out += item
+	}
+	return out
+}
+
+func knownProviderNames() []string {
+	names := make([]string, 0, len(capabilitiesRegistry))
+	for _, pc := range capabilitiesRegistry {
+		names = append(names, pc.Provider)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewCapabilitiesCmd provides the "capabilities" subcommand, reporting which modes, commands, and
+// notable flags each cloud provider supports in this build of ccoctl.
+func NewCapabilitiesCmd() *cobra.Command {
+	capabilitiesCobraCmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "Print the supported modes/commands/flags for each cloud provider",
+		Run:   capabilitiesCmd,
+	}
+
+	capabilitiesCobraCmd.PersistentFlags().StringVar(&CapabilitiesOpts.Provider, "provider", "", "Only report capabilities for this provider (defaults to all known providers)")
+	capabilitiesCobraCmd.PersistentFlags().StringVar(&CapabilitiesOpts.Output, "output", "table", "Output format: table or json")
+
+	return capabilitiesCobraCmd
+}
@@ -0,0 +1,86 @@
+package provisioning
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// ChecksumMetadataDirName is the directory, relative to the output directory, that holds the
+// checksum metadata file. It is kept out of the output directory's top level (and out of the
+// manifests directory) so it isn't mistaken for a generated manifest or picked up by tooling that
+// enumerates ccoctl's output files.
+const ChecksumMetadataDirName = ".ccoctl-metadata"
+
+// ChecksumMetadataFileName is the name of the file, within ChecksumMetadataDirName, that records
+// the checksum ccoctl last processed each CredentialsRequest with. It lets a repeated run
+// recognize unchanged CredentialsRequests and skip regenerating their Secrets.
+const ChecksumMetadataFileName = "checksums.json"
+
+// ChecksumStore maps a CredentialsRequest name to the checksum of the inputs it was last
+// processed with.
+type ChecksumStore map[string]string
+
+// LoadChecksumStore reads the checksum metadata file from targetDir. A missing file is not an
+// error; it is treated the same as an empty store, since that's the state of a fresh output
+// directory.
+func LoadChecksumStore(targetDir string) (ChecksumStore, error) {
+	store := ChecksumStore{}
+
+	raw, err := ioutil.ReadFile(filepath.Join(targetDir, ChecksumMetadataDirName, ChecksumMetadataFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, errors.Wrap(err, "failed to read checksum metadata file")
+	}
+
+	if err := json.Unmarshal(raw, &store); err != nil {
+		return nil, errors.Wrap(err, "failed to parse checksum metadata file")
+	}
+
+	return store, nil
+}
+
+// Save writes the checksum store back to the checksum metadata file in targetDir.
+func (s ChecksumStore) Save(targetDir string) error {
+	raw, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checksum metadata")
+	}
+
+	metadataDir := filepath.Join(targetDir, ChecksumMetadataDirName)
+	if err := EnsureDir(metadataDir); err != nil {
+		return errors.Wrap(err, "failed to create checksum metadata directory")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(metadataDir, ChecksumMetadataFileName), raw, 0600); err != nil {
+		return errors.Wrap(err, "failed to save checksum metadata file")
+	}
+
+	return nil
+}
+
+// UpToDate reports whether key was last processed with exactly checksum.
+func (s ChecksumStore) UpToDate(key, checksum string) bool {
+	existing, ok := s[key]
+	return ok && existing == checksum
+}
+
+// ComputeChecksum hashes the given inputs, in order, into a single checksum suitable for
+// ChecksumStore. Callers should pass every resolved input that affects the generated output (the
+// CredentialsRequest itself plus any command-line-derived values such as ARNs), so that a change
+// to any of them is detected as a change.
+func ComputeChecksum(inputs ...string) string {
+	h := sha256.New()
+	for _, in := range inputs {
+		h.Write([]byte(in))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
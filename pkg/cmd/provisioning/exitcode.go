@@ -0,0 +1,90 @@
+package provisioning
+
+import (
+	"errors"
+	"log"
+	"os"
+)
+
+// ExitCode is a stable process exit status returned by ccoctl subcommands, so automation wrapping
+// ccoctl can branch on outcome without scraping stderr.
+type ExitCode int
+
+const (
+	// ExitSuccess is returned when a command completes all requested work.
+	ExitSuccess ExitCode = 0
+	// ExitGenericError is returned for any failure that doesn't fit one of the more specific codes
+	// below.
+	ExitGenericError ExitCode = 1
+	// ExitValidationError is returned when a command's input - CredentialsRequest manifests or
+	// flags - fails validation before any cloud API calls are attempted.
+	ExitValidationError ExitCode = 2
+	// ExitAuthFailure is returned when the configured cloud credentials are missing, rejected, or
+	// insufficiently privileged for the requested operation.
+	ExitAuthFailure ExitCode = 3
+	// ExitNothingToDo is returned when a command completes without making any changes, e.g.
+	// because every resource it would otherwise create or update already existed in the desired
+	// state.
+	ExitNothingToDo ExitCode = 4
+	// ExitPartialSuccess is returned when a command completes some, but not all, of the requested
+	// work, e.g. provisioning succeeded for some CredentialsRequests but failed for others.
+	ExitPartialSuccess ExitCode = 5
+)
+
+// CLIError associates an error with the ExitCode ccoctl should terminate with on account of it.
+// Build one with NewValidationError/NewAuthFailureError/NewNothingToDoError/NewPartialSuccessError
+// rather than constructing a CLIError directly.
+type CLIError struct {
+	Code ExitCode
+	Err  error
+}
+
+func (e *CLIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CLIError) Unwrap() error {
+	return e.Err
+}
+
+// NewValidationError wraps err so ccoctl exits with ExitValidationError on account of it.
+func NewValidationError(err error) error {
+	return &CLIError{Code: ExitValidationError, Err: err}
+}
+
+// NewAuthFailureError wraps err so ccoctl exits with ExitAuthFailure on account of it.
+func NewAuthFailureError(err error) error {
+	return &CLIError{Code: ExitAuthFailure, Err: err}
+}
+
+// NewNothingToDoError wraps err so ccoctl exits with ExitNothingToDo on account of it.
+func NewNothingToDoError(err error) error {
+	return &CLIError{Code: ExitNothingToDo, Err: err}
+}
+
+// NewPartialSuccessError wraps err so ccoctl exits with ExitPartialSuccess on account of it.
+func NewPartialSuccessError(err error) error {
+	return &CLIError{Code: ExitPartialSuccess, Err: err}
+}
+
+// ExitCodeFor returns the ExitCode ccoctl should terminate with on account of err: ExitSuccess for
+// a nil err, the code carried by a *CLIError anywhere in err's chain, or ExitGenericError for any
+// other non-nil err.
+func ExitCodeFor(err error) ExitCode {
+	if err == nil {
+		return ExitSuccess
+	}
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) {
+		return cliErr.Code
+	}
+	return ExitGenericError
+}
+
+// Fatal prints err and terminates the process with the ExitCode appropriate for it (see
+// ExitCodeFor). It is a drop-in replacement for log.Fatal(err) at call sites that want ccoctl's
+// exit status to reflect the kind of failure instead of always signaling ExitGenericError.
+func Fatal(err error) {
+	log.Print(err)
+	os.Exit(int(ExitCodeFor(err)))
+}
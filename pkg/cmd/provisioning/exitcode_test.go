@@ -0,0 +1,69 @@
+package provisioning
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	baseErr := errors.New("boom")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected ExitCode
+	}{
+		{
+			name:     "nil error is success",
+			err:      nil,
+			expected: ExitSuccess,
+		},
+		{
+			name:     "plain error is generic",
+			err:      baseErr,
+			expected: ExitGenericError,
+		},
+		{
+			name:     "validation error",
+			err:      NewValidationError(baseErr),
+			expected: ExitValidationError,
+		},
+		{
+			name:     "auth failure error",
+			err:      NewAuthFailureError(baseErr),
+			expected: ExitAuthFailure,
+		},
+		{
+			name:     "nothing to do error",
+			err:      NewNothingToDoError(baseErr),
+			expected: ExitNothingToDo,
+		},
+		{
+			name:     "partial success error",
+			err:      NewPartialSuccessError(baseErr),
+			expected: ExitPartialSuccess,
+		},
+		{
+			name:     "CLIError wrapped by another error still maps",
+			err:      fmt.Errorf("while doing X: %w", NewAuthFailureError(baseErr)),
+			expected: ExitAuthFailure,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ExitCodeFor(test.err))
+		})
+	}
+}
+
+func TestCLIErrorUnwrap(t *testing.T) {
+	baseErr := errors.New("boom")
+	cliErr := NewValidationError(baseErr)
+
+	assert.Equal(t, baseErr, errors.Unwrap(cliErr))
+	assert.Equal(t, baseErr.Error(), cliErr.Error())
+}
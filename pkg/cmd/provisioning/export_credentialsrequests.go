@@ -0,0 +1,121 @@
+package provisioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+)
+
+// exportCredentialsRequestsFileNamePattern names the manifest file "export credentialsrequests"
+// writes for each CredentialsRequest it finds on the cluster.
+const exportCredentialsRequestsFileNamePattern = "%s-%s-credentials-request.yaml"
+
+type exportCredentialsRequestsOptions struct {
+	KubeConfigFile    string
+	Namespace         string
+	LabelSelector     string
+	OutputDir         string
+	EnableTechPreview bool
+}
+
+var (
+	// ExportCredentialsRequestsOpts captures the options that affect exporting CredentialsRequests
+	// from a live cluster to manifest files.
+	ExportCredentialsRequestsOpts = exportCredentialsRequestsOptions{}
+)
+
+// NewExportCmd provides the "export" command, a home for subcommands that read objects off a live
+// cluster and write them back out as manifests, the inverse of ccoctl's usual manifests-in,
+// cloud-resources-out direction.
+func NewExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export objects from a live cluster to manifest files",
+	}
+
+	exportCmd.AddCommand(newExportCredentialsRequestsCmd())
+
+	return exportCmd
+}
+
+// newExportCredentialsRequestsCmd provides the "export credentialsrequests" command, for
+// recreating a manifests directory from a live cluster when the original one used to provision it
+// is lost, or for backing one up before day-2 edits.
+func newExportCredentialsRequestsCmd() *cobra.Command {
+	exportCredentialsRequestsCmd := &cobra.Command{
+		Use:   "credentialsrequests",
+		Short: "List CredentialsRequests from a live cluster and write them out as manifest files",
+		Long:  "Lists CredentialsRequests from a live cluster via the Kubernetes API, strips server-managed fields (status, resourceVersion, uid, and similar metadata), and writes each one out as its own manifest file, so the result can seed --credentials-requests-dir for create-all/create-iam-roles on a different cluster, or serve as a backup.",
+		Run:   exportCredentialsRequestsCmdRun,
+	}
+
+	exportCredentialsRequestsCmd.PersistentFlags().StringVar(&ExportCredentialsRequestsOpts.KubeConfigFile, "kubeconfig", "", "Path to the kubeconfig file to use (defaults to the standard kubeconfig loading rules)")
+	exportCredentialsRequestsCmd.PersistentFlags().StringVar(&ExportCredentialsRequestsOpts.Namespace, "namespace", "", "Only list CredentialsRequests in this namespace (defaults to all namespaces)")
+	exportCredentialsRequestsCmd.PersistentFlags().StringVar(&ExportCredentialsRequestsOpts.LabelSelector, "selector", "", "Only list CredentialsRequests matching this label selector")
+	exportCredentialsRequestsCmd.PersistentFlags().StringVar(&ExportCredentialsRequestsOpts.OutputDir, "output-dir", "", "Directory to place the exported CredentialsRequest manifests (defaults to current directory)")
+	exportCredentialsRequestsCmd.PersistentFlags().BoolVar(&ExportCredentialsRequestsOpts.EnableTechPreview, "enable-tech-preview", false, "Include CredentialsRequests marked as tech-preview")
+
+	return exportCredentialsRequestsCmd
+}
+
+func exportCredentialsRequestsCmdRun(cmd *cobra.Command, args []string) {
+	credRequests, err := GetListOfCredentialsRequestsFromCluster(ExportCredentialsRequestsOpts.KubeConfigFile, ExportCredentialsRequestsOpts.Namespace, ExportCredentialsRequestsOpts.LabelSelector, ExportCredentialsRequestsOpts.EnableTechPreview)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outputDir := ExportCredentialsRequestsOpts.OutputDir
+	manifestsDir := filepath.Join(outputDir, ManifestsDirName)
+	if err := EnsureDir(manifestsDir); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, cr := range credRequests {
+		if err := writeExportedCredentialsRequest(cr, manifestsDir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	log.Printf("Exported %d CredentialsRequest(s) to %s", len(credRequests), manifestsDir)
+}
+
+// writeExportedCredentialsRequest strips the server-managed fields off a CredentialsRequest
+// fetched from a live cluster (everything but name, namespace, labels, and annotations on
+// ObjectMeta, and all of Status) and writes what remains to its own manifest file.
+func writeExportedCredentialsRequest(cr *credreqv1.CredentialsRequest, manifestsDir string) error {
+	exported := &credreqv1.CredentialsRequest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "cloudcredential.openshift.io/v1",
+			Kind:       "CredentialsRequest",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cr.Name,
+			Namespace:   cr.Namespace,
+			Labels:      cr.Labels,
+			Annotations: cr.Annotations,
+		},
+		Spec: cr.Spec,
+	}
+
+	data, err := yaml.Marshal(exported)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal CredentialsRequest %s/%s", cr.Namespace, cr.Name)
+	}
+
+	filePath := filepath.Join(manifestsDir, fmt.Sprintf(exportCredentialsRequestsFileNamePattern, cr.Namespace, cr.Name))
+	if err := ioutil.WriteFile(filePath, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to save CredentialsRequest manifest %s", filePath)
+	}
+
+	log.Printf("Saved CredentialsRequest manifest to: %s", filePath)
+	return nil
+}
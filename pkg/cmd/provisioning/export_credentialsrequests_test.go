@@ -0,0 +1,41 @@
+package provisioning
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWriteExportedCredentialsRequest(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "exportcredreqtest")
+	require.NoError(t, err, "unexpected error creating temp dir")
+	defer os.RemoveAll(tempDir)
+
+	cr := NewCredentialsRequestBuilder().
+		Options(WithName("credReqA")).
+		Options(WithSecretRef("namespace1", "secretName1")).
+		Build()
+	cr.ResourceVersion = "12345"
+	cr.UID = "some-uid"
+	cr.Generation = 3
+
+	require.NoError(t, writeExportedCredentialsRequest(cr, tempDir))
+
+	filePath := filepath.Join(tempDir, "-credReqA-credentials-request.yaml")
+	got, err := GetCredentialsRequestFromFile(filePath)
+	require.NoError(t, err, "unexpected error reading back exported CredentialsRequest")
+
+	assert.Equal(t, "credReqA", got.Name)
+	assert.Equal(t, "namespace1", got.Spec.SecretRef.Namespace)
+	assert.Equal(t, "secretName1", got.Spec.SecretRef.Name)
+	assert.Empty(t, got.ResourceVersion, "expected server-managed resourceVersion to be stripped")
+	assert.Empty(t, got.UID, "expected server-managed uid to be stripped")
+	assert.Zero(t, got.Generation, "expected server-managed generation to be stripped")
+	assert.Equal(t, metav1.Time{}, got.CreationTimestamp, "expected server-managed creationTimestamp to be stripped")
+}
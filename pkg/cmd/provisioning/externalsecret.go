@@ -0,0 +1,196 @@
+package provisioning
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ExternalSecretsBackend identifies which cloud secret manager a SecretStore should be
+// configured to read from.
+type ExternalSecretsBackend string
+
+const (
+	// BackendAWSSecretsManager configures a SecretStore backed by AWS Secrets Manager.
+	BackendAWSSecretsManager ExternalSecretsBackend = "aws-secrets-manager"
+	// BackendGCPSecretManager configures a SecretStore backed by GCP Secret Manager.
+	BackendGCPSecretManager ExternalSecretsBackend = "gcp-secret-manager"
+	// BackendAzureKeyVault configures a SecretStore backed by Azure Key Vault.
+	BackendAzureKeyVault ExternalSecretsBackend = "azure-key-vault"
+)
+
+// secretStore is a minimal representation of an external-secrets.io/v1beta1 SecretStore,
+// sufficient for ccoctl to point the External Secrets Operator at the backend holding a
+// credential pushed there out-of-band.
+type secretStore struct {
+	APIVersion string          `yaml:"apiVersion"`
+	Kind       string          `yaml:"kind"`
+	Metadata   resourceMeta    `yaml:"metadata"`
+	Spec       secretStoreSpec `yaml:"spec"`
+}
+
+type resourceMeta struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+type secretStoreSpec struct {
+	Provider map[string]interface{} `yaml:"provider"`
+}
+
+// externalSecret is a minimal representation of an external-secrets.io/v1beta1 ExternalSecret.
+type externalSecret struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   resourceMeta       `yaml:"metadata"`
+	Spec       externalSecretSpec `yaml:"spec"`
+}
+
+type externalSecretSpec struct {
+	SecretStoreRef secretStoreRef        `yaml:"secretStoreRef"`
+	Target         externalSecretTarget  `yaml:"target"`
+	Data           []externalSecretDatum `yaml:"data"`
+}
+
+type secretStoreRef struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+}
+
+type externalSecretTarget struct {
+	Name string `yaml:"name"`
+}
+
+type externalSecretDatum struct {
+	SecretKey string                  `yaml:"secretKey"`
+	RemoteRef externalSecretRemoteRef `yaml:"remoteRef"`
+}
+
+type externalSecretRemoteRef struct {
+	Key      string `yaml:"key"`
+	Property string `yaml:"property"`
+}
+
+// providerConfig builds the backend-specific "provider" block of a SecretStore. backendConfig is
+// the one piece of backend-specific addressing ccoctl needs from the caller: the AWS region, the
+// GCP project ID, or the Azure Key Vault URL.
+func providerConfig(backend ExternalSecretsBackend, backendConfig string) (map[string]interface{}, error) {
+	switch backend {
+	case BackendAWSSecretsManager:
+		return map[string]interface{}{
+			"aws": map[string]interface{}{
+				"service": "SecretsManager",
+				"region":  backendConfig,
+			},
+		}, nil
+	case BackendGCPSecretManager:
+		return map[string]interface{}{
+			"gcpsm": map[string]interface{}{
+				"projectID": backendConfig,
+			},
+		}, nil
+	case BackendAzureKeyVault:
+		return map[string]interface{}{
+			"azurekv": map[string]interface{}{
+				"vaultUrl": backendConfig,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported external-secrets backend: %s", backend)
+	}
+}
+
+// PushCredentialToBackend is expected to store data in the given cloud secret manager under
+// backendRef, prior to writing the ExternalSecret manifests that reference it.
+//
+// None of the backend SDKs (AWS Secrets Manager, GCP Secret Manager, Azure Key Vault) are
+// vendored in this build, so this always errors rather than silently skipping the push. Until a
+// backend-specific implementation lands, pair WriteExternalSecretManifests with a credential
+// already placed at backendRef by some other means (e.g. the cloud CLI).
+func PushCredentialToBackend(backend ExternalSecretsBackend, backendRef string, data map[string]string) error {
+	return fmt.Errorf("pushing credentials to %s is not supported in this build; store the credential at %q out-of-band and pass it to WriteExternalSecretManifests", backend, backendRef)
+}
+
+// DeleteCredentialFromBackend is expected to remove the entry backendRef stores in the given cloud
+// secret manager, mirroring PushCredentialToBackend's gap: none of the backend SDKs are vendored in
+// this build, so this always errors rather than silently leaving the entry behind unannounced.
+// Callers should still proceed with deleting the cloud resources under their control and surface
+// this error so the operator knows to remove backendRef from the backend by hand.
+func DeleteCredentialFromBackend(backend ExternalSecretsBackend, backendRef string) error {
+	return fmt.Errorf("deleting credentials from %s is not supported in this build; remove %q from the backend out-of-band", backend, backendRef)
+}
+
+// WriteExternalSecretManifests writes a SecretStore and an ExternalSecret manifest that together
+// tell the External Secrets Operator to materialize namespace/name as a Secret populated from the
+// credential already stored in the backend under backendRef, with each entry of keys read from the
+// matching property on that backend entry. No Secret type is set on the ExternalSecret's target.
+func WriteExternalSecretManifests(backend ExternalSecretsBackend, backendConfig, namespace, name, backendRef string, keys []string, storeOutPath, secretOutPath string) error {
+	provider, err := providerConfig(backend, backendConfig)
+	if err != nil {
+		return err
+	}
+
+	storeName := fmt.Sprintf("%s-store", name)
+
+	store := secretStore{
+		APIVersion: "external-secrets.io/v1beta1",
+		Kind:       "SecretStore",
+		Metadata: resourceMeta{
+			Name:      storeName,
+			Namespace: namespace,
+		},
+		Spec: secretStoreSpec{
+			Provider: provider,
+		},
+	}
+
+	data := make([]externalSecretDatum, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, externalSecretDatum{
+			SecretKey: key,
+			RemoteRef: externalSecretRemoteRef{
+				Key:      backendRef,
+				Property: key,
+			},
+		})
+	}
+
+	secret := externalSecret{
+		APIVersion: "external-secrets.io/v1beta1",
+		Kind:       "ExternalSecret",
+		Metadata: resourceMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: externalSecretSpec{
+			SecretStoreRef: secretStoreRef{
+				Name: storeName,
+				Kind: "SecretStore",
+			},
+			Target: externalSecretTarget{
+				Name: name,
+			},
+			Data: data,
+		},
+	}
+
+	storeOut, err := yaml.Marshal(&store)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SecretStore manifest")
+	}
+	if err := ioutil.WriteFile(storeOutPath, storeOut, 0600); err != nil {
+		return errors.Wrap(err, "failed to save SecretStore manifest")
+	}
+
+	secretOut, err := yaml.Marshal(&secret)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ExternalSecret manifest")
+	}
+	if err := ioutil.WriteFile(secretOutPath, secretOut, 0600); err != nil {
+		return errors.Wrap(err, "failed to save ExternalSecret manifest")
+	}
+
+	return nil
+}
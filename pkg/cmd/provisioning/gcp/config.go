@@ -0,0 +1,93 @@
+package gcp
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// fileConfig is the schema for --config, letting project/region/pool settings and per-
+// CredentialsRequest overrides be declared in a version-controlled YAML file instead of passed as
+// flags on every run. Fields mirror the create-all flags they back; an empty field means "not set
+// in the file".
+type fileConfig struct {
+	Name                  string `yaml:"name"`
+	Project               string `yaml:"project"`
+	Region                string `yaml:"region"`
+	IdentityPoolProject   string `yaml:"identityPoolProject"`
+	CredRequestDir        string `yaml:"credentialsRequestsDir"`
+	TargetDir             string `yaml:"outputDir"`
+	EnableTechPreview     bool   `yaml:"enableTechPreview"`
+	SADisplayNameTemplate string `yaml:"saDisplayNameTemplate"`
+	// BindingConditions holds "credReqName=expr" entries, one per CredentialsRequest needing an
+	// IAM condition on its project role binding, in the same format as --binding-condition.
+	BindingConditions []string `yaml:"bindingConditions"`
+}
+
+// loadFileConfig reads and parses the YAML file at path into a fileConfig.
+func loadFileConfig(path string) (*fileConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config file %s", path)
+	}
+
+	cfg := &fileConfig{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config file %s", path)
+	}
+
+	return cfg, nil
+}
+
+// applyFileConfig sets cmd's flags from cfg, for any flag the user did not already pass on the
+// command line (per cmd.Flags().Changed). Going through cmd.Flags().Set, rather than writing
+// directly to opts, keeps a required flag (e.g. --name) satisfied by the config file from
+// tripping cobra's required-flag check, since that check only looks at whether the flag was
+// marked Changed. opts is filled in as a side effect, since its fields are bound to these same
+// flags.
+func applyFileConfig(cmd *cobra.Command, opts *options, cfg *fileConfig) error {
+	setIfUnchanged := func(name, value string) error {
+		if value == "" || cmd.Flags().Changed(name) {
+			return nil
+		}
+		return cmd.Flags().Set(name, value)
+	}
+
+	if err := setIfUnchanged("name", cfg.Name); err != nil {
+		return err
+	}
+	if err := setIfUnchanged("project", cfg.Project); err != nil {
+		return err
+	}
+	if err := setIfUnchanged("region", cfg.Region); err != nil {
+		return err
+	}
+	if err := setIfUnchanged("identity-pool-project", cfg.IdentityPoolProject); err != nil {
+		return err
+	}
+	if err := setIfUnchanged("credentials-requests-dir", cfg.CredRequestDir); err != nil {
+		return err
+	}
+	if err := setIfUnchanged("output-dir", cfg.TargetDir); err != nil {
+		return err
+	}
+	if err := setIfUnchanged("sa-display-name-template", cfg.SADisplayNameTemplate); err != nil {
+		return err
+	}
+	if cfg.EnableTechPreview {
+		if err := setIfUnchanged("enable-tech-preview", strconv.FormatBool(cfg.EnableTechPreview)); err != nil {
+			return err
+		}
+	}
+	if len(cfg.BindingConditions) > 0 {
+		if err := setIfUnchanged("binding-condition", strings.Join(cfg.BindingConditions, ",")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
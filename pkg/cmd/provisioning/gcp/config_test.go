@@ -0,0 +1,99 @@
+package gcp
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfigCmd builds a bare cobra.Command registering the same flags create-all does, enough to
+// exercise applyFileConfig's Changed/Set logic without needing the full create-all command.
+func testConfigCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringVar(&opts.Name, "name", "", "")
+	cmd.Flags().StringVar(&opts.Project, "project", "", "")
+	cmd.Flags().StringVar(&opts.Region, "region", "us", "")
+	cmd.Flags().StringVar(&opts.IdentityPoolProject, "identity-pool-project", "", "")
+	cmd.Flags().StringVar(&opts.CredRequestDir, "credentials-requests-dir", "", "")
+	cmd.Flags().StringVar(&opts.TargetDir, "output-dir", "", "")
+	cmd.Flags().StringVar(&opts.SADisplayNameTemplate, "sa-display-name-template", "", "")
+	cmd.Flags().BoolVar(&opts.EnableTechPreview, "enable-tech-preview", false, "")
+	cmd.Flags().StringSliceVar(&opts.BindingConditions, "binding-condition", nil, "")
+	return cmd
+}
+
+func TestApplyFileConfig(t *testing.T) {
+	t.Run("fills unset flags from the config file", func(t *testing.T) {
+		opts := &options{}
+		cmd := testConfigCmd(opts)
+		cfg := &fileConfig{
+			Name:                  "cluster-a",
+			Project:               "my-project",
+			Region:                "europe-west1",
+			IdentityPoolProject:   "pool-project",
+			CredRequestDir:        "/creds",
+			TargetDir:             "/out",
+			SADisplayNameTemplate: "{{.ClusterName}}-{{.CredReqName}}",
+			EnableTechPreview:     true,
+			BindingConditions:     []string{"cr-one=resource.name.startsWith('x')"},
+		}
+
+		require.NoError(t, applyFileConfig(cmd, opts, cfg))
+		assert.Equal(t, "cluster-a", opts.Name)
+		assert.Equal(t, "my-project", opts.Project)
+		assert.Equal(t, "europe-west1", opts.Region)
+		assert.Equal(t, "pool-project", opts.IdentityPoolProject)
+		assert.Equal(t, "/creds", opts.CredRequestDir)
+		assert.Equal(t, "/out", opts.TargetDir)
+		assert.Equal(t, "{{.ClusterName}}-{{.CredReqName}}", opts.SADisplayNameTemplate)
+		assert.True(t, opts.EnableTechPreview)
+		assert.Equal(t, []string{"cr-one=resource.name.startsWith('x')"}, opts.BindingConditions)
+	})
+
+	t.Run("flags explicitly set on the command line win over the file", func(t *testing.T) {
+		opts := &options{}
+		cmd := testConfigCmd(opts)
+		require.NoError(t, cmd.Flags().Set("project", "cli-project"))
+		require.NoError(t, cmd.Flags().Set("region", "us-east1"))
+
+		cfg := &fileConfig{Project: "file-project", Region: "file-region"}
+		require.NoError(t, applyFileConfig(cmd, opts, cfg))
+
+		assert.Equal(t, "cli-project", opts.Project)
+		assert.Equal(t, "us-east1", opts.Region)
+	})
+
+	t.Run("empty config leaves flag defaults untouched", func(t *testing.T) {
+		opts := &options{}
+		cmd := testConfigCmd(opts)
+
+		require.NoError(t, applyFileConfig(cmd, opts, &fileConfig{}))
+		assert.Equal(t, "us", opts.Region)
+		assert.Equal(t, "", opts.Project)
+	})
+}
+
+func TestLoadFileConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "gcp-config-*.yaml")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString("project: my-project\nregion: us-east1\nbindingConditions:\n- cr-one=true\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	cfg, err := loadFileConfig(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, "my-project", cfg.Project)
+	assert.Equal(t, "us-east1", cfg.Region)
+	assert.Equal(t, []string{"cr-one=true"}, cfg.BindingConditions)
+}
+
+func TestLoadFileConfigMissingFile(t *testing.T) {
+	_, err := loadFileConfig("/nonexistent/path/config.yaml")
+	assert.Error(t, err)
+}
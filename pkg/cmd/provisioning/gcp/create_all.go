@@ -43,16 +43,24 @@ func createAllCmd(cmd *cobra.Command, args []string) {
 		log.Fatalf("Failed to create public/private key pair: %s", err)
 	}
 
-	if err = createWorkloadIdentityPool(ctx, gcpClient, CreateAllOpts.Name, CreateAllOpts.Project, CreateAllOpts.TargetDir, false); err != nil {
+	identityPoolProject := identityPoolProjectOrDefault(CreateAllOpts)
+
+	if err = createWorkloadIdentityPool(ctx, gcpClient, CreateAllOpts.Name, identityPoolProject, CreateAllOpts.TargetDir, false); err != nil {
 		log.Fatalf("Failed to create workload identity pool: %s", err)
 	}
 
-	if err = createWorkloadIdentityProvider(ctx, gcpClient, CreateAllOpts.Name, CreateAllOpts.Region, CreateAllOpts.Project, CreateAllOpts.Name, publicKeyPath, CreateAllOpts.TargetDir, false); err != nil {
+	if err = createWorkloadIdentityProvider(ctx, gcpClient, CreateAllOpts.Name, CreateAllOpts.Region, CreateAllOpts.Project, identityPoolProject, CreateAllOpts.Name, publicKeyPath, CreateAllOpts.TargetDir, "", nil, false); err != nil {
 		log.Fatalf("Failed to create workload identity provider: %s", err)
 	}
 
-	if err = createServiceAccounts(ctx, gcpClient, CreateAllOpts.Name, CreateAllOpts.Name, CreateAllOpts.Name, CreateAllOpts.CredRequestDir,
-		CreateAllOpts.TargetDir, CreateAllOpts.EnableTechPreview, false); err != nil {
+	bindingConditions, err := parseBindingConditions(CreateAllOpts.BindingConditions)
+	if err != nil {
+		log.Fatal(err)
+	}
+	CreateServiceAccountsOpts.SADisplayNameTemplate = CreateAllOpts.SADisplayNameTemplate
+
+	if err = createServiceAccounts(ctx, gcpClient, CreateAllOpts.Name, identityPoolProject, CreateAllOpts.Name, CreateAllOpts.Name, CreateAllOpts.CredRequestDir,
+		CreateAllOpts.TargetDir, CreateAllOpts.EnableTechPreview, false, bindingConditions); err != nil {
 		log.Fatalf("Failed to create IAM service accounts: %s", err)
 	}
 }
@@ -60,6 +68,16 @@ func createAllCmd(cmd *cobra.Command, args []string) {
 // validationForCreateAllCmd will validate the arguments to the command, ensure the destination directory
 // is ready to receive the generated files, and will create the directory if necessary.
 func validationForCreateAllCmd(cmd *cobra.Command, args []string) {
+	if CreateAllOpts.Config != "" {
+		cfg, err := loadFileConfig(CreateAllOpts.Config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := applyFileConfig(cmd, &CreateAllOpts, cfg); err != nil {
+			log.Fatalf("Failed to apply --config: %s", err)
+		}
+	}
+
 	if len(CreateWorkloadIdentityPoolOpts.Name) > 32 {
 		log.Fatalf("Name can be at most 32 characters long")
 	}
@@ -108,12 +126,16 @@ func NewCreateAllCmd() *cobra.Command {
 		PersistentPreRun: validationForCreateAllCmd,
 	}
 
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.Config, "config", "", "Path to a YAML file declaring project, region, pool settings, and per-CredentialsRequest overrides (e.g. binding-condition) for create-all, so a run is reproducible from version-controlled configuration. Flags passed explicitly on the command line override values from this file")
 	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.Name, "name", "", "User-defined name for all created Google cloud resources (can be separate from the cluster's infra-id)")
 	createAllCmd.MarkPersistentFlagRequired("name")
 	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.Region, "region", "us", "Google cloud region where the Google Storage Bucket holding the OpenID Connect configuration will be created")
 	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.Project, "project", "", "ID of the Google cloud project")
 	createAllCmd.MarkPersistentFlagRequired("project")
-	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create gcp service accounts for (can be created by running 'oc adm release extract --credentials-requests --cloud=gcp' against an OpenShift release image)")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.IdentityPoolProject, "identity-pool-project", "", "ID of the Google cloud project to create the workload identity pool and provider in, when it should be centrally managed in a host project separate from --project (defaults to --project)")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.SADisplayNameTemplate, "sa-display-name-template", "", "Go text/template used to render each IAM service account's display name, with .ClusterName and .CredReqName available (see create-service-accounts)")
+	createAllCmd.PersistentFlags().StringSliceVar(&CreateAllOpts.BindingConditions, "binding-condition", nil, "Comma-separated credReqName=expr pairs attaching a GCP IAM condition to the project role bindings created for that CredentialsRequest (see create-service-accounts)")
+	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create gcp service accounts for (can be created by running 'oc adm release extract --credentials-requests --cloud=gcp' against an OpenShift release image). Accepts a comma-separated list of directories to merge")
 	createAllCmd.MarkPersistentFlagRequired("credentials-requests-dir")
 	createAllCmd.PersistentFlags().StringVar(&CreateAllOpts.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
 	createAllCmd.PersistentFlags().BoolVar(&CreateAllOpts.EnableTechPreview, "enable-tech-preview", false, "Opt into processing CredentialsRequests marked as tech-preview")
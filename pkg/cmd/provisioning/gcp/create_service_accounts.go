@@ -1,17 +1,22 @@
 package gcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/template"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"google.golang.org/api/cloudresourcemanager/v1"
 	iamadminpb "google.golang.org/genproto/googleapis/iam/admin/v1"
 
 	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
@@ -21,6 +26,45 @@ import (
 	"github.com/openshift/cloud-credential-operator/pkg/operator/utils"
 )
 
+// maxServiceAccountDisplayNameLen is GCP's hard limit on IAM service account display names.
+const maxServiceAccountDisplayNameLen = 100
+
+// serviceAccountDisplayNameData is the data made available to --sa-display-name-template.
+type serviceAccountDisplayNameData struct {
+	ClusterName string
+	CredReqName string
+}
+
+// renderServiceAccountDisplayName renders tmpl (--sa-display-name-template) against clusterName and
+// credReqName. With tmpl empty, it falls back to the original "<clusterName>-<credReqName>" naming
+// scheme used before the flag existed. The rendered name is truncated to
+// maxServiceAccountDisplayNameLen if needed, with a stable hash of the untruncated name appended so
+// two CredentialsRequests that collide only after truncation don't end up with the same display name.
+func renderServiceAccountDisplayName(tmpl, clusterName, credReqName string) (string, error) {
+	if tmpl == "" {
+		return utils.GenerateNameWithFieldLimits(clusterName, 50, credReqName, 49)
+	}
+
+	t, err := template.New("sa-display-name").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid --sa-display-name-template")
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, serviceAccountDisplayNameData{ClusterName: clusterName, CredReqName: credReqName}); err != nil {
+		return "", errors.Wrap(err, "failed to render --sa-display-name-template")
+	}
+	rendered := buf.String()
+
+	if len(rendered) <= maxServiceAccountDisplayNameLen {
+		return rendered, nil
+	}
+
+	hasher := fnv.New32a()
+	hasher.Write([]byte(rendered))
+	suffix := fmt.Sprintf("-%x", hasher.Sum32())
+	return rendered[:maxServiceAccountDisplayNameLen-len(suffix)] + suffix, nil
+}
+
 const (
 	// secretManifestsTemplate ia template of a gcp credentials secret manifest
 	secretManifestsTemplate = `apiVersion: v1
@@ -65,6 +109,13 @@ type: Opaque`
 	// generateCredentialsConfigScriptName is the name of the script to generate credentials config required to
 	// impersonate service account
 	generateCredentialsConfigScriptName = "08-%d-generate-credentials-config-for-%s-sa.sh"
+	// serviceAccountJSONSecretKey is the Secret data key ccoctl uses for a GCP service account
+	// credential, matching gcpSecretJSONKey in pkg/gcp/actuator.
+	serviceAccountJSONSecretKey = "service_account.json"
+	// staticKeysPendingDirName holds IAM service account JSON keys minted for
+	// --static-key-secret-manager-project that still need to be pushed to GCP Secret Manager by
+	// hand, since this build has no Secret Manager SDK vendored to do that automatically.
+	staticKeysPendingDirName = "service-account-keys-pending-secret-manager"
 )
 
 var (
@@ -75,25 +126,35 @@ var (
 	}
 )
 
-func createServiceAccounts(ctx context.Context, client gcp.Client, name, workloadIdentityPool, workloadIdentityProvider, credReqDir, targetDir string, enableTechPreview, generateOnly bool) error {
+func createServiceAccounts(ctx context.Context, client gcp.Client, name, identityPoolProject, workloadIdentityPool, workloadIdentityProvider, credReqDir, targetDir string, enableTechPreview, generateOnly bool, bindingConditions map[string]string) error {
 	// Process directory
-	credRequests, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview)
+	credRequests, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview, true)
 	if err != nil {
 		return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
 	}
 
 	// Create service accounts
-	if err := processCredentialsRequests(ctx, client, credRequests, name, workloadIdentityPool, workloadIdentityProvider, targetDir, generateOnly); err != nil {
+	if err := processCredentialsRequests(ctx, client, credRequests, name, identityPoolProject, workloadIdentityPool, workloadIdentityProvider, targetDir, generateOnly, bindingConditions); err != nil {
 		return errors.Wrap(err, "Failed while processing each CredentialsRequest")
 	}
 
 	return nil
 }
 
-func processCredentialsRequests(ctx context.Context, client gcp.Client, credReqs []*credreqv1.CredentialsRequest, name, workloadIdentityPool, workloadIdentityProvider, targetDir string, generateOnly bool) error {
+func processCredentialsRequests(ctx context.Context, client gcp.Client, credReqs []*credreqv1.CredentialsRequest, name, identityPoolProject, workloadIdentityPool, workloadIdentityProvider, targetDir string, generateOnly bool, bindingConditions map[string]string) error {
 	project := client.GetProjectName()
+	if identityPoolProject == "" {
+		identityPoolProject = project
+	}
 	for i, cr := range credReqs {
-		_, err := createServiceAccount(ctx, client, name, cr, i, workloadIdentityPool, workloadIdentityProvider, project, targetDir, generateOnly)
+		var condition *cloudresourcemanager.Expr
+		if expr, ok := bindingConditions[cr.Name]; ok {
+			condition = &cloudresourcemanager.Expr{
+				Expression: expr,
+				Title:      fmt.Sprintf("ccoctl-%s", cr.Name),
+			}
+		}
+		_, err := createServiceAccount(ctx, client, name, cr, i, identityPoolProject, workloadIdentityPool, workloadIdentityProvider, project, targetDir, generateOnly, condition)
 		if err != nil {
 			return err
 		}
@@ -102,7 +163,60 @@ func processCredentialsRequests(ctx context.Context, client gcp.Client, credReqs
 	return nil
 }
 
-func createServiceAccount(ctx context.Context, client gcp.Client, name string, credReq *credreqv1.CredentialsRequest, serviceAccountNum int, workloadIdentityPool, workloadIdentityProvider, project, targetDir string, generateOnly bool) (string, error) {
+// parseBindingConditions parses --binding-condition entries of the form "credReqName=expr", where
+// expr is a CEL expression to attach as an IAM condition on the project role binding created for
+// that CredentialsRequest. Each expression is syntactically sanity-checked before being accepted:
+// this build has no CEL parser vendored to validate the expression against GCP's actual grammar, so
+// callers should expect GCP itself to reject a syntactically-balanced-but-semantically-invalid
+// expression at apply time.
+func parseBindingConditions(entries []string) (map[string]string, error) {
+	conditions := map[string]string{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid --binding-condition entry %q, expected credReqName=expr", entry)
+		}
+		credReqName, expr := parts[0], parts[1]
+		if err := validateCELExpressionSyntax(expr); err != nil {
+			return nil, errors.Wrapf(err, "invalid --binding-condition expression for %q", credReqName)
+		}
+		conditions[credReqName] = expr
+	}
+	return conditions, nil
+}
+
+// validateCELExpressionSyntax performs a lightweight syntactic sanity check on a CEL expression:
+// non-empty, and with balanced parentheses/brackets. It is not a real CEL parser, so it will not
+// catch every malformed expression, but it catches the common mistakes (an empty value, a
+// mismatched paren from a copy-paste) before an invalid binding is sent to GCP.
+func validateCELExpressionSyntax(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return errors.New("expression is empty")
+	}
+
+	var stack []rune
+	pairs := map[rune]rune{')': '(', ']': '[', '}': '{'}
+	for _, r := range expr {
+		switch r {
+		case '(', '[', '{':
+			stack = append(stack, r)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != pairs[r] {
+				return fmt.Errorf("unbalanced %q in expression", r)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) != 0 {
+		return fmt.Errorf("unbalanced %q in expression", stack[len(stack)-1])
+	}
+	return nil
+}
+
+// createServiceAccount creates the GCP IAM service account for credReq in project, and binds it
+// to the workload identity pool/provider hosted in identityPoolProject (the same project as
+// project unless --identity-pool-project centralizes the pool in a separate host project).
+func createServiceAccount(ctx context.Context, client gcp.Client, name string, credReq *credreqv1.CredentialsRequest, serviceAccountNum int, identityPoolProject, workloadIdentityPool, workloadIdentityProvider, project, targetDir string, generateOnly bool, bindingCondition *cloudresourcemanager.Expr) (string, error) {
 	// The credReq must have a non zero-length list of ServiceAccountNames
 	// that can be used to restrict which k8s ServiceAccounts can use the GCP ServiceAccount.
 	if len(credReq.Spec.ServiceAccountNames) == 0 {
@@ -116,9 +230,10 @@ func createServiceAccount(ctx context.Context, client gcp.Client, name string, c
 	if err != nil {
 		return "", errors.Wrap(err, "Error generating service account ID")
 	}
-	// The service account name field has a 100 char max, so generate a name consisting of the
-	// infraName chopped to 50 chars + the crName chopped to 49 chars (separated by a '-').
-	serviceAccountName, err := utils.GenerateNameWithFieldLimits(name, 50, credReq.Name, 49)
+	// The service account name field has a 100 char max. By default this is the infraName chopped
+	// to 50 chars + the crName chopped to 49 chars (separated by a '-'); --sa-display-name-template
+	// lets auditors opt into a more descriptive name instead, still respecting the 100 char limit.
+	serviceAccountName, err := renderServiceAccountDisplayName(CreateServiceAccountsOpts.SADisplayNameTemplate, name, credReq.Name)
 	if err != nil {
 		return "", errors.Wrap(err, "Error generating service account name")
 	}
@@ -138,13 +253,19 @@ func createServiceAccount(ctx context.Context, client gcp.Client, name string, c
 		return "", fmt.Errorf("CredentialsRequest %s/%s is not of type GCP", credReq.Namespace, credReq.Name)
 	}
 
-	projectNum, err := getProjectNumber(ctx, client, project)
+	projectNum, err := getProjectNumber(ctx, client, identityPoolProject)
 	if err != nil {
 		return "", errors.Wrap(err, "Failed to get project number")
 	}
 
 	identityProviderBindingNames := getIdentityProviderBindingNames(projectNum, workloadIdentityPool, credReq.Spec.SecretRef.Namespace, credReq.Spec.ServiceAccountNames)
 
+	if CreateServiceAccountsOpts.EmitPoliciesDir != "" {
+		if err := emitPoliciesForReview(credReq, serviceAccountID, gcpProviderSpec.PredefinedRoles, bindingCondition, CreateServiceAccountsOpts.EmitPoliciesDir); err != nil {
+			return "", errors.Wrapf(err, "error while emitting policies for review for %s", credReq.Name)
+		}
+	}
+
 	var encodedCredentialsConfig string
 	switch generateOnly {
 	case true:
@@ -163,7 +284,11 @@ func createServiceAccount(ctx context.Context, client gcp.Client, name string, c
 		svcAcctBindingName := "serviceAccount:<POPULATE_SERVICE_ACCOUNT_EMAIL>"
 		var addPolicyBindingCmds []string
 		for _, role := range gcpProviderSpec.PredefinedRoles {
-			addPolicyBindingCmds = append(addPolicyBindingCmds, fmt.Sprintf(addPolicyBindingForProjectCmd, project, svcAcctBindingName, role))
+			cmd := fmt.Sprintf(addPolicyBindingForProjectCmd, project, svcAcctBindingName, role)
+			if bindingCondition != nil {
+				cmd = fmt.Sprintf("%s --condition=expression=%s,title=%s", cmd, shellQuote(bindingCondition.Expression), shellQuote(bindingCondition.Title))
+			}
+			addPolicyBindingCmds = append(addPolicyBindingCmds, cmd)
 		}
 		// commands to add bindings for workload identity user role to service account
 		for _, identityPoolBindingName := range identityProviderBindingNames {
@@ -181,7 +306,7 @@ func createServiceAccount(ctx context.Context, client gcp.Client, name string, c
 		// the IAM service account
 		credentialsConfigFilePath := filepath.Join(targetDir, "credentials_configurations", fmt.Sprintf("%s_credentials_config.json", serviceAccountName))
 		generateCredentialsConfigScript := createShellScript([]string{
-			fmt.Sprintf(generateCredentialsConfigCmd, project, workloadIdentityPool, workloadIdentityProvider, credentialsConfigFilePath),
+			fmt.Sprintf(generateCredentialsConfigCmd, identityPoolProject, workloadIdentityPool, workloadIdentityProvider, credentialsConfigFilePath),
 		})
 		generateCredentialsConfigScriptName := fmt.Sprintf(generateCredentialsConfigScriptName, serviceAccountNum, serviceAccountName)
 		generateCredentialsConfigScriptFullPath := filepath.Join(targetDir, generateCredentialsConfigScriptName)
@@ -205,8 +330,11 @@ func createServiceAccount(ctx context.Context, client gcp.Client, name string, c
 		serviceAccount, err = getServiceAccountByName(ctx, client, serviceAccountName)
 		if err != nil {
 			if strings.Contains(err.Error(), "not found") {
-				createdByCcoctlForSvcAcct := fmt.Sprintf("%s for service account %s", createdByCcoctl, serviceAccountName)
-				serviceAccount, err = actuator.CreateServiceAccount(client, serviceAccountID, serviceAccountName, createdByCcoctlForSvcAcct, project)
+				// Describe the service account with the cluster and source CredentialsRequest it was
+				// created for, so auditors reviewing the dozens of SAs a cluster creates don't have to
+				// reverse-engineer the purpose of each one from its (possibly truncated) display name.
+				svcAcctDescription := fmt.Sprintf("%s for cluster %q, CredentialsRequest %s/%s", createdByCcoctl, name, credReq.Namespace, credReq.Name)
+				serviceAccount, err = actuator.CreateServiceAccount(client, serviceAccountID, serviceAccountName, svcAcctDescription, project)
 				if err != nil {
 					return "", errors.Wrap(err, "Failed to create IAM service account")
 				}
@@ -220,21 +348,30 @@ func createServiceAccount(ctx context.Context, client gcp.Client, name string, c
 
 		// Add member <-> role bindings for the project
 		svcAcctBindingName := actuator.ServiceAccountBindingName(serviceAccount)
-		err = actuator.EnsurePolicyBindingsForProject(client, gcpProviderSpec.PredefinedRoles, svcAcctBindingName)
+		err = actuator.EnsurePolicyBindingsForProject(client, gcpProviderSpec.PredefinedRoles, svcAcctBindingName, bindingCondition)
 		if err != nil {
 			return "", errors.Wrap(err, fmt.Sprintf("Failed to add predefined roles for IAM service account %s", serviceAccount.DisplayName))
 		}
 
-		// Add member <-> role bindings for the IAM service account
-		for _, identityProvideBindingName := range identityProviderBindingNames {
-			err = actuator.EnsurePolicyBindingsForServiceAccount(client, serviceAccount, []string{workloadIdentityUserRole}, identityProvideBindingName)
-			if err != nil {
-				return "", errors.Wrap(err, fmt.Sprintf("Failed to add workload identity user role for IAM service account %s", serviceAccount.DisplayName))
+		// Add member <-> role bindings for the IAM service account, granting the workload
+		// identity pool permission to impersonate it. Not needed with
+		// --static-key-secret-manager-project, since that mode authenticates with a long-lived
+		// key instead of federation.
+		if CreateServiceAccountsOpts.StaticKeySecretManagerProject == "" {
+			for _, identityProvideBindingName := range identityProviderBindingNames {
+				err = actuator.EnsurePolicyBindingsForServiceAccount(client, serviceAccount, []string{workloadIdentityUserRole}, identityProvideBindingName)
+				if err != nil {
+					return "", errors.Wrap(err, fmt.Sprintf("Failed to add workload identity user role for IAM service account %s", serviceAccount.DisplayName))
+				}
 			}
 		}
 
 		log.Printf("Updated policy bindings for IAM service account %s", serviceAccount.DisplayName)
 
+		if CreateServiceAccountsOpts.StaticKeySecretManagerProject != "" {
+			return "", createStaticKeyAndWriteSecretManagerRef(ctx, client, credReq, serviceAccount, targetDir)
+		}
+
 		projectNumStr := fmt.Sprint(projectNum)
 		credentialsConfig := fmt.Sprintf(credentialsConfigTemplate, projectNumStr, workloadIdentityPool, workloadIdentityProvider, serviceAccount.Email, provisioning.OidcTokenPath)
 		encodedCredentialsConfig = base64.StdEncoding.EncodeToString([]byte(credentialsConfig))
@@ -246,11 +383,44 @@ func createServiceAccount(ctx context.Context, client gcp.Client, name string, c
 	return "", nil
 }
 
+// emitPoliciesFilenameFormat names the file --emit-policies-dir writes per CredentialsRequest
+const emitPoliciesFilenameFormat = "%s-%s-policies.json"
+
+// emitPoliciesForReview writes the predefined IAM roles (and binding condition, if any) that
+// would be bound to credReq's service account to a JSON file under policiesDir, for a security
+// team to review before anything is created in Google Cloud. It runs regardless of --dry-run, so
+// it can pair with a normal (non-dry-run) invocation to produce an audit trail alongside the
+// service accounts actually created.
+func emitPoliciesForReview(credReq *credreqv1.CredentialsRequest, serviceAccountID string, predefinedRoles []string, bindingCondition *cloudresourcemanager.Expr, policiesDir string) error {
+	policies := map[string]interface{}{
+		"ServiceAccountID": serviceAccountID,
+		"PredefinedRoles":  predefinedRoles,
+	}
+	if bindingCondition != nil {
+		policies["BindingCondition"] = bindingCondition
+	}
+	policiesJSON, err := json.MarshalIndent(&policies, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to convert policies to JSON")
+	}
+
+	policiesFilename := fmt.Sprintf(emitPoliciesFilenameFormat, credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name)
+	policiesFullPath := filepath.Join(policiesDir, policiesFilename)
+	log.Printf("Saving policies for %s/%s locally at %s for review", credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name, policiesFullPath)
+	return ioutil.WriteFile(policiesFullPath, policiesJSON, fileModeCcoctlDryRun)
+}
+
 // createShellScript creates a shell script given commands to execute
 func createShellScript(commands []string) string {
 	return fmt.Sprintf("#!/bin/sh\n%s", strings.Join(commands, "\n"))
 }
 
+// shellQuote wraps s in single quotes for safe inclusion in a generated shell script, escaping any
+// single quotes already present in s.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // getProjectNumber fetches project number given project name
 func getProjectNumber(ctx context.Context, client gcp.Client, projectName string) (int64, error) {
 	project, err := client.GetProject(ctx, projectName)
@@ -318,6 +488,44 @@ func writeCredReqSecret(cr *credreqv1.CredentialsRequest, targetDir, encodedCred
 	return nil
 }
 
+// createStaticKeyAndWriteSecretManagerRef mints a long-lived IAM service account JSON key for
+// serviceAccount, for teams that must still use static SA keys instead of workload identity
+// federation, and writes an external-secrets.io SecretStore/ExternalSecret pair pointing at GCP
+// Secret Manager in place of a plaintext Secret manifest. The key itself is saved to a local file
+// under targetDir rather than pushed to Secret Manager directly, since this build has no Secret
+// Manager SDK vendored to do that for the caller; see provisioning.PushCredentialToBackend.
+func createStaticKeyAndWriteSecretManagerRef(ctx context.Context, client gcp.Client, credReq *credreqv1.CredentialsRequest, serviceAccount *iamadminpb.ServiceAccount, targetDir string) error {
+	key, err := client.CreateServiceAccountKey(ctx, &iamadminpb.CreateServiceAccountKeyRequest{Name: serviceAccount.Name})
+	if err != nil {
+		return errors.Wrap(err, "Failed to create IAM service account key")
+	}
+
+	pendingKeysDir := filepath.Join(targetDir, staticKeysPendingDirName)
+	if err := provisioning.EnsureDir(pendingKeysDir); err != nil {
+		return errors.Wrap(err, "Failed to create directory for pending Secret Manager keys")
+	}
+
+	keyFileName := fmt.Sprintf("%s-%s-key.json", credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name)
+	keyFilePath := filepath.Join(pendingKeysDir, keyFileName)
+	if err := ioutil.WriteFile(keyFilePath, key.PrivateKeyData, 0600); err != nil {
+		return errors.Wrap(err, "Failed to save IAM service account key locally")
+	}
+
+	manifestsDir := filepath.Join(targetDir, provisioning.ManifestsDirName)
+	backendRef := fmt.Sprintf("%s/%s", credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name)
+	storeFilePath := filepath.Join(manifestsDir, fmt.Sprintf("%s-%s-secretstore.yaml", credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name))
+	secretFilePath := filepath.Join(manifestsDir, fmt.Sprintf("%s-%s-credentials.yaml", credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name))
+	if err := provisioning.WriteExternalSecretManifests(provisioning.BackendGCPSecretManager, CreateServiceAccountsOpts.StaticKeySecretManagerProject,
+		credReq.Spec.SecretRef.Namespace, credReq.Spec.SecretRef.Name, backendRef, []string{serviceAccountJSONSecretKey}, storeFilePath, secretFilePath); err != nil {
+		return errors.Wrap(err, "Failed to save ExternalSecret manifests")
+	}
+
+	log.Printf("Saved IAM service account key locally to %s; push its contents to GCP Secret Manager under %q in project %s (this build cannot do so automatically), then delete the local file and apply %s and %s",
+		keyFilePath, backendRef, CreateServiceAccountsOpts.StaticKeySecretManagerProject, storeFilePath, secretFilePath)
+
+	return nil
+}
+
 func createServiceAccountsCmd(cmd *cobra.Command, args []string) {
 	ctx := context.Background()
 
@@ -331,9 +539,22 @@ func createServiceAccountsCmd(cmd *cobra.Command, args []string) {
 		log.Fatal(err)
 	}
 
-	err = createServiceAccounts(ctx, gcpClient, CreateServiceAccountsOpts.Name, CreateServiceAccountsOpts.WorkloadIdentityPool,
+	identityPoolProject := identityPoolProjectOrDefault(CreateWorkloadIdentityProviderOpts)
+
+	bindingConditions, err := parseBindingConditions(CreateServiceAccountsOpts.BindingConditions)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if CreateServiceAccountsOpts.EmitPoliciesDir != "" {
+		if err := provisioning.EnsureDir(CreateServiceAccountsOpts.EmitPoliciesDir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = createServiceAccounts(ctx, gcpClient, CreateServiceAccountsOpts.Name, identityPoolProject, CreateServiceAccountsOpts.WorkloadIdentityPool,
 		CreateServiceAccountsOpts.WorkloadIdentityProvider, CreateServiceAccountsOpts.CredRequestDir, CreateServiceAccountsOpts.TargetDir,
-		CreateServiceAccountsOpts.EnableTechPreview, CreateServiceAccountsOpts.DryRun)
+		CreateServiceAccountsOpts.EnableTechPreview, CreateServiceAccountsOpts.DryRun, bindingConditions)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -381,17 +602,22 @@ func NewCreateServiceAccountsCmd() *cobra.Command {
 
 	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.Name, "name", "", "User-defined name for all created google cloud resources (can be separate from the cluster's infra-id)")
 	createServiceAccountsCmd.MarkPersistentFlagRequired("name")
-	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create gcp service accounts for (can be created by running 'oc adm release extract --credentials-requests --cloud=gcp' against an OpenShift release image)")
+	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create gcp service accounts for (can be created by running 'oc adm release extract --credentials-requests --cloud=gcp' against an OpenShift release image). Accepts a comma-separated list of directories to merge")
 	createServiceAccountsCmd.MarkPersistentFlagRequired("credentials-requests-dir")
 	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.WorkloadIdentityPool, "workload-identity-pool", "", "ID of workload identity pool (can be created with the 'create-workload-identity-pool' sub-command)")
 	createServiceAccountsCmd.MarkPersistentFlagRequired("workload-identity-pool")
 	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.WorkloadIdentityProvider, "workload-identity-provider", "", "ID of workload identity provider (can be created with the 'create-workload-identity-pool' sub-command)")
 	createServiceAccountsCmd.MarkPersistentFlagRequired("workload-identity-provider")
 	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.Project, "project", "", "ID of the google cloud project")
+	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.IdentityPoolProject, "identity-pool-project", "", "ID of the Google cloud project the workload identity pool was created in via --identity-pool-project of create-workload-identity-pool (defaults to --project)")
 	createServiceAccountsCmd.MarkPersistentFlagRequired("project")
 	createServiceAccountsCmd.PersistentFlags().BoolVar(&CreateServiceAccountsOpts.DryRun, "dry-run", false, "Skip creating objects, and just save what would have been created into files")
 	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
 	createServiceAccountsCmd.PersistentFlags().BoolVar(&CreateServiceAccountsOpts.EnableTechPreview, "enable-tech-preview", false, "Opt into processing CredentialsRequests marked as tech-preview")
+	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.SADisplayNameTemplate, "sa-display-name-template", "", "Go text/template used to render each IAM service account's display name, with .ClusterName and .CredReqName available (defaults to \"<ClusterName>-<CredReqName>\", truncated to fit). Truncated to GCP's 100 character display name limit with a stable hash suffix if needed")
+	createServiceAccountsCmd.PersistentFlags().StringSliceVar(&CreateServiceAccountsOpts.BindingConditions, "binding-condition", nil, "Comma-separated credReqName=expr pairs attaching a GCP IAM condition (a CEL expression) to the project role bindings created for that CredentialsRequest, for fine-grained (resource- or time-scoped) access. The expression is syntactically sanity-checked, not validated against GCP's CEL grammar")
+	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.StaticKeySecretManagerProject, "static-key-secret-manager-project", "", "For teams that must still use long-lived IAM service account keys instead of workload identity federation: mint a static JSON key per CredentialsRequest and emit a SecretStore/ExternalSecret manifest pair pointing at GCP Secret Manager in this project, instead of a credentials_config Secret. Not yet implemented: pushing the key into Secret Manager, since no Secret Manager SDK is vendored in this build; the key is saved locally for the operator to push by hand")
+	createServiceAccountsCmd.PersistentFlags().StringVar(&CreateServiceAccountsOpts.EmitPoliciesDir, "emit-policies-dir", "", "Write each CredentialsRequest's predefined IAM roles and binding condition to a JSON file in this directory for review, independent of --dry-run. Makes no additional Google Cloud API calls")
 
 	return createServiceAccountsCmd
 }
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -15,6 +16,7 @@ import (
 	"google.golang.org/api/iam/v1"
 	iamadminpb "google.golang.org/genproto/googleapis/iam/admin/v1"
 
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
 	mockgcp "github.com/openshift/cloud-credential-operator/pkg/gcp/mock"
 )
@@ -191,7 +193,7 @@ func TestCreateServiceAccounts(t *testing.T) {
 			require.NoError(t, err, "Unexpected error creating manifests dir for test")
 			defer os.RemoveAll(manifestsDir)
 
-			err = createServiceAccounts(context.TODO(), mockGCPClient, testName, testName, testName, credReqDir, targetDir, false, test.generateOnly)
+			err = createServiceAccounts(context.TODO(), mockGCPClient, testName, "", testName, testName, credReqDir, targetDir, false, test.generateOnly, nil)
 
 			if test.expectError {
 				require.Error(t, err, "expected error returned")
@@ -306,3 +308,212 @@ func mockSetServiceAccountIamPolicy(mockGCPClient *mockgcp.MockClient) {
 	mockGCPClient.EXPECT().SetServiceAccountIamPolicy(gomock.Any(), gomock.Any()).Return(
 		&iam.Policy{}, nil).Times(2)
 }
+
+// TestCreateServiceAccountsWithIdentityPoolProject verifies that when --identity-pool-project
+// names a host project separate from the service account's own project, the generated workload
+// identity bindings target the identity pool's project rather than the service account's.
+func TestCreateServiceAccountsWithIdentityPoolProject(t *testing.T) {
+	const testIdentityPoolProject = "test-identity-pool-project"
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockGCPClient := mockgcp.NewMockClient(mockCtrl)
+	mockGCPClient.EXPECT().GetProjectName().Return(testProject).Times(1)
+	mockGCPClient.EXPECT().GetProject(gomock.Any(), testIdentityPoolProject).Return(&cloudresourcemanager.Project{
+		Name:          testIdentityPoolProject,
+		ProjectNumber: testProjectNumber,
+	}, nil).Times(1)
+
+	credReqDir, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(credReqDir)
+	err = testCredentialsRequest(t, testCredReqName, testTargetNamespaceName, testTargetSecretName, credReqDir)
+	require.NoError(t, err, "Error while setting up test CredReq files")
+
+	targetDir, err := ioutil.TempDir(os.TempDir(), "create_service_account_test")
+	require.NoError(t, err, "Unexpected error creating target dir for test")
+	defer os.RemoveAll(targetDir)
+	manifestsDir := filepath.Join(targetDir, provisioning.ManifestsDirName)
+	err = provisioning.EnsureDir(manifestsDir)
+	require.NoError(t, err, "Unexpected error creating manifests dir for test")
+
+	err = createServiceAccounts(context.TODO(), mockGCPClient, testName, testIdentityPoolProject, testName, testName, credReqDir, targetDir, false, true, nil)
+	require.NoError(t, err, "Unexpected error from createServiceAccounts")
+
+	generateCredsConfigScript := fmt.Sprintf(generateCredentialsConfigScriptName, 0, fmt.Sprintf("%s-%s", testName, testCredReqName))
+	content, err := ioutil.ReadFile(filepath.Join(targetDir, generateCredsConfigScript))
+	require.NoError(t, err, "expected generate-credentials-config script to have been written")
+	assert.Contains(t, string(content), fmt.Sprintf("projects/%s/locations/global/workloadIdentityPools", testIdentityPoolProject))
+	assert.NotContains(t, string(content), fmt.Sprintf("projects/%s/locations/global/workloadIdentityPools", testProject))
+}
+
+func TestRenderServiceAccountDisplayName(t *testing.T) {
+	tests := []struct {
+		name        string
+		tmpl        string
+		clusterName string
+		credReqName string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:        "no template falls back to default naming scheme",
+			tmpl:        "",
+			clusterName: testName,
+			credReqName: testCredReqName,
+			expected:    fmt.Sprintf("%s-%s", testName, testCredReqName),
+		},
+		{
+			name:        "template is rendered with cluster and CredentialsRequest name",
+			tmpl:        "{{.ClusterName}}/{{.CredReqName}}",
+			clusterName: testName,
+			credReqName: testCredReqName,
+			expected:    fmt.Sprintf("%s/%s", testName, testCredReqName),
+		},
+		{
+			name:        "invalid template returns an error",
+			tmpl:        "{{.NotAField}}",
+			clusterName: testName,
+			credReqName: testCredReqName,
+			expectError: true,
+		},
+		{
+			name:        "overlong rendered name is truncated with a stable hash suffix",
+			tmpl:        strings.Repeat("x", maxServiceAccountDisplayNameLen+20),
+			clusterName: testName,
+			credReqName: testCredReqName,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := renderServiceAccountDisplayName(test.tmpl, test.clusterName, test.credReqName)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(result), maxServiceAccountDisplayNameLen)
+			if test.expected != "" {
+				assert.Equal(t, test.expected, result)
+			}
+
+			// Rendering again with the same inputs must produce the same name, since it doubles as
+			// the key used to detect an already-created service account on a re-run.
+			result2, err := renderServiceAccountDisplayName(test.tmpl, test.clusterName, test.credReqName)
+			require.NoError(t, err)
+			assert.Equal(t, result, result2)
+		})
+	}
+}
+
+func TestParseBindingConditions(t *testing.T) {
+	tests := []struct {
+		name      string
+		entries   []string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name:     "empty",
+			entries:  nil,
+			expected: map[string]string{},
+		},
+		{
+			name:     "valid entries",
+			entries:  []string{`cr-one=resource.name.startsWith("projects/_/buckets/prod")`},
+			expected: map[string]string{"cr-one": `resource.name.startsWith("projects/_/buckets/prod")`},
+		},
+		{
+			name:      "missing equals sign",
+			entries:   []string{"cr-one"},
+			expectErr: true,
+		},
+		{
+			name:      "empty expression",
+			entries:   []string{"cr-one="},
+			expectErr: true,
+		},
+		{
+			name:      "unbalanced parens",
+			entries:   []string{`cr-one=resource.name.startsWith("prod"`},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conditions, err := parseBindingConditions(test.entries)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, conditions)
+		})
+	}
+}
+
+func TestCreateStaticKeyAndWriteSecretManagerRef(t *testing.T) {
+	CreateServiceAccountsOpts.StaticKeySecretManagerProject = "test-secrets-project"
+	defer func() { CreateServiceAccountsOpts.StaticKeySecretManagerProject = "" }()
+
+	tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tempDirName)
+	require.NoError(t, provisioning.EnsureDir(filepath.Join(tempDirName, provisioning.ManifestsDirName)))
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockGCPClient := mockgcp.NewMockClient(mockCtrl)
+	mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), gomock.Any()).Return(&iamadminpb.ServiceAccountKey{
+		PrivateKeyData: []byte(`{"type": "service_account"}`),
+	}, nil)
+
+	credReq := &credreqv1.CredentialsRequest{}
+	credReq.Spec.SecretRef.Namespace = testTargetNamespaceName
+	credReq.Spec.SecretRef.Name = testTargetSecretName
+
+	serviceAccount := &iamadminpb.ServiceAccount{
+		Name: "projects/testproject/serviceAccounts/testsa@testproject.iam.gserviceaccount.com",
+	}
+
+	err = createStaticKeyAndWriteSecretManagerRef(context.TODO(), mockGCPClient, credReq, serviceAccount, tempDirName)
+	require.NoError(t, err)
+
+	keyFilePath := filepath.Join(tempDirName, staticKeysPendingDirName, fmt.Sprintf("%s-%s-key.json", testTargetNamespaceName, testTargetSecretName))
+	keyData, err := ioutil.ReadFile(keyFilePath)
+	require.NoError(t, err, "expected the IAM service account key to be saved locally")
+	assert.JSONEq(t, `{"type": "service_account"}`, string(keyData))
+
+	manifestsDir := filepath.Join(tempDirName, provisioning.ManifestsDirName)
+	assert.FileExists(t, filepath.Join(manifestsDir, fmt.Sprintf("%s-%s-secretstore.yaml", testTargetNamespaceName, testTargetSecretName)))
+	assert.FileExists(t, filepath.Join(manifestsDir, fmt.Sprintf("%s-%s-credentials.yaml", testTargetNamespaceName, testTargetSecretName)))
+}
+
+func TestEmitPoliciesForReview(t *testing.T) {
+	tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tempDirName)
+
+	credReq := &credreqv1.CredentialsRequest{}
+	credReq.Spec.SecretRef.Namespace = testTargetNamespaceName
+	credReq.Spec.SecretRef.Name = testTargetSecretName
+
+	bindingCondition := &cloudresourcemanager.Expr{
+		Expression: "resource.name == \"test\"",
+		Title:      "test-condition",
+	}
+
+	err = emitPoliciesForReview(credReq, "test-sa-id", []string{"roles/viewer", "roles/editor"}, bindingCondition, tempDirName)
+	require.NoError(t, err)
+
+	policiesFilePath := filepath.Join(tempDirName, fmt.Sprintf("%s-%s-policies.json", testTargetNamespaceName, testTargetSecretName))
+	policiesData, err := ioutil.ReadFile(policiesFilePath)
+	require.NoError(t, err, "expected the policies file to be saved locally")
+	assert.JSONEq(t, `{
+		"ServiceAccountID": "test-sa-id",
+		"PredefinedRoles": ["roles/viewer", "roles/editor"],
+		"BindingCondition": {"expression": "resource.name == \"test\"", "title": "test-condition"}
+	}`, string(policiesData))
+}
@@ -46,17 +46,29 @@ func createWorkloadIdentityPoolCmd(cmd *cobra.Command, args []string) {
 		log.Fatalf("Failed to load credentials: %s", err)
 	}
 
-	gcpClient, err := gcp.NewClient(CreateWorkloadIdentityPoolOpts.Project, creds.JSON)
+	identityPoolProject := identityPoolProjectOrDefault(CreateWorkloadIdentityPoolOpts)
+
+	gcpClient, err := gcp.NewClient(identityPoolProject, creds.JSON)
 	if err != nil {
 		log.Fatalf("Failed to setup GCP client: %s", err)
 	}
 
-	err = createWorkloadIdentityPool(ctx, gcpClient, CreateWorkloadIdentityPoolOpts.Name, CreateWorkloadIdentityPoolOpts.Project, CreateWorkloadIdentityPoolOpts.TargetDir, CreateWorkloadIdentityPoolOpts.DryRun)
+	err = createWorkloadIdentityPool(ctx, gcpClient, CreateWorkloadIdentityPoolOpts.Name, identityPoolProject, CreateWorkloadIdentityPoolOpts.TargetDir, CreateWorkloadIdentityPoolOpts.DryRun)
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
+// identityPoolProjectOrDefault returns the host/identity project the workload identity pool and
+// provider should be created in: --identity-pool-project when set, so the pool can be shared by
+// service accounts that live in separate per-cluster projects, or --project otherwise.
+func identityPoolProjectOrDefault(opts options) string {
+	if opts.IdentityPoolProject != "" {
+		return opts.IdentityPoolProject
+	}
+	return opts.Project
+}
+
 // validationForCreateWorkloadIdentityPoolCmd will validate the arguments to the command, ensure the destination directory
 // is ready to receive the generated files, and will create the directory if necessary.
 func validationForCreateWorkloadIdentityPoolCmd(cmd *cobra.Command, args []string) {
@@ -144,6 +156,7 @@ func NewCreateWorkloadIdentityPool() *cobra.Command {
 	createWorkloadIdentityPoolCmd.MarkPersistentFlagRequired("name")
 	createWorkloadIdentityPoolCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityPoolOpts.Project, "project", "", "ID of the Google cloud project")
 	createWorkloadIdentityPoolCmd.MarkPersistentFlagRequired("project")
+	createWorkloadIdentityPoolCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityPoolOpts.IdentityPoolProject, "identity-pool-project", "", "ID of the Google cloud project to create the workload identity pool in, when it should be centrally managed in a host project separate from --project (defaults to --project)")
 	createWorkloadIdentityPoolCmd.PersistentFlags().BoolVar(&CreateWorkloadIdentityPoolOpts.DryRun, "dry-run", false, "Skip creating objects, and just save what would have been created into files")
 	createWorkloadIdentityPoolCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityPoolOpts.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
 
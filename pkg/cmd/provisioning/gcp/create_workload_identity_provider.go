@@ -70,39 +70,59 @@ func createWorkloadIdentityProviderCmd(cmd *cobra.Command, args []string) {
 		publicKeyPath = filepath.Join(CreateWorkloadIdentityProviderOpts.TargetDir, provisioning.PublicKeyFile)
 	}
 
-	err = createWorkloadIdentityProvider(ctx, gcpClient, CreateWorkloadIdentityProviderOpts.Name, CreateWorkloadIdentityProviderOpts.Region, CreateWorkloadIdentityProviderOpts.Project, CreateWorkloadIdentityProviderOpts.WorkloadIdentityPool, publicKeyPath, CreateWorkloadIdentityProviderOpts.TargetDir, CreateWorkloadIdentityProviderOpts.DryRun)
+	identityPoolProject := identityPoolProjectOrDefault(CreateWorkloadIdentityProviderOpts)
+
+	err = createWorkloadIdentityProvider(ctx, gcpClient, CreateWorkloadIdentityProviderOpts.Name, CreateWorkloadIdentityProviderOpts.Region, CreateWorkloadIdentityProviderOpts.Project, identityPoolProject, CreateWorkloadIdentityProviderOpts.WorkloadIdentityPool, publicKeyPath, CreateWorkloadIdentityProviderOpts.TargetDir, CreateWorkloadIdentityProviderOpts.IssuerURI, CreateWorkloadIdentityProviderOpts.AllowedAudiences, CreateWorkloadIdentityProviderOpts.DryRun)
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func createWorkloadIdentityProvider(ctx context.Context, client gcp.Client, name, region, project, workloadIdentityPool string, publicKeyPath, targetDir string, generateOnly bool) error {
-	// Create a storage bucket
-	bucketName := fmt.Sprintf("%s-oidc", name)
-	if err := createOIDCBucket(ctx, client, bucketName, region, project, targetDir, generateOnly); err != nil {
-		return err
-	}
-	issuerURL := fmt.Sprintf("https://storage.googleapis.com/%s", bucketName)
+// createWorkloadIdentityProvider creates the workload identity provider itself in
+// identityPoolProject, the project hosting workloadIdentityPool. The two are the same project
+// unless --identity-pool-project was used to centralize the pool and provider in a host project
+// shared across per-cluster service projects.
+//
+// By default the provider trusts the cluster's own OIDC issuer: ccoctl creates the OIDC bucket
+// in project, populates it, and the installer manifest recording the issuer for cluster credential
+// federation. Passing issuerURI skips all of that and instead creates a provider trusting that
+// external issuer (e.g. a GitHub Actions or GitLab CI OIDC issuer) for allowedAudiences, so the
+// same tooling can set up CI federation alongside the cluster's own.
+func createWorkloadIdentityProvider(ctx context.Context, client gcp.Client, name, region, project, identityPoolProject, workloadIdentityPool string, publicKeyPath, targetDir, issuerURI string, allowedAudiences []string, generateOnly bool) error {
+	issuerURL := issuerURI
+	if issuerURL == "" {
+		// Create a storage bucket
+		bucketName := fmt.Sprintf("%s-oidc", name)
+		if err := createOIDCBucket(ctx, client, bucketName, region, project, targetDir, generateOnly); err != nil {
+			return err
+		}
+		issuerURL = fmt.Sprintf("https://storage.googleapis.com/%s", bucketName)
 
-	// Create the OIDC config file
-	if err := createOIDCConfiguration(ctx, client, bucketName, issuerURL, targetDir, generateOnly); err != nil {
-		return err
+		// Create the OIDC config file
+		if err := createOIDCConfiguration(ctx, client, bucketName, issuerURL, targetDir, generateOnly); err != nil {
+			return err
+		}
+
+		// Create the OIDC key list
+		if err := createJSONWebKeySet(ctx, client, publicKeyPath, bucketName, targetDir, generateOnly); err != nil {
+			return err
+		}
 	}
 
-	// Create the OIDC key list
-	if err := createJSONWebKeySet(ctx, client, publicKeyPath, bucketName, targetDir, generateOnly); err != nil {
-		return err
+	if len(allowedAudiences) == 0 {
+		allowedAudiences = []string{openShiftAudience}
 	}
 
 	// Create the workload identity provider
-	err := createIdentityProvider(ctx, client, name, project, issuerURL, workloadIdentityPool, targetDir, generateOnly)
-	if err != nil {
+	if err := createIdentityProvider(ctx, client, name, identityPoolProject, issuerURL, workloadIdentityPool, allowedAudiences, targetDir, generateOnly); err != nil {
 		return err
 	}
 
-	// Create the installer manifest file
-	if err := provisioning.CreateClusterAuthentication(issuerURL, targetDir); err != nil {
-		return err
+	if issuerURI == "" {
+		// Create the installer manifest file
+		if err := provisioning.CreateClusterAuthentication(issuerURL, targetDir); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -197,11 +217,11 @@ func createJSONWebKeySet(ctx context.Context, client gcp.Client, publicKeyFilepa
 	return nil
 }
 
-func createIdentityProvider(ctx context.Context, client gcp.Client, name, project, issuerURL, workloadIdentityPool, targetDir string, generateOnly bool) error {
+func createIdentityProvider(ctx context.Context, client gcp.Client, name, project, issuerURL, workloadIdentityPool string, allowedAudiences []string, targetDir string, generateOnly bool) error {
 	if generateOnly {
 		createIdentityProviderScript := provisioning.CreateShellScript([]string{createIdentityProviderCmd})
 		createIdentityProviderScriptFilepath := filepath.Join(targetDir, createIdentityProviderScriptName)
-		script := fmt.Sprintf(createIdentityProviderScript, name, workloadIdentityPool, name, createdByCcoctl, issuerURL, openShiftAudience)
+		script := fmt.Sprintf(createIdentityProviderScript, name, workloadIdentityPool, name, createdByCcoctl, issuerURL, strings.Join(allowedAudiences, ","))
 		log.Printf("Saving shell script to create workload identity provider locally at %s", createIdentityProviderScriptFilepath)
 		if err := ioutil.WriteFile(createIdentityProviderScriptFilepath, []byte(script), fileModeCcoctlDryRun); err != nil {
 			return errors.Wrap(err, fmt.Sprintf("Failed to save shell script to create workload identity provider locally at %s", createIdentityProviderScriptFilepath))
@@ -218,7 +238,7 @@ func createIdentityProvider(ctx context.Context, client gcp.Client, name, projec
 					State:       "ACTIVE",
 					Disabled:    false,
 					Oidc: &iam.Oidc{
-						AllowedAudiences: []string{openShiftAudience},
+						AllowedAudiences: allowedAudiences,
 						IssuerUri:        issuerURL,
 					},
 					AttributeMapping: map[string]string{
@@ -294,11 +314,14 @@ func NewCreateWorkloadIdentityProviderCmd() *cobra.Command {
 	createWorkloadIdentityProviderCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.Region, "region", "us", "Google cloud region where the Google Storage Bucket holding the OpenID Connect configuration will be created")
 	createWorkloadIdentityProviderCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.Project, "project", "", "ID of the Google cloud project")
 	createWorkloadIdentityProviderCmd.MarkPersistentFlagRequired("project")
+	createWorkloadIdentityProviderCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.IdentityPoolProject, "identity-pool-project", "", "ID of the Google cloud project the workload identity pool was created in via --identity-pool-project of create-workload-identity-pool (defaults to --project)")
 	createWorkloadIdentityProviderCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.WorkloadIdentityPool, "workload-identity-pool", "", "Pool to create this provider in")
 	createWorkloadIdentityProviderCmd.MarkPersistentFlagRequired("workload-identity-pool")
 	createWorkloadIdentityProviderCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.PublicKeyPath, "public-key-file", "", "Path to public ServiceAccount signing key")
 	createWorkloadIdentityProviderCmd.PersistentFlags().BoolVar(&CreateWorkloadIdentityProviderOpts.DryRun, "dry-run", false, "Skip creating objects, and just save what would have been created into files")
 	createWorkloadIdentityProviderCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
+	createWorkloadIdentityProviderCmd.PersistentFlags().StringVar(&CreateWorkloadIdentityProviderOpts.IssuerURI, "issuer-uri", "", "OIDC issuer URI the provider should trust, for federating with an external issuer (e.g. a GitHub Actions or GitLab CI OIDC issuer) instead of the cluster's own. Defaults to creating and trusting the cluster's own OIDC issuer")
+	createWorkloadIdentityProviderCmd.PersistentFlags().StringSliceVar(&CreateWorkloadIdentityProviderOpts.AllowedAudiences, "allowed-audience", nil, "Comma-separated list of acceptable values for the OIDC token's `aud` field. Defaults to \"openshift\", the only audience used by OpenShift components; set this when --issuer-uri is an external issuer whose tokens carry a different audience")
 
 	return createWorkloadIdentityProviderCmd
 }
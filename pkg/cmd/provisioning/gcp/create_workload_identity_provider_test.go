@@ -48,13 +48,15 @@ const (
 func TestCreateWorkloadIdentityProvider(t *testing.T) {
 
 	tests := []struct {
-		name          string
-		mockGCPClient func(mockCtrl *gomock.Controller) *mockgcp.MockClient
-		setup         func(*testing.T) string
-		verify        func(t *testing.T, tempDirName string)
-		cleanup       func(*testing.T)
-		generateOnly  bool
-		expectError   bool
+		name             string
+		mockGCPClient    func(mockCtrl *gomock.Controller) *mockgcp.MockClient
+		setup            func(*testing.T) string
+		verify           func(t *testing.T, tempDirName string)
+		cleanup          func(*testing.T)
+		issuerURI        string
+		allowedAudiences []string
+		generateOnly     bool
+		expectError      bool
 	}{
 		{
 			name: "Public key not found",
@@ -176,6 +178,27 @@ func TestCreateWorkloadIdentityProvider(t *testing.T) {
 			generateOnly: true,
 			expectError:  false,
 		},
+		{
+			name: "external issuer skips bucket creation and uses given audiences",
+			mockGCPClient: func(mockCtrl *gomock.Controller) *mockgcp.MockClient {
+				mockGCPClient := mockgcp.NewMockClient(mockCtrl)
+				mockGetWorkloadIdentityProviderFailure(mockGCPClient)
+				mockCreateWorkloadIdentityProviderSuccess(mockGCPClient)
+				return mockGCPClient
+			},
+			setup: func(t *testing.T) string {
+				tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+				require.NoError(t, err, "Failed to create temp directory")
+				return tempDirName
+			},
+			verify: func(t *testing.T, tempDirName string) {
+				_, err := os.Stat(filepath.Join(tempDirName, gcpOidcConfigurationFilename))
+				assert.True(t, os.IsNotExist(err), "expected no discovery document to be generated for an external issuer")
+			},
+			issuerURI:        "https://token.actions.githubusercontent.com",
+			allowedAudiences: []string{"sts.amazonaws.com"},
+			expectError:      false,
+		},
 	}
 
 	for _, test := range tests {
@@ -189,7 +212,7 @@ func TestCreateWorkloadIdentityProvider(t *testing.T) {
 			defer os.RemoveAll(tempDirName)
 
 			testPublicKeyPath := filepath.Join(tempDirName, testPublicKeyFile)
-			err := createWorkloadIdentityProvider(context.TODO(), mockGCPClient, testInfraName, testRegionName, testProject, testName, testPublicKeyPath, tempDirName, test.generateOnly)
+			err := createWorkloadIdentityProvider(context.TODO(), mockGCPClient, testInfraName, testRegionName, testProject, testProject, testName, testPublicKeyPath, tempDirName, test.issuerURI, test.allowedAudiences, test.generateOnly)
 
 			if test.expectError {
 				require.Error(t, err, "expected error returned")
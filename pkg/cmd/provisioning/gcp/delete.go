@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	iamadminpb "google.golang.org/genproto/googleapis/iam/admin/v1"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
 	"github.com/openshift/cloud-credential-operator/pkg/gcp"
 	"github.com/openshift/cloud-credential-operator/pkg/gcp/actuator"
 )
@@ -20,22 +24,30 @@ var (
 	DeleteOpts = options{}
 )
 
-// deleteOIDCObjectsFromBucket deletes the objects in OIDC cloud storage bucket
-func deleteOIDCObjectsFromBucket(ctx context.Context, client gcp.Client, bucketName, namePrefix string) error {
+// deleteOIDCObjectsFromBucket deletes the objects in OIDC cloud storage bucket. If
+// continueOnError is true, a failure to delete one object does not stop the rest from being
+// attempted; all errors are returned together at the end.
+func deleteOIDCObjectsFromBucket(ctx context.Context, client gcp.Client, bucketName, namePrefix string, continueOnError bool) error {
 	objectAttrs, err := client.ListObjects(ctx, bucketName)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to list objects from bucket %s", bucketName)
 	}
 
+	var errs []error
 	for _, attr := range objectAttrs {
 		err := client.DeleteObject(ctx, bucketName, attr.Name)
 		if err != nil {
-			return errors.Wrapf(err, "Failed to delete object %s from bucket %s", attr.Name, bucketName)
+			err = errors.Wrapf(err, "Failed to delete object %s from bucket %s", attr.Name, bucketName)
+			if !continueOnError {
+				return err
+			}
+			errs = append(errs, err)
+			continue
 		}
 		log.Printf("Deleted object %s from bucket %s", attr.Name, bucketName)
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
 }
 
 // deleteOIDCBucket deletes the OIDC cloud storage bucket
@@ -49,8 +61,10 @@ func deleteOIDCBucket(ctx context.Context, client gcp.Client, bucketName, namePr
 	return nil
 }
 
-// deleteServiceAccounts deletes the IAM service accounts created by ccoctl
-func deleteServiceAccounts(ctx context.Context, client gcp.Client, namePrefix string) error {
+// deleteServiceAccounts deletes the IAM service accounts created by ccoctl. If continueOnError is
+// true, a failure to delete one service account does not stop the rest from being attempted; all
+// errors are returned together at the end.
+func deleteServiceAccounts(ctx context.Context, client gcp.Client, namePrefix string, continueOnError bool) error {
 	projectName := client.GetProjectName()
 	projectResourceName := fmt.Sprintf("projects/%s", projectName)
 	listServiceAccountsRequest := &iamadminpb.ListServiceAccountsRequest{
@@ -61,23 +75,46 @@ func deleteServiceAccounts(ctx context.Context, client gcp.Client, namePrefix st
 	if err != nil {
 		return errors.Wrapf(err, "Failed to fetch list of service accounts")
 	}
+
+	var errs []error
 	for _, svcAcct := range svcAcctList {
 		if isCreatedByCcoctl(svcAcct.Email, namePrefix) || isCreatedByCcoctl(svcAcct.DisplayName, namePrefix) {
 			svcAcctBindingName := actuator.ServiceAccountBindingName(svcAcct)
 			err := actuator.RemovePolicyBindingsForProject(client, svcAcctBindingName)
 			if err != nil {
-				return errors.Wrapf(err, "Failed to remove project policy bindings for service account")
+				err = errors.Wrapf(err, "Failed to remove project policy bindings for service account")
+				if !continueOnError {
+					return err
+				}
+				errs = append(errs, err)
+				continue
 			}
 
 			if err := actuator.DeleteServiceAccount(client, svcAcct); err != nil {
-				return errors.Wrapf(err, "Failed to delete service account")
+				err = errors.Wrapf(err, "Failed to delete service account")
+				if !continueOnError {
+					return err
+				}
+				errs = append(errs, err)
+				continue
 			}
 
 			log.Printf("IAM Service account %s deleted", svcAcct.DisplayName)
 		}
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
+}
+
+// removeStaticKeySecretManagerEntries reminds the operator to remove the GCP Secret Manager entries
+// created by 'create-service-accounts --static-key-secret-manager-project'. Unlike
+// deleteServiceAccounts, this command only has the cluster's resource name prefix to go on, not the
+// per-CredentialsRequest secretRef each entry was stored under, and this build has no Secret
+// Manager SDK vendored to enumerate or remove entries itself; see provisioning.DeleteCredentialFromBackend.
+func removeStaticKeySecretManagerEntries(project, namePrefix string) {
+	if err := provisioning.DeleteCredentialFromBackend(provisioning.BackendGCPSecretManager, namePrefix); err != nil {
+		log.Printf("NOTE: %v (in project %s)", err, project)
+	}
 }
 
 // isCreatedByCcoctl checks if the google cloud resource is created by ccoctl based on the name prefix
@@ -113,20 +150,36 @@ func deleteCmd(cmd *cobra.Command, args []string) {
 
 	bucketName := fmt.Sprintf("%s-oidc", DeleteOpts.Name)
 
-	if err := deleteOIDCObjectsFromBucket(ctx, gcpClient, bucketName, DeleteOpts.Name); err != nil {
+	var errs []error
+
+	if err := deleteOIDCObjectsFromBucket(ctx, gcpClient, bucketName, DeleteOpts.Name, DeleteOpts.ContinueOnError); err != nil {
 		log.Print(err)
+		errs = append(errs, err)
 	}
 
 	if err := deleteOIDCBucket(ctx, gcpClient, bucketName, DeleteOpts.Name); err != nil {
 		log.Print(err)
+		errs = append(errs, err)
 	}
 
-	if err := deleteServiceAccounts(ctx, gcpClient, DeleteOpts.Name); err != nil {
+	if err := deleteServiceAccounts(ctx, gcpClient, DeleteOpts.Name, DeleteOpts.ContinueOnError); err != nil {
 		log.Print(err)
+		errs = append(errs, err)
 	}
 
 	if err := deleteWorkloadIdentityPool(ctx, gcpClient, DeleteOpts.Name); err != nil {
 		log.Print(err)
+		errs = append(errs, err)
+	}
+
+	if DeleteOpts.StaticKeySecretManagerProject != "" {
+		removeStaticKeySecretManagerEntries(DeleteOpts.StaticKeySecretManagerProject, DeleteOpts.Name)
+	}
+
+	if DeleteOpts.ContinueOnError {
+		if err := utilerrors.NewAggregate(errs); err != nil {
+			os.Exit(1)
+		}
 	}
 }
 
@@ -143,6 +196,8 @@ func NewDeleteCmd() *cobra.Command {
 	deleteCmd.MarkPersistentFlagRequired("name")
 	deleteCmd.PersistentFlags().StringVar(&DeleteOpts.Project, "project", "", "ID of the google cloud project")
 	deleteCmd.MarkPersistentFlagRequired("project")
+	deleteCmd.PersistentFlags().BoolVar(&DeleteOpts.ContinueOnError, "continue-on-error", false, "Continue deleting remaining resources when a failure is encountered, reporting all failures at the end")
+	deleteCmd.PersistentFlags().StringVar(&DeleteOpts.StaticKeySecretManagerProject, "static-key-secret-manager-project", "", "GCP project passed to 'create-service-accounts --static-key-secret-manager-project' for this cluster, so its Secret Manager entries can be flagged for manual removal")
 
 	return deleteCmd
 }
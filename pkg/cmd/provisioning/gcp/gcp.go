@@ -7,16 +7,25 @@ import (
 )
 
 type options struct {
-	TargetDir                string
-	PublicKeyPath            string
-	Region                   string
-	Name                     string
-	Project                  string
-	WorkloadIdentityPool     string
-	WorkloadIdentityProvider string
-	CredRequestDir           string
-	DryRun                   bool
-	EnableTechPreview        bool
+	TargetDir                     string
+	PublicKeyPath                 string
+	Region                        string
+	Name                          string
+	Project                       string
+	IdentityPoolProject           string
+	WorkloadIdentityPool          string
+	WorkloadIdentityProvider      string
+	CredRequestDir                string
+	DryRun                        bool
+	EnableTechPreview             bool
+	ContinueOnError               bool
+	SADisplayNameTemplate         string
+	BindingConditions             []string
+	StaticKeySecretManagerProject string
+	Config                        string
+	EmitPoliciesDir               string
+	IssuerURI                     string
+	AllowedAudiences              []string
 }
 
 // NewGCPCmd implements the "gcp" subcommand for the credentials provisioning
@@ -1,17 +1,30 @@
 package ibmcloud
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
-	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
-	"github.com/openshift/cloud-credential-operator/pkg/ibmcloud"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
+	"github.com/openshift/cloud-credential-operator/pkg/ibmcloud"
 )
 
+// retryBackoff is the base delay between retries of a failed Service ID creation, scaled
+// linearly by attempt number.
+const retryBackoff = 2 * time.Second
+
 // APIKeyEnvVars is a list of environment variable names containing an IBM Cloud API key
 var APIKeyEnvVars = []string{"IC_API_KEY", "IBMCLOUD_API_KEY", "BM_API_KEY", "BLUEMIX_API_KEY"}
 
@@ -36,6 +49,59 @@ func getEnv(envs []string) string {
 	return ""
 }
 
+// resolveAPIKey returns the IBM Cloud API key ccoctl should use, checking each supported source
+// in order and returning the first that yields a value:
+//  1. one of APIKeyEnvVars, kept first so existing env-var-based invocations are unaffected
+//  2. --api-key-from-secret, read from the referenced Secret via the in-cluster or kubeconfig
+//     client, for Job-style runs where injecting the key as an env var isn't desirable
+//
+// ccoctl does not support reading the API key from a local file.
+func resolveAPIKey(kubeconfigPath, apiKeyFromSecret string) (string, error) {
+	if apiKey := getEnv(APIKeyEnvVars); apiKey != "" {
+		return apiKey, nil
+	}
+
+	if apiKeyFromSecret != "" {
+		return getAPIKeyFromSecret(kubeconfigPath, apiKeyFromSecret)
+	}
+
+	return "", fmt.Errorf("no IBM Cloud API key found: set one of %v, or pass --api-key-from-secret", APIKeyEnvVars)
+}
+
+// getAPIKeyFromSecret reads an IBM Cloud API key out of a Kubernetes Secret referenced by ref, in
+// the form "namespace/name/key". kubeconfigPath may be empty to use the in-cluster config; the
+// KUBECONFIG env var is not consulted in that case.
+func getAPIKeyFromSecret(kubeconfigPath, ref string) (string, error) {
+	kubeClient, err := newClientset(kubeconfigPath)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create the kubernetes clientset")
+	}
+
+	return getAPIKeyFromSecretWithClient(kubeClient, ref)
+}
+
+// getAPIKeyFromSecretWithClient is the testable core of getAPIKeyFromSecret, taking an
+// already-constructed Kubernetes client so tests can substitute a fake one.
+func getAPIKeyFromSecretWithClient(kubeClient kubernetes.Interface, ref string) (string, error) {
+	parts := strings.SplitN(ref, "/", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("--api-key-from-secret must be of the form namespace/name/key, got %q", ref)
+	}
+	namespace, name, key := parts[0], parts[1], parts[2]
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get Secret %s/%s", namespace, name)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("Secret %s/%s has no key %q", namespace, name, key)
+	}
+
+	return string(value), nil
+}
+
 // NewCreateServiceIDCmd provides the "create-service-id" subcommand
 func NewCreateServiceIDCmd() *cobra.Command {
 	createServiceIDCmd := &cobra.Command{
@@ -47,19 +113,27 @@ func NewCreateServiceIDCmd() *cobra.Command {
 
 	createServiceIDCmd.PersistentFlags().StringVar(&Options.Name, "name", "", "User-defined name for all created IBM Cloud resources (can be separate from the cluster's infra-id)")
 	createServiceIDCmd.MarkPersistentFlagRequired("name")
-	createServiceIDCmd.PersistentFlags().StringVar(&Options.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=ibmcloud' against an OpenShift release image)")
+	createServiceIDCmd.PersistentFlags().StringVar(&Options.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to create IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=ibmcloud' against an OpenShift release image). Accepts a comma-separated list of directories to merge")
 	createServiceIDCmd.MarkPersistentFlagRequired("credentials-requests-dir")
 	createServiceIDCmd.PersistentFlags().StringVar(&Options.ResourceGroupName, "resource-group-name", "", "Name of the resource group used for scoping the access policies")
+	createServiceIDCmd.PersistentFlags().StringVar(&Options.Region, "region", "", "IBM Cloud region the created resources target. When set, it is also written into the generated Secret as ibmcloud_region, alongside ibmcloud_resource_group when --resource-group-name is set")
 	createServiceIDCmd.PersistentFlags().StringVar(&Options.TargetDir, "output-dir", "", "Directory to place generated files (defaults to current directory)")
 	createServiceIDCmd.PersistentFlags().BoolVar(&Options.EnableTechPreview, "enable-tech-preview", false, "Opt into processing CredentialsRequests marked as tech-preview")
+	createServiceIDCmd.PersistentFlags().BoolVar(&Options.Resume, "resume", false, "Resume a previous, partially-failed run by reusing any Service IDs that were already created instead of erroring out")
+	createServiceIDCmd.PersistentFlags().IntVar(&Options.RetryAttempts, "retry-attempts", 2, "Number of additional attempts to make for a Service ID that fails to be created before giving up")
+	createServiceIDCmd.PersistentFlags().BoolVar(&Options.AllowEmpty, "allow-empty", false, "Do not error out when --credentials-requests-dir contains no matching CredentialsRequests")
+	createServiceIDCmd.PersistentFlags().StringVar(&Options.APIKeyFromSecret, "api-key-from-secret", "", "Read the IBM Cloud API key from a Kubernetes Secret instead of an environment variable, given as namespace/name/key. Used only when none of "+fmt.Sprint(APIKeyEnvVars)+" is set")
+	createServiceIDCmd.PersistentFlags().StringVar(&Options.KubeConfigFile, "kubeconfig", "", "Path to the kubeconfig file to use when --api-key-from-secret is set (defaults to the in-cluster config)")
+	createServiceIDCmd.PersistentFlags().StringVar(&Options.Infrastructure, "infrastructure", InfrastructureVPC, "IBM Cloud infrastructure flavor the generated credentials target (vpc or classic). vpc requires IBMCloudProviderSpec CredentialsRequests; classic requires IBMCloudPowerVSProviderSpec CredentialsRequests, since Power Systems Virtual Server is hosted out of classic (non-VPC) datacenters. Also written into the generated Secret as ibmcloud_infrastructure")
+	createServiceIDCmd.PersistentFlags().BoolVar(&Options.StampMetadata, "stamp-metadata", false, "Annotate the generated Secret with when its API key was created and the key's IBM Cloud ID, for rotation governance tooling to alert on stale credentials and correlate a Secret with its cloud-side key")
 
 	return createServiceIDCmd
 }
 
 func createServiceIDCmd(cmd *cobra.Command, args []string) error {
-	apiKey := getEnv(APIKeyEnvVars)
-	if apiKey == "" {
-		return fmt.Errorf("%s environment variable not set", APIKeyEnvVars)
+	apiKey, err := resolveAPIKey(Options.KubeConfigFile, Options.APIKeyFromSecret)
+	if err != nil {
+		return provisioning.NewAuthFailureError(err)
 	}
 
 	params := &ibmcloud.ClientParams{
@@ -75,11 +149,11 @@ func createServiceIDCmd(cmd *cobra.Command, args []string) error {
 	apiKeyDetailsOptions.SetIamAPIKey(apiKey)
 	apiKeyDetails, _, err := ibmclient.GetAPIKeysDetails(apiKeyDetailsOptions)
 	if err != nil {
-		return errors.Wrap(err, "Failed to get Details for the given APIKey")
+		return provisioning.NewAuthFailureError(errors.Wrap(err, "Failed to get Details for the given APIKey"))
 	}
 
 	err = createServiceIDs(ibmclient, apiKeyDetails.AccountID, Options.Name, Options.ResourceGroupName,
-		Options.CredRequestDir, Options.TargetDir, Options.EnableTechPreview)
+		Options.CredRequestDir, Options.TargetDir, Options.EnableTechPreview, Options.Resume, Options.RetryAttempts, Options.Region, Options.AllowEmpty, Options.Infrastructure, Options.StampMetadata)
 	if err != nil {
 		return err
 	}
@@ -88,7 +162,11 @@ func createServiceIDCmd(cmd *cobra.Command, args []string) error {
 }
 
 func createServiceIDs(client ibmcloud.Client, accountID *string,
-	name, resourceGroupName, credReqDir, targetDir string, enableTechPreview bool) error {
+	name, resourceGroupName, credReqDir, targetDir string, enableTechPreview, resume bool, retryAttempts int, region string, allowEmpty bool, infrastructure string, stampMetadata bool) error {
+
+	if infrastructure != InfrastructureVPC && infrastructure != InfrastructureClassic {
+		return fmt.Errorf("--infrastructure must be %q or %q, got %q", InfrastructureVPC, InfrastructureClassic, infrastructure)
+	}
 
 	resourceGroupID, err := getResourceGroupID(client, accountID, resourceGroupName)
 	if err != nil {
@@ -96,11 +174,19 @@ func createServiceIDs(client ibmcloud.Client, accountID *string,
 	}
 
 	// Process directory
-	credReqs, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview)
+	credReqs, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview, false)
 	if err != nil {
 		return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
 	}
 
+	if len(credReqs) == 0 && !allowEmpty {
+		scanned, err := ioutil.ReadDir(credReqDir)
+		if err != nil {
+			return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
+		}
+		return fmt.Errorf("no CredentialsRequests found in %q (%d file(s) scanned); pass --allow-empty if this is expected", credReqDir, len(scanned))
+	}
+
 	var serviceIDs []*ServiceID
 
 	undo := func() {
@@ -110,19 +196,31 @@ func createServiceIDs(client ibmcloud.Client, accountID *string,
 	}
 
 	for _, cr := range credReqs {
-		serviceID := NewServiceID(client, name, *accountID, resourceGroupID, cr)
+		crResourceGroupID := resourceGroupID
+		if override := cr.Annotations[credreqv1.AnnotationIBMResourceGroup]; override != "" {
+			var err error
+			crResourceGroupID, err = getResourceGroupID(client, accountID, override)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to getResourceGroupID for %s annotation on CredentialsRequest %s/%s",
+					credreqv1.AnnotationIBMResourceGroup, cr.Namespace, cr.Name)
+			}
+		}
+
+		serviceID := NewServiceID(client, name, *accountID, crResourceGroupID, region, cr, infrastructure, stampMetadata)
 		serviceIDs = append(serviceIDs, serviceID)
 	}
 
 	for _, serviceID := range serviceIDs {
-		if err := serviceID.Validate(); err != nil {
+		if err := serviceID.Validate(resume); err != nil {
 			return errors.Wrap(err, "Failed to validate the serviceID")
 		}
 	}
 
 	for _, serviceID := range serviceIDs {
-		if err := serviceID.Do(); err != nil {
-			undo()
+		if err := serviceID.DoWithRetry(retryAttempts, retryBackoff); err != nil {
+			if !resume {
+				undo()
+			}
 			return errors.Wrap(err, "Failed to process the serviceID")
 		}
 	}
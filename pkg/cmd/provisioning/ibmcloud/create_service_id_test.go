@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -19,6 +21,12 @@ import (
 	pmv1 "github.com/IBM/platform-services-go-sdk/iampolicymanagementv1"
 	"github.com/IBM/platform-services-go-sdk/resourcemanagerv2"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	"github.com/openshift/cloud-credential-operator/pkg/cmd/provisioning"
 	mockibmcloud "github.com/openshift/cloud-credential-operator/pkg/ibmcloud/mock"
 )
@@ -91,6 +99,7 @@ func TestCreateSecretsCmd(t *testing.T) {
 
 			if test.expectError {
 				require.Error(t, err, "Expected error returned")
+				assert.Equal(t, provisioning.ExitAuthFailure, provisioning.ExitCodeFor(err), "missing API key should be reported as an auth failure to automation")
 			} else {
 				require.NoError(t, err, "Unexpected error creating secrets")
 				test.verify(t, targetDir)
@@ -104,12 +113,17 @@ func TestCreateSharedSecrets(t *testing.T) {
 		name               string
 		mockIBMCloudClient func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient
 		resourceGroupName  string
+		region             string
+		allowEmpty         bool
+		infrastructure     string
+		resume             bool
 		setup              func(*testing.T) string
 		verify             func(*testing.T, string, string)
 		wantErr            bool
 	}{
 		{
-			name: "createServiceIDs No CredReqs",
+			name:       "createServiceIDs No CredReqs with --allow-empty",
+			allowEmpty: true,
 			mockIBMCloudClient: func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient {
 				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
 				mockCreatePolicy(mockIBMCloudClient, 0, false)
@@ -126,6 +140,24 @@ func TestCreateSharedSecrets(t *testing.T) {
 				assert.Zero(t, countNonDirectoryFiles(files), "Should be no files in manifestsDir when no CredReqs to process")
 			},
 		},
+		{
+			name: "createServiceIDs No CredReqs without --allow-empty errors",
+			mockIBMCloudClient: func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient {
+				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
+				return mockIBMCloudClient
+			},
+			setup: func(t *testing.T) string {
+				tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+				require.NoError(t, err, "Failed to create temp directory")
+				return tempDirName
+			},
+			verify: func(t *testing.T, targetDir string, manifestsDir string) {
+				files, err := ioutil.ReadDir(manifestsDir)
+				require.NoError(t, err, "unexpected error listing files in manifestsDir")
+				assert.Zero(t, countNonDirectoryFiles(files), "Should be no files in manifestsDir when no CredReqs to process")
+			},
+			wantErr: true,
+		},
 		{
 			name: "Create for one CredReq",
 			mockIBMCloudClient: func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient {
@@ -134,6 +166,7 @@ func TestCreateSharedSecrets(t *testing.T) {
 				mockCreateServiceID(mockIBMCloudClient, 1, false)
 				mockDeleteServiceID(mockIBMCloudClient, 0, false)
 				mockCreateAPIKey(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
 				mockCreatePolicy(mockIBMCloudClient, 1, false)
 				return mockIBMCloudClient
 			},
@@ -152,13 +185,40 @@ func TestCreateSharedSecrets(t *testing.T) {
 			},
 		},
 		{
-			name: "CredReq with IBMCloudPowerVSProvider",
+			name:   "--resume against a Service ID with already-created policies skips CreatePolicy",
+			resume: true,
+			mockIBMCloudClient: func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient {
+				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
+				mockListServiceID(mockIBMCloudClient, "", 1, false)
+				mockDeleteServiceID(mockIBMCloudClient, 0, false)
+				mockCreateAPIKey(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 1, 1)
+				return mockIBMCloudClient
+			},
+			setup: func(t *testing.T) string {
+				tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+				require.NoError(t, err, "Failed to create temp directory")
+
+				testCredentialsRequest(t, "firstcredreq", "namespace1", "secretName1", tempDirName)
+
+				return tempDirName
+			},
+			verify: func(t *testing.T, targetDir string, manifestsDir string) {
+				files, err := ioutil.ReadDir(manifestsDir)
+				require.NoError(t, err, "unexpected error listing files in manifestsDir")
+				assert.Equal(t, 1, countNonDirectoryFiles(files), "Should be exactly 1 secret in manifestsDir for one CredReq")
+			},
+		},
+		{
+			name:           "CredReq with IBMCloudPowerVSProvider",
+			infrastructure: InfrastructureClassic,
 			mockIBMCloudClient: func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient {
 				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
 				mockListServiceID(mockIBMCloudClient, "", 0, false)
 				mockCreateServiceID(mockIBMCloudClient, 1, false)
 				mockDeleteServiceID(mockIBMCloudClient, 0, false)
 				mockCreateAPIKey(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
 				mockCreatePolicy(mockIBMCloudClient, 1, false)
 				return mockIBMCloudClient
 			},
@@ -186,6 +246,7 @@ func TestCreateSharedSecrets(t *testing.T) {
 				mockCreateServiceID(mockIBMCloudClient, 1, false)
 				mockDeleteServiceID(mockIBMCloudClient, 0, false)
 				mockCreateAPIKey(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
 				mockCreatePolicy(mockIBMCloudClient, 1, false)
 				return mockIBMCloudClient
 			},
@@ -204,6 +265,40 @@ func TestCreateSharedSecrets(t *testing.T) {
 				assert.Equal(t, 1, countNonDirectoryFiles(files), "Should be exactly 1 secret in manifestsDir for one CredReq")
 			},
 		},
+		{
+			name:              "CredReq with region and resource group",
+			resourceGroupName: "resource-group-exist",
+			region:            "us-south",
+			mockIBMCloudClient: func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient {
+				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
+				mockListResourceGroups(mockIBMCloudClient, true, false)
+				mockListServiceID(mockIBMCloudClient, "", 0, false)
+				mockCreateServiceID(mockIBMCloudClient, 1, false)
+				mockDeleteServiceID(mockIBMCloudClient, 0, false)
+				mockCreateAPIKey(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
+				mockCreatePolicy(mockIBMCloudClient, 1, false)
+				return mockIBMCloudClient
+			},
+			setup: func(t *testing.T) string {
+				tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+				require.NoError(t, err, "Failed to create temp directory")
+
+				testCredentialsRequest(t, "firstcredreq", "namespace1", "secretName1", tempDirName)
+
+				return tempDirName
+			},
+			verify: func(t *testing.T, targetDir string, manifestsDir string) {
+				files, err := ioutil.ReadDir(manifestsDir)
+				require.NoError(t, err, "unexpected error listing files in manifestsDir")
+				require.Equal(t, 1, countNonDirectoryFiles(files), "Should be exactly 1 secret in manifestsDir for one CredReq")
+
+				secretContent, err := ioutil.ReadFile(filepath.Join(manifestsDir, files[0].Name()))
+				require.NoError(t, err, "unexpected error reading generated Secret")
+				assert.Contains(t, string(secretContent), "ibmcloud_region: us-south")
+				assert.Contains(t, string(secretContent), "ibmcloud_resource_group:")
+			},
+		},
 		{
 			name:              "CredReq with invalid ResourceGroupName",
 			resourceGroupName: "resource-group-doesnotexist",
@@ -228,6 +323,35 @@ func TestCreateSharedSecrets(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:              "CredReq resource group annotation overrides --resource-group-name",
+			resourceGroupName: "resource-group-exist",
+			mockIBMCloudClient: func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient {
+				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
+				mockListResourceGroups(mockIBMCloudClient, true, false)
+				mockListResourceGroups(mockIBMCloudClient, true, false)
+				mockListServiceID(mockIBMCloudClient, "", 0, false)
+				mockCreateServiceID(mockIBMCloudClient, 1, false)
+				mockDeleteServiceID(mockIBMCloudClient, 0, false)
+				mockCreateAPIKey(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
+				mockCreatePolicy(mockIBMCloudClient, 1, false)
+				return mockIBMCloudClient
+			},
+			setup: func(t *testing.T) string {
+				tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+				require.NoError(t, err, "Failed to create temp directory")
+
+				testCredentialsRequestWithResourceGroupAnnotation(t, "firstcredreq", "namespace1", "secretName1", "resource-group-other", tempDirName)
+
+				return tempDirName
+			},
+			verify: func(t *testing.T, targetDir string, manifestsDir string) {
+				files, err := ioutil.ReadDir(manifestsDir)
+				require.NoError(t, err, "unexpected error listing files in manifestsDir")
+				assert.Equal(t, 1, countNonDirectoryFiles(files), "Should be exactly 1 secret in manifestsDir for one CredReq")
+			},
+		},
 		{
 			name:              "failed to ListResourceGroups",
 			resourceGroupName: "resource-group",
@@ -309,6 +433,7 @@ func TestCreateSharedSecrets(t *testing.T) {
 				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
 				mockListServiceID(mockIBMCloudClient, "", 0, false)
 				mockCreateServiceID(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
 				mockCreatePolicy(mockIBMCloudClient, 1, true)
 				mockDeleteServiceID(mockIBMCloudClient, 1, false)
 				return mockIBMCloudClient
@@ -330,6 +455,7 @@ func TestCreateSharedSecrets(t *testing.T) {
 				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
 				mockListServiceID(mockIBMCloudClient, "", 0, false)
 				mockCreateServiceID(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
 				mockCreatePolicy(mockIBMCloudClient, 1, false)
 				mockCreateAPIKey(mockIBMCloudClient, 1, true)
 				mockDeleteServiceID(mockIBMCloudClient, 1, false)
@@ -352,6 +478,7 @@ func TestCreateSharedSecrets(t *testing.T) {
 				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
 				mockListServiceID(mockIBMCloudClient, "", 0, false)
 				mockCreateServiceID(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
 				mockCreatePolicy(mockIBMCloudClient, 1, false)
 				mockCreateAPIKey(mockIBMCloudClient, 1, true)
 				mockDeleteServiceID(mockIBMCloudClient, 1, true)
@@ -424,7 +551,11 @@ func TestCreateSharedSecrets(t *testing.T) {
 			require.NoError(t, err, "unexpected error creating manifests dir for test")
 			defer os.RemoveAll(manifestsDir)
 
-			if err := createServiceIDs(mockIBMCloudClient, core.StringPtr("1234"), "name", tt.resourceGroupName, credReqDir, targetDir, false); (err != nil) != tt.wantErr {
+			infrastructure := tt.infrastructure
+			if infrastructure == "" {
+				infrastructure = InfrastructureVPC
+			}
+			if err := createServiceIDs(mockIBMCloudClient, core.StringPtr("1234"), "name", tt.resourceGroupName, credReqDir, targetDir, false, tt.resume, 0, tt.region, tt.allowEmpty, infrastructure, false); (err != nil) != tt.wantErr {
 				t.Errorf("createServiceIDs() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			tt.verify(t, targetDir, manifestsDir)
@@ -449,6 +580,7 @@ func TestCreateSharedSecretsInvalidTargetDir(t *testing.T) {
 				mockCreateServiceID(mockIBMCloudClient, 1, false)
 				mockDeleteServiceID(mockIBMCloudClient, 0, false)
 				mockCreateAPIKey(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
 				mockCreatePolicy(mockIBMCloudClient, 1, false)
 				mockDeleteServiceID(mockIBMCloudClient, 1, true)
 				return mockIBMCloudClient
@@ -477,7 +609,7 @@ func TestCreateSharedSecretsInvalidTargetDir(t *testing.T) {
 
 			targetDir := "doesnotexist"
 
-			if err := createServiceIDs(mockIBMCloudClient, core.StringPtr("1234"), "name1", tt.resourceGroupName, credReqDir, targetDir, false); (err != nil) != tt.wantErr {
+			if err := createServiceIDs(mockIBMCloudClient, core.StringPtr("1234"), "name1", tt.resourceGroupName, credReqDir, targetDir, false, false, 0, "", false, InfrastructureVPC, false); (err != nil) != tt.wantErr {
 				t.Errorf("createServiceIDs() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -505,6 +637,12 @@ func testCredentialsRequestPowerVS(t *testing.T, crName, targetSecretNamespace,
 	writeToTempFile(t, targetDir, getCredentialsRequest(crName, "IBMCloudPowerVSProviderSpec", targetSecretNamespace, targetSecretName))
 }
 
+func testCredentialsRequestWithResourceGroupAnnotation(t *testing.T, crName, targetSecretNamespace, targetSecretName, resourceGroupName, targetDir string) {
+	credReq := getCredentialsRequest(crName, "IBMCloudProviderSpec", targetSecretNamespace, targetSecretName)
+	credReq = strings.Replace(credReq, "metadata:\n  name:", fmt.Sprintf("metadata:\n  annotations:\n    %s: %s\n  name:", credreqv1.AnnotationIBMResourceGroup, resourceGroupName), 1)
+	writeToTempFile(t, targetDir, credReq)
+}
+
 func getCredentialsRequest(crName, kind, targetSecretNamespace, targetSecretName string) string {
 	credReqTemplate := `---
 apiVersion: cloudcredential.openshift.io/v1
@@ -578,6 +716,260 @@ func Test_getEnv(t *testing.T) {
 	}
 }
 
+func Test_getAPIKeyFromSecretWithClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		ref         string
+		secret      *corev1.Secret
+		want        string
+		expectError bool
+	}{
+		{
+			name: "reads the requested key",
+			ref:  "openshift-config/ibm-creds/ibmcloud_api_key",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "ibm-creds", Namespace: "openshift-config"},
+				Data:       map[string][]byte{"ibmcloud_api_key": []byte("secret-api-key")},
+			},
+			want: "secret-api-key",
+		},
+		{
+			name:        "malformed ref errors",
+			ref:         "openshift-config/ibm-creds",
+			expectError: true,
+		},
+		{
+			name: "missing key errors",
+			ref:  "openshift-config/ibm-creds/ibmcloud_api_key",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "ibm-creds", Namespace: "openshift-config"},
+				Data:       map[string][]byte{"other-key": []byte("unused")},
+			},
+			expectError: true,
+		},
+		{
+			name:        "missing secret errors",
+			ref:         "openshift-config/ibm-creds/ibmcloud_api_key",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []runtime.Object
+			if tt.secret != nil {
+				objs = append(objs, tt.secret)
+			}
+			kubeClient := fake.NewSimpleClientset(objs...)
+
+			got, err := getAPIKeyFromSecretWithClient(kubeClient, tt.ref)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_resolveAPIKey(t *testing.T) {
+	for _, v := range APIKeyEnvVars {
+		os.Unsetenv(v)
+	}
+
+	os.Setenv(APIKeyEnvVars[0], "env-api-key")
+	defer os.Unsetenv(APIKeyEnvVars[0])
+
+	got, err := resolveAPIKey("", "openshift-config/ibm-creds/ibmcloud_api_key")
+	require.NoError(t, err, "env var should take precedence without needing a cluster client")
+	assert.Equal(t, "env-api-key", got)
+
+	os.Unsetenv(APIKeyEnvVars[0])
+	_, err = resolveAPIKey("", "")
+	assert.Error(t, err, "no source configured should error")
+}
+
+func Test_validateSecretKeyMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []credreqv1.SecretKeyMapping
+		wantErr  bool
+	}{
+		{
+			name:     "no mappings",
+			mappings: nil,
+		},
+		{
+			name: "valid override",
+			mappings: []credreqv1.SecretKeyMapping{
+				{CloudField: "apiKey", SecretKey: "my-api-key"},
+			},
+		},
+		{
+			name: "unsupported cloudField",
+			mappings: []credreqv1.SecretKeyMapping{
+				{CloudField: "notARealField", SecretKey: "my-api-key"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty secretKey",
+			mappings: []credreqv1.SecretKeyMapping{
+				{CloudField: "apiKey", SecretKey: ""},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate cloudField",
+			mappings: []credreqv1.SecretKeyMapping{
+				{CloudField: "apiKey", SecretKey: "key-one"},
+				{CloudField: "apiKey", SecretKey: "key-two"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate secretKey",
+			mappings: []credreqv1.SecretKeyMapping{
+				{CloudField: "apiKey", SecretKey: "same-key"},
+				{CloudField: "region", SecretKey: "same-key"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			overrides, err := validateSecretKeyMapping(test.mappings)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, m := range test.mappings {
+				assert.Equal(t, m.SecretKey, overrides[m.CloudField])
+			}
+		})
+	}
+}
+
+func TestBuildSecretWithSecretKeyOverride(t *testing.T) {
+	apiKey := "fake-api-key"
+	s := &ServiceID{
+		apiKey: &apiKey,
+		cr: &credreqv1.CredentialsRequest{
+			Spec: credreqv1.CredentialsRequestSpec{
+				SecretRef: corev1.ObjectReference{Namespace: "testns", Name: "testsecret"},
+			},
+		},
+		secretKeyOverrides: map[string]string{"apiKey": "custom-api-key"},
+	}
+
+	secret, err := s.BuildSecret()
+	require.NoError(t, err)
+	assert.Equal(t, apiKey, secret.StringData["custom-api-key"])
+	assert.NotContains(t, secret.StringData, "ibmcloud_api_key")
+	assert.Contains(t, secret.StringData, "ibm-credentials.env")
+}
+
+func TestBuildSecretWithInfrastructure(t *testing.T) {
+	apiKey := "fake-api-key"
+	s := &ServiceID{
+		apiKey: &apiKey,
+		cr: &credreqv1.CredentialsRequest{
+			Spec: credreqv1.CredentialsRequestSpec{
+				SecretRef: corev1.ObjectReference{Namespace: "testns", Name: "testsecret"},
+			},
+		},
+		infrastructure: InfrastructureClassic,
+	}
+
+	secret, err := s.BuildSecret()
+	require.NoError(t, err)
+	assert.Equal(t, InfrastructureClassic, secret.StringData["ibmcloud_infrastructure"])
+}
+
+func TestBuildSecretWithStampMetadata(t *testing.T) {
+	apiKey := "fake-api-key"
+	apiKeyID := "fake-api-key-id"
+	cr := &credreqv1.CredentialsRequest{
+		Spec: credreqv1.CredentialsRequestSpec{
+			SecretRef: corev1.ObjectReference{Namespace: "testns", Name: "testsecret"},
+		},
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		s := &ServiceID{apiKey: &apiKey, apiKeyID: &apiKeyID, cr: cr}
+		secret, err := s.BuildSecret()
+		require.NoError(t, err)
+		assert.Empty(t, secret.Annotations)
+	})
+
+	t.Run("stamps creation time and cloud key ID when enabled", func(t *testing.T) {
+		s := &ServiceID{apiKey: &apiKey, apiKeyID: &apiKeyID, cr: cr, stampMetadata: true}
+		secret, err := s.BuildSecret()
+		require.NoError(t, err)
+		assert.Equal(t, apiKeyID, secret.Annotations[credreqv1.AnnotationCloudKeyID])
+		_, err = time.Parse(time.RFC3339, secret.Annotations[credreqv1.AnnotationCredentialsCreated])
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidateInfrastructure(t *testing.T) {
+	tests := []struct {
+		name           string
+		infrastructure string
+		crKind         string
+		wantErr        bool
+	}{
+		{
+			name:           "no infrastructure set skips validation",
+			infrastructure: "",
+			crKind:         "AnyKind",
+		},
+		{
+			name:           "vpc with IBMCloudProviderSpec",
+			infrastructure: InfrastructureVPC,
+			crKind:         "IBMCloudProviderSpec",
+		},
+		{
+			name:           "vpc with IBMCloudPowerVSProviderSpec fails",
+			infrastructure: InfrastructureVPC,
+			crKind:         "IBMCloudPowerVSProviderSpec",
+			wantErr:        true,
+		},
+		{
+			name:           "classic with IBMCloudPowerVSProviderSpec",
+			infrastructure: InfrastructureClassic,
+			crKind:         "IBMCloudPowerVSProviderSpec",
+		},
+		{
+			name:           "classic with IBMCloudProviderSpec fails",
+			infrastructure: InfrastructureClassic,
+			crKind:         "IBMCloudProviderSpec",
+			wantErr:        true,
+		},
+		{
+			name:           "unsupported infrastructure value",
+			infrastructure: "bare-metal",
+			crKind:         "IBMCloudProviderSpec",
+			wantErr:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &ServiceID{infrastructure: test.infrastructure}
+			err := s.validateInfrastructure(test.crKind)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func getMockedAPIKey() *iamidentityv1.APIKey {
 	return &iamidentityv1.APIKey{
 		ID:        &apiKeyID,
@@ -607,6 +999,14 @@ func mockCreatePolicy(client *mockibmcloud.MockClient, times int, fail bool) {
 		}, nil, err).Times(times)
 }
 
+// mockListPolicies expects the policiesAlreadyExist existing-policy-count check that now precedes
+// CreatePolicy, returning existingCount policies so callers simulating a fresh Service ID can
+// pass 0 to keep the subsequent CreatePolicy calls unskipped.
+func mockListPolicies(client *mockibmcloud.MockClient, existingCount, times int) {
+	client.EXPECT().ListPolicies(gomock.Any()).Return(
+		&pmv1.PolicyList{Policies: make([]pmv1.Policy, existingCount)}, nil, nil).Times(times)
+}
+
 func mockCreateAPIKey(client *mockibmcloud.MockClient, times int, fail bool) {
 	var err error
 	if fail {
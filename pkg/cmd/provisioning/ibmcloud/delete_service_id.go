@@ -20,7 +20,7 @@ func NewDeleteServiceIDCmd() *cobra.Command {
 
 	deleteServiceIDCmd.PersistentFlags().StringVar(&Options.Name, "name", "", "User-defined name for all created IBM Cloud resources (can be separate from the cluster's infra-id)")
 	deleteServiceIDCmd.MarkPersistentFlagRequired("name")
-	deleteServiceIDCmd.PersistentFlags().StringVar(&Options.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to delete IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=ibmcloud' against an OpenShift release image)")
+	deleteServiceIDCmd.PersistentFlags().StringVar(&Options.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to delete IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=ibmcloud' against an OpenShift release image). Accepts a comma-separated list of directories to merge")
 	deleteServiceIDCmd.MarkPersistentFlagRequired("credentials-requests-dir")
 	deleteServiceIDCmd.PersistentFlags().BoolVar(&Options.Force, "force", false, "delete all the service account forcefully(will delete all the entries with the name)")
 
@@ -60,7 +60,7 @@ func deleteServiceIDCmd(cmd *cobra.Command, args []string) error {
 func deleteServiceIDs(client ibmcloud.Client, accountID, name, credReqDir string, force bool) error {
 	// Process directory
 	// always tech-preview==true because we should do a full cleanup to be on the safe side
-	credReqs, err := provisioning.GetListOfCredentialsRequests(credReqDir, true)
+	credReqs, err := provisioning.GetListOfCredentialsRequests(credReqDir, true, false)
 	if err != nil {
 		return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
 	}
@@ -68,7 +68,7 @@ func deleteServiceIDs(client ibmcloud.Client, accountID, name, credReqDir string
 	var serviceIDs []*ServiceID
 
 	for _, cr := range credReqs {
-		serviceID := NewServiceID(client, name, accountID, "", cr)
+		serviceID := NewServiceID(client, name, accountID, "", "", cr, "", false)
 		serviceIDs = append(serviceIDs, serviceID)
 	}
 
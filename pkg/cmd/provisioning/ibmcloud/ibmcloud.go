@@ -9,10 +9,18 @@ type options struct {
 	Name              string
 	CredRequestDir    string
 	ResourceGroupName string
+	Region            string
 	Force             bool
+	AllowEmpty        bool
+	APIKeyFromSecret  string
 	KubeConfigFile    string
 	Create            bool
 	EnableTechPreview bool
+	Resume            bool
+	RetryAttempts     int
+	Infrastructure    string
+	StampMetadata     bool
+	OlderThan         string
 }
 
 // NewIBMCloudCmd implements the "ibmcloud" subcommand for the credentials provisioning
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -28,18 +30,29 @@ func NewRefreshKeysCmd() *cobra.Command {
 
 	refreshKeysCmd.PersistentFlags().StringVar(&Options.Name, "name", "", "User-defined name for all created IBM Cloud resources (can be separate from the cluster's infra-id)")
 	refreshKeysCmd.MarkPersistentFlagRequired("name")
-	refreshKeysCmd.PersistentFlags().StringVar(&Options.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to delete IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=ibmcloud' against an OpenShift release image)")
+	refreshKeysCmd.PersistentFlags().StringVar(&Options.CredRequestDir, "credentials-requests-dir", "", "Directory containing files of CredentialsRequests to delete IAM Roles for (can be created by running 'oc adm release extract --credentials-requests --cloud=ibmcloud' against an OpenShift release image). Accepts a comma-separated list of directories to merge")
 	refreshKeysCmd.MarkPersistentFlagRequired("credentials-requests-dir")
 	refreshKeysCmd.PersistentFlags().StringVar(&Options.KubeConfigFile, "kubeconfig", "", "absolute path to the kubeconfig file")
 	refreshKeysCmd.MarkPersistentFlagRequired("kubeconfig")
 	refreshKeysCmd.PersistentFlags().StringVar(&Options.ResourceGroupName, "resource-group-name", "", "Name of the resource group used for scoping the access policies")
 	refreshKeysCmd.PersistentFlags().BoolVar(&Options.Create, "create", false, "Create the ServiceID if does not exists")
 	refreshKeysCmd.PersistentFlags().BoolVar(&Options.EnableTechPreview, "enable-tech-preview", false, "Opt into processing CredentialsRequests marked as tech-preview")
+	refreshKeysCmd.PersistentFlags().BoolVar(&Options.StampMetadata, "stamp-metadata", false, "Annotate the generated Secret with when its API key was created and the key's IBM Cloud ID, for rotation governance tooling to alert on stale credentials and correlate a Secret with its cloud-side key")
+	refreshKeysCmd.PersistentFlags().StringVar(&Options.OlderThan, "older-than", "", "Only refresh credentials whose --stamp-metadata-recorded creation time is older than this duration (e.g. 30d, 72h), skipping more recently-created ones. Requires --stamp-metadata to have been set on the run that minted the credential; credentials with no recorded creation time are always refreshed. Lets a staged rotation roll through a fleet gradually instead of all at once")
 
 	return refreshKeysCmd
 }
 
 func refreshKeysCmd(cmd *cobra.Command, args []string) error {
+	var olderThan time.Duration
+	if Options.OlderThan != "" {
+		var err error
+		olderThan, err = provisioning.ParseRotationAge(Options.OlderThan)
+		if err != nil {
+			return provisioning.NewValidationError(errors.Wrap(err, "invalid --older-than"))
+		}
+	}
+
 	apiKey := getEnv(APIKeyEnvVars)
 	if apiKey == "" {
 		return fmt.Errorf("%s environment variable not set", APIKeyEnvVars)
@@ -65,28 +78,29 @@ func refreshKeysCmd(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return errors.Wrap(err, "Failed to create the kubernetes clientset")
 	}
-	err = refreshKeys(ibmclient, cs, apiKeyDetails.AccountID, Options.Name, Options.ResourceGroupName, Options.CredRequestDir, Options.Create, Options.EnableTechPreview)
+
+	err = refreshKeys(ibmclient, cs, apiKeyDetails.AccountID, Options.Name, Options.ResourceGroupName, Options.CredRequestDir, Options.Create, Options.EnableTechPreview, Options.StampMetadata, olderThan)
 	if err != nil {
 		return errors.Wrap(err, "Failed to refresh keys")
 	}
 	return nil
 }
 
-func refreshKeys(ibmcloudClient ibmcloud.Client, kubeClient kubernetes.Interface, accountID *string, name, resourceGroupName, credReqDir string, create, enableTechPreview bool) error {
+func refreshKeys(ibmcloudClient ibmcloud.Client, kubeClient kubernetes.Interface, accountID *string, name, resourceGroupName, credReqDir string, create, enableTechPreview, stampMetadata bool, olderThan time.Duration) error {
 	resourceGroupID, err := getResourceGroupID(ibmcloudClient, accountID, resourceGroupName)
 	if err != nil {
 		return errors.Wrap(err, "Failed to getResourceGroupID")
 	}
 
 	// Process directory
-	credReqs, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview)
+	credReqs, err := provisioning.GetListOfCredentialsRequests(credReqDir, enableTechPreview, false)
 	if err != nil {
 		return errors.Wrap(err, "Failed to process files containing CredentialsRequests")
 	}
 
 	var serviceIDs []*ServiceID
 	for _, cr := range credReqs {
-		serviceID := NewServiceID(ibmcloudClient, name, *accountID, resourceGroupID, cr)
+		serviceID := NewServiceID(ibmcloudClient, name, *accountID, resourceGroupID, "", cr, "", stampMetadata)
 		serviceIDs = append(serviceIDs, serviceID)
 	}
 
@@ -103,7 +117,25 @@ func refreshKeys(ibmcloudClient ibmcloud.Client, kubeClient kubernetes.Interface
 		}
 	}
 
+	var skipped []string
 	for _, serviceID := range serviceIDs {
+		secretRef := serviceID.cr.Spec.SecretRef
+		existing, err := kubeClient.CoreV1().Secrets(secretRef.Namespace).Get(context.TODO(), secretRef.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Failed to get existing secret %s/%s for ServiceID: %s", secretRef.Namespace, secretRef.Name, serviceID.name)
+		}
+		if err == nil {
+			tooNew, age, err := provisioning.CredentialTooNewToRotate(existing, olderThan)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to determine age of existing secret %s/%s for ServiceID: %s", secretRef.Namespace, secretRef.Name, serviceID.name)
+			}
+			if tooNew {
+				log.Printf("Skipping ServiceID: %s, its secret was created %s ago which is within --older-than", serviceID.name, age.Round(time.Second))
+				skipped = append(skipped, serviceID.name)
+				continue
+			}
+		}
+
 		log.Printf("Refershing the token for ServiceID: %s", serviceID.name)
 		list, err := serviceID.List()
 		if err != nil {
@@ -143,6 +175,10 @@ func refreshKeys(ibmcloudClient ibmcloud.Client, kubeClient kubernetes.Interface
 		}
 	}
 
+	if len(skipped) > 0 {
+		log.Printf("Skipped %d ServiceID(s) as too new to rotate: %v", len(skipped), skipped)
+	}
+
 	return nil
 }
 
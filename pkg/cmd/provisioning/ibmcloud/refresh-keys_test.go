@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +17,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	mockibmcloud "github.com/openshift/cloud-credential-operator/pkg/ibmcloud/mock"
 )
 
@@ -37,6 +39,7 @@ func Test_refreshKeys(t *testing.T) {
 		setup              func(*testing.T) string
 		resourceGroupName  string
 		create             bool
+		olderThan          time.Duration
 		expectError        string
 	}{
 		{
@@ -94,6 +97,7 @@ func Test_refreshKeys(t *testing.T) {
 				mockListResourceGroups(mockIBMCloudClient, true, false)
 				mockListServiceIDTimes(mockIBMCloudClient, testServiceIDprefix, 0, false, 2)
 				mockCreateServiceID(mockIBMCloudClient, 1, false)
+				mockListPolicies(mockIBMCloudClient, 0, 1)
 				mockCreatePolicy(mockIBMCloudClient, 1, false)
 				mockCreateAPIKey(mockIBMCloudClient, 1, false)
 				mockListAPIKeys(mockIBMCloudClient, 1, false)
@@ -256,6 +260,34 @@ func Test_refreshKeys(t *testing.T) {
 			},
 			expectError: "Failed to remove the stale API Keys",
 		},
+		{
+			name: "skips a credential newer than --older-than",
+			mockIBMCloudClient: func(mockCtrl *gomock.Controller) *mockibmcloud.MockClient {
+				mockIBMCloudClient := mockibmcloud.NewMockClient(mockCtrl)
+				mockListServiceIDTimes(mockIBMCloudClient, testServiceIDprefix, 1, false, 1)
+				return mockIBMCloudClient
+			},
+			fakeKubeClient: func() *fake.Clientset {
+				return fake.NewSimpleClientset(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      targetSecretName,
+						Namespace: targetNamespace,
+						Annotations: map[string]string{
+							credreqv1.AnnotationCredentialsCreated: time.Now().UTC().Format(time.RFC3339),
+						},
+					},
+				})
+			},
+			setup: func(t *testing.T) string {
+				tempDirName, err := ioutil.TempDir(os.TempDir(), testDirPrefix)
+				require.NoError(t, err, "Failed to create temp directory")
+
+				testCredentialsRequest(t, testCRName, targetNamespace, targetSecretName, tempDirName)
+
+				return tempDirName
+			},
+			olderThan: 24 * time.Hour,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -268,7 +300,7 @@ func Test_refreshKeys(t *testing.T) {
 			credReqDir := tt.setup(t)
 			defer os.RemoveAll(credReqDir)
 
-			err := refreshKeys(mockIBMCloudClient, fakeKubeClient, &testAccountID, testName, tt.resourceGroupName, credReqDir, tt.create, false)
+			err := refreshKeys(mockIBMCloudClient, fakeKubeClient, &testAccountID, testName, tt.resourceGroupName, credReqDir, tt.create, false, false, tt.olderThan)
 			if tt.expectError == "" {
 				assert.NoError(t, err)
 			} else {
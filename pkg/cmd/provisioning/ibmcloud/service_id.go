@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -28,14 +29,70 @@ import (
 const (
 	manifestsDirName      = "manifests"
 	secretFileNamePattern = "%s-%s-credentials.yaml"
+
+	// secretCloudFieldAPIKey, secretCloudFieldCredentialsEnv, secretCloudFieldRegion, and
+	// secretCloudFieldResourceGroupID are the CloudField values a CredentialsRequest's
+	// IBMCloudProviderSpec.SecretKeys/IBMCloudPowerVSProviderSpec.SecretKeys may reference to
+	// override the default Secret .data key name BuildSecret writes that field under.
+	secretCloudFieldAPIKey          = "apiKey"
+	secretCloudFieldCredentialsEnv  = "credentialsEnv"
+	secretCloudFieldRegion          = "region"
+	secretCloudFieldResourceGroupID = "resourceGroupID"
+	secretCloudFieldInfrastructure  = "infrastructure"
+
+	// InfrastructureVPC and InfrastructureClassic are the supported values of --infrastructure.
+	// IBMCloudProviderSpec CredentialsRequests target IBM Cloud VPC Gen2 resources, while
+	// IBMCloudPowerVSProviderSpec CredentialsRequests target Power Systems Virtual Server, which is
+	// hosted out of IBM Cloud's classic (non-VPC) datacenters - so the CredentialsRequest's
+	// ProviderSpec kind is what Validate checks the selected infrastructure against.
+	InfrastructureVPC     = "vpc"
+	InfrastructureClassic = "classic"
 )
 
+// defaultSecretKeys are the Secret .data key names BuildSecret uses for each supported cloud
+// field unless overridden via IBMCloudProviderSpec.SecretKeys/IBMCloudPowerVSProviderSpec.SecretKeys.
+var defaultSecretKeys = map[string]string{
+	//TODO(mkumatag): Remove the default for secretCloudFieldAPIKey once all the in-cluster components migrate to use the GetAuthenticatorFromEnvironment method
+	secretCloudFieldAPIKey:          "ibmcloud_api_key",
+	secretCloudFieldCredentialsEnv:  "ibm-credentials.env",
+	secretCloudFieldRegion:          "ibmcloud_region",
+	secretCloudFieldResourceGroupID: "ibmcloud_resource_group",
+	secretCloudFieldInfrastructure:  "ibmcloud_infrastructure",
+}
+
+// validateSecretKeyMapping checks mappings against defaultSecretKeys' known CloudField set and
+// rejects empty or duplicate SecretKey/CloudField entries, returning a CloudField-to-SecretKey
+// override map on success. Called from Validate so a bad schema is caught at read time, before
+// any cloud resources are provisioned.
+func validateSecretKeyMapping(mappings []credreqv1.SecretKeyMapping) (map[string]string, error) {
+	overrides := make(map[string]string, len(mappings))
+	seenSecretKeys := make(map[string]bool, len(mappings))
+	for _, m := range mappings {
+		if _, known := defaultSecretKeys[m.CloudField]; !known {
+			return nil, fmt.Errorf("unsupported secretKeys cloudField %q", m.CloudField)
+		}
+		if m.SecretKey == "" {
+			return nil, fmt.Errorf("secretKeys entry for cloudField %q has an empty secretKey", m.CloudField)
+		}
+		if _, dup := overrides[m.CloudField]; dup {
+			return nil, fmt.Errorf("secretKeys has more than one entry for cloudField %q", m.CloudField)
+		}
+		if seenSecretKeys[m.SecretKey] {
+			return nil, fmt.Errorf("secretKeys maps more than one cloudField to secretKey %q", m.SecretKey)
+		}
+		overrides[m.CloudField] = m.SecretKey
+		seenSecretKeys[m.SecretKey] = true
+	}
+	return overrides, nil
+}
+
 var _ Provision = &ServiceID{}
 
 type Provision interface {
-	Validate() error
+	Validate(resume bool) error
 
 	Do() error
+	DoWithRetry(maxRetries int, backoff time.Duration) error
 	UnDo(string) error
 
 	List() ([]iamidentityv1.ServiceID, error)
@@ -55,8 +112,32 @@ type ServiceID struct {
 	name            string
 	accountID       string
 	resourceGroupID string
+	region          string
 	cr              *credreqv1.CredentialsRequest
 	apiKey          *string
+	apiKeyID        *string
+
+	// infrastructure is the --infrastructure value ("vpc" or "classic") passed to
+	// create-service-id, empty for commands (delete-service-id, refresh-keys) that don't create or
+	// validate against it.
+	infrastructure string
+
+	// stampMetadata is the --stamp-metadata value: when true, BuildSecret annotates the generated
+	// Secret with the API key's creation time and IBM Cloud key ID, for rotation governance
+	// tooling.
+	stampMetadata bool
+
+	// secretKeyOverrides holds the CloudField-to-SecretKey overrides read from the
+	// CredentialsRequest's providerSpec SecretKeys, populated by Validate. Empty unless the
+	// providerSpec declares any.
+	secretKeyOverrides map[string]string
+
+	// policiesCreated and apiKeyCreated record that Do has already completed the access-policy
+	// and API-key creation steps, so a later Do call from DoWithRetry's retry loop (after a
+	// transient failure past that point) doesn't re-attach duplicate IAM access policies or mint
+	// an extra API key.
+	policiesCreated bool
+	apiKeyCreated   bool
 }
 
 func (s *ServiceID) List() ([]iamidentityv1.ServiceID, error) {
@@ -79,7 +160,11 @@ func (s *ServiceID) List() ([]iamidentityv1.ServiceID, error) {
 	return nil, nil
 }
 
-func (s *ServiceID) Validate() error {
+// Validate checks that the ServiceID's CredentialsRequest is of a supported kind and that no
+// Service ID with the same name already exists. When resume is true, a pre-existing Service ID
+// is adopted instead of treated as an error, so that a previous partial run of "create-service-id"
+// can be re-run to pick up where it left off rather than needing a full delete/recreate.
+func (s *ServiceID) Validate(resume bool) error {
 	codec, err := credreqv1.NewCodec()
 	if err != nil {
 		return errors.Wrap(err, "Failed to create credReq codec")
@@ -96,63 +181,198 @@ func (s *ServiceID) Validate() error {
 		return fmt.Errorf("not supported of kind: %s", unknown.Kind)
 	}
 
+	if err := s.validateInfrastructure(unknown.Kind); err != nil {
+		return err
+	}
+
+	secretKeys, err := s.extractSecretKeys()
+	if err != nil {
+		return err
+	}
+	s.secretKeyOverrides, err = validateSecretKeyMapping(secretKeys)
+	if err != nil {
+		return errors.Wrap(err, "invalid secretKeys in providerSpec")
+	}
+
 	list, err := s.List()
 	if err != nil {
 		return err
 	}
 	if len(list) != 0 {
-		return errors.Errorf("exists with the same name: %s, please delete the entries or create with a different name", s.name)
+		if !resume {
+			return errors.Errorf("exists with the same name: %s, please delete the entries or create with a different name", s.name)
+		}
+		log.Printf("resuming: adopting existing Service ID %s for %s", *list[0].ID, s.name)
+		adopted := list[0]
+		s.ServiceID = &adopted
 	}
 	return nil
 }
 
-func (s *ServiceID) Do() error {
-	serviceIDOptions := &iamidentityv1.CreateServiceIDOptions{
-		AccountID: &s.accountID,
-		Name:      &s.name,
+// validateInfrastructure checks that crKind is the ProviderSpec kind expected for
+// s.infrastructure, so that e.g. a VPC-targeted CredentialsRequest can't be processed with
+// --infrastructure=classic and end up with secrets shaped for the wrong consuming operator.
+// A blank s.infrastructure (delete-service-id, refresh-keys) skips the check, since those
+// commands don't emit infrastructure-specific secrets.
+func (s *ServiceID) validateInfrastructure(crKind string) error {
+	if s.infrastructure == "" {
+		return nil
 	}
-	id, _, err := s.Client.CreateServiceID(serviceIDOptions)
-	if err != nil {
-		return err
+
+	switch s.infrastructure {
+	case InfrastructureVPC:
+		if crKind != reflect.TypeOf(credreqv1.IBMCloudProviderSpec{}).Name() {
+			return fmt.Errorf("--infrastructure=%s requires a CredentialsRequest of kind %s, got %s",
+				InfrastructureVPC, reflect.TypeOf(credreqv1.IBMCloudProviderSpec{}).Name(), crKind)
+		}
+	case InfrastructureClassic:
+		if crKind != reflect.TypeOf(credreqv1.IBMCloudPowerVSProviderSpec{}).Name() {
+			return fmt.Errorf("--infrastructure=%s requires a CredentialsRequest of kind %s, got %s",
+				InfrastructureClassic, reflect.TypeOf(credreqv1.IBMCloudPowerVSProviderSpec{}).Name(), crKind)
+		}
+	default:
+		return fmt.Errorf("unsupported --infrastructure value %q, must be %q or %q", s.infrastructure, InfrastructureVPC, InfrastructureClassic)
 	}
-	s.ServiceID = id
-	policies, err := s.extractPolicies()
-	if err != nil {
-		return errors.Wrapf(err, "Failed to extract the policies: %+v", err)
+	return nil
+}
+
+// Do creates the Service ID (unless Validate already adopted one while resuming), its access
+// policies, and its API key. It is called with retries by DoWithRetry so that transient IBM
+// Cloud API failures don't require restarting the whole "create-service-id" run from scratch.
+func (s *ServiceID) Do() error {
+	if s.ServiceID == nil {
+		serviceIDOptions := &iamidentityv1.CreateServiceIDOptions{
+			AccountID: &s.accountID,
+			Name:      &s.name,
+		}
+		id, _, err := s.Client.CreateServiceID(serviceIDOptions)
+		if err != nil {
+			return err
+		}
+		s.ServiceID = id
 	}
-	// Create a new Access Policy for each policy in the CredReq.
-	for _, policy := range policies {
-		err = s.createPolicy(&policy)
+	if !s.policiesCreated {
+		policies, err := s.extractPolicies()
+		if err != nil {
+			return errors.Wrapf(err, "Failed to extract the policies: %+v", err)
+		}
+		alreadyExist, err := s.policiesAlreadyExist(len(policies))
 		if err != nil {
-			return errors.Wrapf(err, "Failed to create access policy with: %+v", policy)
+			return errors.Wrap(err, "Failed to check for already-created access policies")
+		}
+		if alreadyExist {
+			log.Printf("Access policies for ServiceID %s already exist, skipping re-creation", s.name)
+		} else {
+			// Create a new Access Policy for each policy in the CredReq.
+			for _, policy := range policies {
+				if err := s.createPolicy(&policy); err != nil {
+					return errors.Wrapf(err, "Failed to create access policy with: %+v", policy)
+				}
+			}
 		}
+		s.policiesCreated = true
 	}
 
-	if err := s.createAPIKey(); err != nil {
-		return errors.Wrapf(err, "Failed to create an API Key for ServiceID Name: %s, ID: %s", *s.ServiceID.Name, *s.ServiceID.ID)
+	if !s.apiKeyCreated {
+		if err := s.createAPIKey(); err != nil {
+			return errors.Wrapf(err, "Failed to create an API Key for ServiceID Name: %s, ID: %s", *s.ServiceID.Name, *s.ServiceID.ID)
+		}
+		s.apiKeyCreated = true
 	}
 	return nil
 }
 
+// policiesAlreadyExist reports whether this Service ID's IAM subject already has at least
+// wantCount access policies attached, so a resumed run against a Service ID whose policies were
+// created by a prior invocation doesn't attach duplicates. It's a count comparison rather than a
+// content match: CredentialsRequest policies aren't individually identifiable once created, so an
+// exact count is the best signal available for "policy creation already ran".
+func (s *ServiceID) policiesAlreadyExist(wantCount int) (bool, error) {
+	if wantCount == 0 {
+		return true, nil
+	}
+	listOptions := &iampolicymanagementv1.ListPoliciesOptions{
+		AccountID: &s.accountID,
+		IamID:     s.IamID,
+	}
+	existing, _, err := s.Client.ListPolicies(listOptions)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to ListPolicies")
+	}
+	return len(existing.Policies) >= wantCount, nil
+}
+
+// DoWithRetry calls Do, retrying up to maxRetries additional times with a linear backoff if it
+// fails, to ride out transient IBM Cloud API errors without failing the whole provisioning run.
+func (s *ServiceID) DoWithRetry(maxRetries int, backoff time.Duration) error {
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			log.Printf("retrying Service ID creation for %s (attempt %d/%d) after error: %v", s.name, attempt, maxRetries, err)
+			time.Sleep(backoff * time.Duration(attempt))
+		}
+		if err = s.Do(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// secretKeyFor returns the Secret .data key name cloudField should be written under, honoring
+// any providerSpec SecretKeys override from s.secretKeyOverrides and falling back to
+// defaultSecretKeys otherwise.
+func (s *ServiceID) secretKeyFor(cloudField string) string {
+	if key, ok := s.secretKeyOverrides[cloudField]; ok {
+		return key
+	}
+	return defaultSecretKeys[cloudField]
+}
+
 func (s *ServiceID) BuildSecret() (*corev1.Secret, error) {
 	if s.apiKey == nil || s.cr == nil {
 		return nil, errors.New("apiKey or credentialRequest can't be nil")
 	}
+
+	stringData := map[string]string{
+		s.secretKeyFor(secretCloudFieldAPIKey):         *s.apiKey,
+		s.secretKeyFor(secretCloudFieldCredentialsEnv): fmt.Sprintf("IBMCLOUD_AUTHTYPE=iam\nIBMCLOUD_APIKEY=%s", *s.apiKey),
+	}
+
+	// region and resourceGroupID are only populated when the caller asked for them (via --region
+	// and --resource-group-name), so the default Secret shape is unchanged for consumers that
+	// only ever read ibmcloud_api_key.
+	if s.region != "" {
+		stringData[s.secretKeyFor(secretCloudFieldRegion)] = s.region
+	}
+	if s.resourceGroupID != "" {
+		stringData[s.secretKeyFor(secretCloudFieldResourceGroupID)] = s.resourceGroupID
+	}
+	if s.infrastructure != "" {
+		stringData[s.secretKeyFor(secretCloudFieldInfrastructure)] = s.infrastructure
+	}
+
+	var annotations map[string]string
+	if s.stampMetadata {
+		annotations = map[string]string{
+			credreqv1.AnnotationCredentialsCreated: time.Now().UTC().Format(time.RFC3339),
+		}
+		if s.apiKeyID != nil {
+			annotations[credreqv1.AnnotationCloudKeyID] = *s.apiKeyID
+		}
+	}
+
 	return &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: corev1.SchemeGroupVersion.String(),
 			Kind:       "Secret",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      s.cr.Spec.SecretRef.Name,
-			Namespace: s.cr.Spec.SecretRef.Namespace,
+			Name:        s.cr.Spec.SecretRef.Name,
+			Namespace:   s.cr.Spec.SecretRef.Namespace,
+			Annotations: annotations,
 		},
-		StringData: map[string]string{
-			//TODO(mkumatag): Remove the entry for ibmcloud_api_key once all the in-cluster components migrate to use the GetAuthenticatorFromEnvironment method
-			"ibmcloud_api_key":    *s.apiKey,
-			"ibm-credentials.env": fmt.Sprintf("IBMCLOUD_AUTHTYPE=iam\nIBMCLOUD_APIKEY=%s", *s.apiKey),
-		},
-		Type: corev1.SecretTypeOpaque,
+		StringData: stringData,
+		Type:       corev1.SecretTypeOpaque,
 	}, nil
 }
 
@@ -194,6 +414,7 @@ func (s *ServiceID) createAPIKey() error {
 		return errors.Wrap(err, "Failed to create Service ID API key")
 	}
 	s.apiKey = apiKey.Apikey
+	s.apiKeyID = apiKey.ID
 	return nil
 }
 
@@ -300,6 +521,36 @@ func (s *ServiceID) extractPolicies() (policies []credreqv1.AccessPolicy, return
 	return
 }
 
+func (s *ServiceID) extractSecretKeys() (secretKeys []credreqv1.SecretKeyMapping, returnErr error) {
+	codec, returnErr := credreqv1.NewCodec()
+	if returnErr != nil {
+		return nil, errors.Wrap(returnErr, "Failed to create credReq codec")
+	}
+	var unknown runtime.Unknown
+	returnErr = codec.DecodeProviderSpec(s.cr.Spec.ProviderSpec, &unknown)
+	if returnErr != nil {
+		return nil, returnErr
+	}
+
+	switch unknown.Kind {
+	case reflect.TypeOf(credreqv1.IBMCloudProviderSpec{}).Name():
+		ibmcloudProviderSpec := &credreqv1.IBMCloudProviderSpec{}
+		if err := codec.DecodeProviderSpec(s.cr.Spec.ProviderSpec, ibmcloudProviderSpec); err != nil {
+			return nil, errors.Wrap(err, "Failed to decode the provider spec")
+		}
+		secretKeys = ibmcloudProviderSpec.SecretKeys
+	case reflect.TypeOf(credreqv1.IBMCloudPowerVSProviderSpec{}).Name():
+		ibmCloudPowerVSProviderSpec := &credreqv1.IBMCloudPowerVSProviderSpec{}
+		if err := codec.DecodeProviderSpec(s.cr.Spec.ProviderSpec, ibmCloudPowerVSProviderSpec); err != nil {
+			return nil, errors.Wrap(err, "Failed to decode the provider spec")
+		}
+		secretKeys = ibmCloudPowerVSProviderSpec.SecretKeys
+	default:
+		return nil, fmt.Errorf("not supported of kind: %s", unknown.Kind)
+	}
+	return
+}
+
 func (s *ServiceID) UnDo(targetDir string) error {
 	if s.ServiceID == nil {
 		return fmt.Errorf("no ServiceID present for: %s", s.name)
@@ -414,12 +665,15 @@ func getPageToken(next *string) string {
 	return q.Get("pagetoken")
 }
 
-func NewServiceID(client ibmcloud.Client, prefix, accountID, resourceGroupID string, cr *credreqv1.CredentialsRequest) *ServiceID {
+func NewServiceID(client ibmcloud.Client, prefix, accountID, resourceGroupID, region string, cr *credreqv1.CredentialsRequest, infrastructure string, stampMetadata bool) *ServiceID {
 	return &ServiceID{
 		Client:          client,
 		name:            prefix + "-" + cr.Spec.SecretRef.Namespace + "-" + cr.Spec.SecretRef.Name,
 		cr:              cr,
 		accountID:       accountID,
 		resourceGroupID: resourceGroupID,
+		region:          region,
+		infrastructure:  infrastructure,
+		stampMetadata:   stampMetadata,
 	}
 }
@@ -0,0 +1,118 @@
+package provisioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	credreqv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+)
+
+// rbacManifestFileNamePattern names the manifest file ccoctl writes for a CredentialsRequest's
+// RBAC objects when --emit-rbac is set.
+const rbacManifestFileNamePattern = "%s-%s-rbac.yaml"
+
+// WriteRBACManifests writes, for cr, a ServiceAccount per cr.Spec.ServiceAccountNames, a Role
+// granting "get" on cr.Spec.SecretRef's Secret, and a RoleBinding tying the two together, all in
+// cr.Spec.SecretRef.Namespace. This saves a consuming operator from hand-authoring the RBAC
+// needed to read its own generated Secret. If cr has no ServiceAccountNames, only the Role is
+// written, since there is no subject to bind it to.
+func WriteRBACManifests(cr *credreqv1.CredentialsRequest, targetDir string) error {
+	namespace := cr.Spec.SecretRef.Namespace
+	secretName := cr.Spec.SecretRef.Name
+	roleName := fmt.Sprintf("%s-secret-reader", secretName)
+
+	var objects []interface{}
+
+	for _, saName := range cr.Spec.ServiceAccountNames {
+		objects = append(objects, &corev1.ServiceAccount{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ServiceAccount",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      saName,
+				Namespace: namespace,
+			},
+		})
+	}
+
+	objects = append(objects, &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      roleName,
+			Namespace: namespace,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"secrets"},
+				ResourceNames: []string{secretName},
+				Verbs:         []string{"get"},
+			},
+		},
+	})
+
+	if len(cr.Spec.ServiceAccountNames) == 0 {
+		log.Printf("CredentialsRequest %s/%s has no serviceAccountNames, emitting its RBAC Role without a RoleBinding", cr.Namespace, cr.Name)
+	} else {
+		subjects := make([]rbacv1.Subject, 0, len(cr.Spec.ServiceAccountNames))
+		for _, saName := range cr.Spec.ServiceAccountNames {
+			subjects = append(subjects, rbacv1.Subject{
+				Kind:      "ServiceAccount",
+				Name:      saName,
+				Namespace: namespace,
+			})
+		}
+
+		objects = append(objects, &rbacv1.RoleBinding{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "rbac.authorization.k8s.io/v1",
+				Kind:       "RoleBinding",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      roleName,
+				Namespace: namespace,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "Role",
+				Name:     roleName,
+			},
+			Subjects: subjects,
+		})
+	}
+
+	var out []byte
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal RBAC manifest")
+		}
+		if i > 0 {
+			out = append(out, []byte("---\n")...)
+		}
+		out = append(out, data...)
+	}
+
+	manifestsDir := filepath.Join(targetDir, ManifestsDirName)
+	filePath := filepath.Join(manifestsDir, fmt.Sprintf(rbacManifestFileNamePattern, namespace, secretName))
+	if err := ioutil.WriteFile(filePath, out, 0600); err != nil {
+		return errors.Wrap(err, "failed to save RBAC manifest")
+	}
+
+	log.Printf("Saved RBAC configuration to: %s", filePath)
+
+	return nil
+}
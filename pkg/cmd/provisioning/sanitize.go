@@ -0,0 +1,45 @@
+package provisioning
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// nameSanitizationRule describes the naming constraints a cloud provider imposes on a
+// ccoctl-generated resource name.
+type nameSanitizationRule struct {
+	// maxLength is the maximum number of characters the cloud provider allows for this kind of
+	// resource name.
+	maxLength int
+	// invalidChars matches characters that are not allowed in the resource name; matches are
+	// stripped out before truncation.
+	invalidChars *regexp.Regexp
+}
+
+// nameSanitizationRules holds the per-cloud, per-resource-kind naming rules used by
+// SanitizeResourceName. Keys are "<cloud>/<resourceKind>", so that a single cloud can register
+// different rules for resources with different constraints (e.g. GCP service accounts are
+// limited to 30 characters, while GCP workload identity pools allow 32).
+var nameSanitizationRules = map[string]nameSanitizationRule{
+	"aws/iam-role":           {maxLength: 64, invalidChars: regexp.MustCompile(`[^\w+=,.@-]`)},
+	"gcp/service-account":    {maxLength: 30, invalidChars: regexp.MustCompile(`[^a-z0-9-]`)},
+	"azure/app-registration": {maxLength: 92, invalidChars: regexp.MustCompile(`[^\w.-]`)},
+}
+
+// SanitizeResourceName strips characters the given cloud/resourceKind doesn't allow in a
+// resource name and truncates the result to the provider's maximum length. The second return
+// value reports whether the input was altered, so callers can warn the user that the name they
+// asked for was not used verbatim.
+func SanitizeResourceName(cloud, resourceKind, name string) (string, bool, error) {
+	rule, ok := nameSanitizationRules[fmt.Sprintf("%s/%s", cloud, resourceKind)]
+	if !ok {
+		return "", false, fmt.Errorf("no name sanitization rule registered for %s/%s", cloud, resourceKind)
+	}
+
+	sanitized := rule.invalidChars.ReplaceAllString(name, "")
+	if len(sanitized) > rule.maxLength {
+		sanitized = sanitized[:rule.maxLength]
+	}
+
+	return sanitized, sanitized != name, nil
+}
@@ -0,0 +1,65 @@
+package provisioning
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeResourceName(t *testing.T) {
+	tests := []struct {
+		name            string
+		cloud           string
+		resourceKind    string
+		input           string
+		expected        string
+		expectedChanged bool
+		expectError     bool
+	}{
+		{
+			name:         "aws iam role within limits is untouched",
+			cloud:        "aws",
+			resourceKind: "iam-role",
+			input:        "my-cluster-openshift-image-registry-installer-cloud-credentials",
+			expected:     "my-cluster-openshift-image-registry-installer-cloud-credentials",
+		},
+		{
+			name:            "aws iam role longer than 64 chars is truncated",
+			cloud:           "aws",
+			resourceKind:    "iam-role",
+			input:           strings.Repeat("a", 80),
+			expected:        strings.Repeat("a", 64),
+			expectedChanged: true,
+		},
+		{
+			name:            "gcp service account strips disallowed characters",
+			cloud:           "gcp",
+			resourceKind:    "service-account",
+			input:           "My_Cluster.SA",
+			expected:        "yluster",
+			expectedChanged: true,
+		},
+		{
+			name:         "unregistered cloud/resource kind errors",
+			cloud:        "openstack",
+			resourceKind: "iam-role",
+			input:        "anything",
+			expectError:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sanitized, changed, err := SanitizeResourceName(test.cloud, test.resourceKind, test.input)
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sanitized)
+			assert.Equal(t, test.expectedChanged, changed)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package provisioning
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// SealScope mirrors the scopes supported by the Bitnami sealed-secrets controller, which
+// determine what a SealedSecret can be renamed/moved to without invalidating the encryption.
+type SealScope string
+
+const (
+	// SealScopeStrict restricts decryption to the exact namespace/name the Secret was sealed for.
+	SealScopeStrict SealScope = "strict"
+	// SealScopeNamespaceWide restricts decryption to the namespace the Secret was sealed for.
+	SealScopeNamespaceWide SealScope = "namespace-wide"
+	// SealScopeClusterWide allows decryption regardless of namespace/name.
+	SealScopeClusterWide SealScope = "cluster-wide"
+
+	// sealedSecretAnnotationNamespaceWide and sealedSecretAnnotationClusterWide are the
+	// annotations the sealed-secrets controller looks for on .spec.template.metadata to pick the
+	// RSA-OAEP label (namespace-only or empty) it should try when unsealing, instead of assuming
+	// the strict namespace/name label. They must match the scope sealLabel encrypted with, or the
+	// controller can't unseal the value.
+	sealedSecretAnnotationNamespaceWide = "sealedsecrets.bitnami.com/namespace-wide"
+	sealedSecretAnnotationClusterWide   = "sealedsecrets.bitnami.com/cluster-wide"
+)
+
+// sealedSecret is a minimal representation of a bitnami.com/v1alpha1 SealedSecret, sufficient
+// for ccoctl to emit a manifest that the sealed-secrets controller can unseal.
+type sealedSecret struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   sealedSecretMeta `yaml:"metadata"`
+	Spec       sealedSecretSpec `yaml:"spec"`
+}
+
+type sealedSecretMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type sealedSecretSpec struct {
+	EncryptedData map[string]string        `yaml:"encryptedData"`
+	Template      sealedSecretSpecTemplate `yaml:"template"`
+}
+
+type sealedSecretSpecTemplate struct {
+	Metadata  sealedSecretMeta `yaml:"metadata"`
+	Type      string           `yaml:"type"`
+	Immutable bool             `yaml:"immutable,omitempty"`
+}
+
+// LoadSealCertificate reads an RSA public key from a PEM-encoded certificate or public key file,
+// as produced by the sealed-secrets controller's "kubeseal --fetch-cert" command.
+func LoadSealCertificate(certPath string) (*rsa.PublicKey, error) {
+	certPEM, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read seal certificate")
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block from seal certificate")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse seal certificate")
+		}
+		pubKey, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("seal certificate does not contain an RSA public key")
+		}
+		return pubKey, nil
+	default:
+		pubKeyIface, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse seal public key")
+		}
+		pubKey, ok := pubKeyIface.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("seal public key is not an RSA key")
+		}
+		return pubKey, nil
+	}
+}
+
+// sealValue performs the hybrid RSA-OAEP/AES-GCM encryption used by the sealed-secrets
+// controller: a random AES session key encrypts the plaintext, and the session key itself
+// is wrapped with RSA-OAEP using the provided label.
+func sealValue(pubKey *rsa.PublicKey, plaintext, label []byte) (string, error) {
+	sessionKey := make([]byte, 32)
+	if _, err := rand.Read(sessionKey); err != nil {
+		return "", errors.Wrap(err, "failed to generate session key")
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create AES cipher")
+	}
+	aed, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create AES-GCM")
+	}
+
+	encryptedSessionKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pubKey, sessionKey, label)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to wrap session key")
+	}
+
+	out := make([]byte, 2, 2+len(encryptedSessionKey)+len(plaintext)+aed.Overhead())
+	binary.BigEndian.PutUint16(out, uint16(len(encryptedSessionKey)))
+	out = append(out, encryptedSessionKey...)
+
+	nonce := make([]byte, aed.NonceSize())
+	out = aed.Seal(out, nonce, plaintext, nil)
+
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// sealLabel computes the label used to scope the encrypted value per the sealed-secrets
+// scoping rules for the given namespace/name.
+func sealLabel(scope SealScope, namespace, name string) []byte {
+	switch scope {
+	case SealScopeClusterWide:
+		return []byte{}
+	case SealScopeNamespaceWide:
+		return []byte(namespace)
+	default:
+		return []byte(fmt.Sprintf("%s/%s", namespace, name))
+	}
+}
+
+// sealScopeAnnotations returns the .spec.template.metadata.annotations the sealed-secrets
+// controller requires to know which RSA-OAEP label sealLabel used, so it knows which label to
+// retry the unseal with. SealScopeStrict needs no annotation: it's the controller's default
+// assumption (the exact namespace/name label).
+func sealScopeAnnotations(scope SealScope) map[string]string {
+	switch scope {
+	case SealScopeClusterWide:
+		return map[string]string{sealedSecretAnnotationClusterWide: "true"}
+	case SealScopeNamespaceWide:
+		return map[string]string{sealedSecretAnnotationNamespaceWide: "true"}
+	default:
+		return nil
+	}
+}
+
+// WriteSealedSecret encrypts the given Secret data against the provided certificate and
+// writes out a SealedSecret manifest in place of a plaintext core/v1 Secret.
+func WriteSealedSecret(certPath string, scope SealScope, namespace, name, secretType string, data map[string]string, outPath string, immutable bool) error {
+	pubKey, err := LoadSealCertificate(certPath)
+	if err != nil {
+		return err
+	}
+
+	label := sealLabel(scope, namespace, name)
+
+	encryptedData := map[string]string{}
+	for key, value := range data {
+		sealed, err := sealValue(pubKey, []byte(value), label)
+		if err != nil {
+			return errors.Wrapf(err, "failed to seal key %q", key)
+		}
+		encryptedData[key] = sealed
+	}
+
+	manifest := sealedSecret{
+		APIVersion: "bitnami.com/v1alpha1",
+		Kind:       "SealedSecret",
+		Metadata: sealedSecretMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: sealedSecretSpec{
+			EncryptedData: encryptedData,
+			Template: sealedSecretSpecTemplate{
+				Metadata: sealedSecretMeta{
+					Name:        name,
+					Namespace:   namespace,
+					Annotations: sealScopeAnnotations(scope),
+				},
+				Type:      secretType,
+				Immutable: immutable,
+			},
+		},
+	}
+
+	out, err := yaml.Marshal(&manifest)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SealedSecret manifest")
+	}
+
+	if err := ioutil.WriteFile(outPath, out, 0600); err != nil {
+		return errors.Wrap(err, "failed to save SealedSecret manifest")
+	}
+
+	return nil
+}
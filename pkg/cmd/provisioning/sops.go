@@ -0,0 +1,18 @@
+package provisioning
+
+import (
+	"fmt"
+)
+
+// EncryptWithSops is expected to encrypt data (a Secret manifest's serialized bytes) into a valid
+// SOPS document under the rules (age/KMS/PGP recipients) configured at sopsConfigPath, so the
+// result can be committed to a GitOps repository and decrypted at apply time with the ordinary
+// "sops" CLI.
+//
+// None of go.mozilla.org/sops's encryption backends (age, PGP, AWS/GCP/Azure KMS) are vendored in
+// this build, so this always errors rather than silently writing an unencrypted file under a name
+// that implies it was encrypted. Until a real implementation lands, encrypt the generated Secret
+// manifest out-of-band with the "sops" CLI using sopsConfigPath's rules.
+func EncryptWithSops(sopsConfigPath string, data []byte) ([]byte, error) {
+	return nil, fmt.Errorf("encrypting with SOPS is not supported in this build; encrypt the generated Secret manifest out-of-band with the sops CLI and %q", sopsConfigPath)
+}
@@ -0,0 +1,226 @@
+package provisioning
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var zeroTime time.Time
+
+// BundleEntry names a file or directory to include in a bundle written by WriteBundle. Name is the
+// path the item is archived under; Path is where it currently lives on disk, and may point at
+// either a single file or a directory (which is walked recursively, as WriteDeterministicTar does).
+type BundleEntry struct {
+	Name string
+	Path string
+}
+
+// bundleIndex is the manifest index written to index.json alongside the bundled files, so the
+// other side of an air gap can see at a glance what the bundle contains without extracting it.
+type bundleIndex struct {
+	Included []string `json:"included"`
+	Skipped  []string `json:"skipped,omitempty"`
+}
+
+// WriteBundle packages entries into a single gzip-compressed tar archive at bundlePath, alongside
+// a generated index.json listing what was included. Entries whose Path does not exist on disk are
+// silently left out of the archive but recorded under "skipped" in the index, since not every
+// optional artifact (e.g. a report that wasn't requested) is always present. This is meant to
+// compose several already-independently-written outputs (manifests, keys, reports) into one
+// artifact that can be moved across a disconnected environment's air gap as a unit.
+func WriteBundle(entries []BundleEntry, bundlePath string) error {
+	bundleFile, err := os.Create(bundlePath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create bundle file %s", bundlePath)
+	}
+	defer bundleFile.Close()
+
+	gzw := gzip.NewWriter(bundleFile)
+	defer gzw.Close()
+
+	tw := tar.NewWriter(gzw)
+	defer tw.Close()
+
+	index := bundleIndex{}
+
+	sortedEntries := make([]BundleEntry, len(entries))
+	copy(sortedEntries, entries)
+	sort.Slice(sortedEntries, func(i, j int) bool { return sortedEntries[i].Name < sortedEntries[j].Name })
+
+	for _, entry := range sortedEntries {
+		info, err := os.Lstat(entry.Path)
+		if os.IsNotExist(err) {
+			index.Skipped = append(index.Skipped, entry.Name)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to stat %s", entry.Path)
+		}
+
+		if err := addBundlePath(tw, entry.Path, entry.Name, info); err != nil {
+			return err
+		}
+		index.Included = append(index.Included, entry.Name)
+	}
+
+	indexJSON, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal bundle index")
+	}
+	if err := writeBundleFile(tw, "index.json", indexJSON); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addBundlePath archives path (a file or directory) under archiveName, recursing into
+// subdirectories as WriteDeterministicTar does.
+func addBundlePath(tw *tar.Writer, path, archiveName string, info os.FileInfo) error {
+	if !info.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", path)
+		}
+		return writeBundleFile(tw, archiveName, content)
+	}
+
+	var paths []string
+	err := filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == path {
+			return nil
+		}
+		paths = append(paths, p)
+		if fi.IsDir() {
+			// Only direct children are collected here; the recursive call below descends.
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to walk directory %s", path)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		fi, err := os.Lstat(p)
+		if err != nil {
+			return errors.Wrapf(err, "failed to stat %s", p)
+		}
+		relPath, err := filepath.Rel(path, p)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve relative path for %s", p)
+		}
+		childName := filepath.ToSlash(filepath.Join(archiveName, relPath))
+		if err := addBundlePath(tw, p, childName, fi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeBundleFile(tw *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: zeroTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "failed to write bundle header for %s", name)
+	}
+	_, err := tw.Write(content)
+	return errors.Wrapf(err, "failed to write bundle contents for %s", name)
+}
+
+// WriteDeterministicTar walks srcDir and writes its contents into a tar archive at tarPath.
+// Entries are written in sorted path order and have their modification times zeroed out, so
+// archiving the same inputs always produces a byte-identical tar, making the result suitable for
+// hash-based verification when moving generated manifests into a disconnected environment.
+func WriteDeterministicTar(srcDir, tarPath string) error {
+	var paths []string
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == srcDir {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to walk directory %s", srcDir)
+	}
+	sort.Strings(paths)
+
+	tarFile, err := os.Create(tarPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create tar file %s", tarPath)
+	}
+	defer tarFile.Close()
+
+	tw := tar.NewWriter(tarFile)
+	defer tw.Close()
+
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to stat %s", path)
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve relative path for %s", path)
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return errors.Wrapf(err, "failed to build tar header for %s", path)
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		// Zero out all timestamps and ownership so the resulting tar is reproducible
+		// across runs and machines given identical input contents.
+		header.ModTime = zeroTime
+		header.AccessTime = zeroTime
+		header.ChangeTime = zeroTime
+		header.Uid = 0
+		header.Gid = 0
+		header.Uname = ""
+		header.Gname = ""
+
+		if err := tw.WriteHeader(header); err != nil {
+			return errors.Wrapf(err, "failed to write tar header for %s", path)
+		}
+
+		if info.IsDir() {
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open %s", path)
+		}
+		_, err = io.Copy(tw, f)
+		f.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to write %s to tar", path)
+		}
+	}
+
+	return nil
+}
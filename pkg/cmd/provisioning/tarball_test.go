@@ -0,0 +1,106 @@
+package provisioning
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDeterministicTar(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "tarball-test-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(srcDir, "sub"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "b.yaml"), []byte("b"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "sub", "a.yaml"), []byte("a"), 0600))
+
+	outDir, err := ioutil.TempDir("", "tarball-test-out")
+	require.NoError(t, err)
+	defer os.RemoveAll(outDir)
+
+	tarPathOne := filepath.Join(outDir, "one.tar")
+	tarPathTwo := filepath.Join(outDir, "two.tar")
+
+	require.NoError(t, WriteDeterministicTar(srcDir, tarPathOne))
+	require.NoError(t, WriteDeterministicTar(srcDir, tarPathTwo))
+
+	contentsOne, err := ioutil.ReadFile(tarPathOne)
+	require.NoError(t, err)
+	contentsTwo, err := ioutil.ReadFile(tarPathTwo)
+	require.NoError(t, err)
+	assert.Equal(t, contentsOne, contentsTwo, "two tars built from identical inputs should be byte-identical")
+
+	f, err := os.Open(tarPathOne)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var names []string
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{"b.yaml", "sub/", "sub/a.yaml"}, names, "entries should be written in sorted path order")
+}
+
+func TestWriteBundle(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "bundle-test-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	manifestsDir := filepath.Join(srcDir, "manifests")
+	require.NoError(t, os.Mkdir(manifestsDir, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(manifestsDir, "secret.yaml"), []byte("secret"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "keys.json"), []byte("jwks"), 0600))
+
+	bundlePath := filepath.Join(srcDir, "bundle.tgz")
+	err = WriteBundle([]BundleEntry{
+		{Name: "manifests", Path: manifestsDir},
+		{Name: "keys.json", Path: filepath.Join(srcDir, "keys.json")},
+		{Name: "report.json", Path: filepath.Join(srcDir, "report.json")},
+	}, bundlePath)
+	require.NoError(t, err)
+
+	f, err := os.Open(bundlePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gzr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	defer gzr.Close()
+
+	contents := map[string]string{}
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		contents[header.Name] = string(body)
+	}
+
+	assert.Equal(t, "secret", contents["manifests/secret.yaml"])
+	assert.Equal(t, "jwks", contents["keys.json"])
+	require.Contains(t, contents, "index.json")
+
+	var index bundleIndex
+	require.NoError(t, json.Unmarshal([]byte(contents["index.json"]), &index))
+	assert.ElementsMatch(t, []string{"manifests", "keys.json"}, index.Included)
+	assert.ElementsMatch(t, []string{"report.json"}, index.Skipped)
+}
@@ -0,0 +1,69 @@
+// Package trace provides lightweight, optional instrumentation for the ccoctl provisioning
+// commands, so slow runs against cloud APIs (especially large-fleet or parallelized scenarios)
+// can be diagnosed.
+//
+// NOTE: this package does not yet emit real OTLP spans, since go.opentelemetry.io/otel is not
+// vendored into this tree. Span/Tracer are shaped to match the OpenTelemetry API (StartSpan/End)
+// so that swapping in a real OTLP exporter later is a drop-in change; for now, an enabled Tracer
+// logs span start/end and duration through logrus instead of exporting anywhere.
+package trace
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Tracer creates Spans for named units of work. The zero Tracer is disabled and its Spans are
+// no-ops, so instrumented code pays no cost when tracing isn't configured.
+type Tracer struct {
+	// endpoint is the OTLP collector endpoint configured via --otel-endpoint. A non-empty
+	// endpoint enables the Tracer; today it is only used to identify the configured destination
+	// in logged span output, since no OTLP exporter is wired up yet.
+	endpoint string
+}
+
+// NewTracer returns a Tracer configured with endpoint. Spans are logged through logrus, tagged
+// with endpoint; nothing is sent to endpoint. An empty endpoint disables tracing entirely,
+// keeping instrumented call sites a no-op.
+func NewTracer(endpoint string) *Tracer {
+	return &Tracer{endpoint: endpoint}
+}
+
+// Enabled reports whether this Tracer was configured with a non-empty endpoint.
+func (t *Tracer) Enabled() bool {
+	return t != nil && t.endpoint != ""
+}
+
+// Span represents one unit of traced work, started by Tracer.StartSpan.
+type Span struct {
+	tracer *Tracer
+	name   string
+	start  time.Time
+}
+
+// StartSpan begins a Span named name. Call End on the returned Span when the work completes.
+// When the Tracer is disabled, StartSpan and the Span it returns are no-ops.
+func (t *Tracer) StartSpan(name string) *Span {
+	if !t.Enabled() {
+		return &Span{}
+	}
+	log.WithField("span", name).WithField("otel-endpoint", t.endpoint).Debug("trace: span started")
+	return &Span{tracer: t, name: name, start: time.Now()}
+}
+
+// SetError records that the traced operation failed. Call it before End, if at all.
+func (s *Span) SetError(err error) {
+	if s == nil || s.tracer == nil || err == nil {
+		return
+	}
+	log.WithField("span", s.name).WithError(err).Debug("trace: span error")
+}
+
+// End finishes the Span and, when tracing is enabled, logs its duration.
+func (s *Span) End() {
+	if s == nil || s.tracer == nil {
+		return
+	}
+	log.WithField("span", s.name).WithField("duration", time.Since(s.start)).Debug("trace: span ended")
+}
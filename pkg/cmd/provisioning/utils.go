@@ -13,12 +13,24 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
+	"context"
+	"time"
+
 	"github.com/pkg/errors"
 	"gopkg.in/square/go-jose.v2"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1 "github.com/openshift/api/config/v1"
 
@@ -147,52 +159,338 @@ func KeyIDFromPublicKey(publicKey interface{}) (string, error) {
 	return keyID, nil
 }
 
-// GetListOfCredentialsRequests decodes manifests in a given directory and returns a list of CredentialsRequests
-func GetListOfCredentialsRequests(dir string, enableTechPreview bool) ([]*credreqv1.CredentialsRequest, error) {
+// knownCredentialsRequestAPIVersions lists every CredentialsRequest apiVersion the manifest
+// reader will accept. "v1" is the current version; "v1beta1" is accepted for backward
+// compatibility with manifests generated by older CCO releases, since the wire format hasn't
+// changed across that bump. A manifest with an apiVersion outside this list is rejected rather
+// than silently decoded, since that most likely means a typo or a version ccoctl doesn't
+// understand yet.
+var knownCredentialsRequestAPIVersions = sets.NewString(
+	"cloudcredential.openshift.io/v1",
+	"cloudcredential.openshift.io/v1beta1",
+)
+
+// GetListOfCredentialsRequests decodes manifests found in the given directory, or directories, and
+// returns a list of CredentialsRequests. dirs accepts a comma-separated list of directories so that
+// CredentialsRequests assembled from multiple operator payloads into separate directories can be
+// read in one pass instead of requiring a separate merge step beforehand. A CredentialsRequest with
+// the same namespace/name found in more than one directory is deduplicated as long as every copy is
+// identical; if the copies disagree, that is treated as a conflicting duplicate and is an error.
+func GetListOfCredentialsRequests(dirs string, enableTechPreview bool, requireServiceAccountNames bool) ([]*credreqv1.CredentialsRequest, error) {
 	credRequests := []*credreqv1.CredentialsRequest{}
-	files, err := ioutil.ReadDir(dir)
-	if err != nil {
-		return nil, err
-	}
+	seen := map[string]*credreqv1.CredentialsRequest{}
+
+	for _, dir := range strings.Split(dirs, ",") {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
 
-	for _, file := range files {
-		f, err := os.Open(filepath.Join(dir, file.Name()))
+		files, err := ioutil.ReadDir(dir)
 		if err != nil {
-			return nil, errors.Wrap(err, "Failed to open file")
+			return nil, err
 		}
-		defer f.Close()
-		decoder := yaml.NewYAMLOrJSONDecoder(f, 4096)
-		for {
-			cr := &credreqv1.CredentialsRequest{}
-			if err := decoder.Decode(cr); err != nil {
-				if err == io.EOF {
-					break
-				}
-				return nil, errors.Wrap(err, "Failed to decode to CredentialsRequest")
-			}
-			// Ignore CredentialsRequest manifest if it has "release.openshift.io/delete" annotation with value "true"
-			// These manifests are marked for in-cluster deletion and should not be consumed by ccoctl to create credentials
-			// infrastructure.
-			if value, ok := cr.Annotations["release.openshift.io/delete"]; ok && value == "true" {
-				log.Printf("Ignoring CredentialsRequest %s/%s as it is marked for in-cluster deletion", cr.Namespace, cr.Name)
-				continue
-			}
 
-			// Handle CredentialsRequest with the feature-gate annotation
-			if value, ok := cr.Annotations[featureGateAnnotation]; ok {
-				if !enableTechPreview {
-					log.Printf("Ignoring CredentialsRequest %s/%s with tech-preview annotation", cr.Namespace, cr.Name)
+		for _, file := range files {
+			f, err := os.Open(filepath.Join(dir, file.Name()))
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to open file")
+			}
+			defer f.Close()
+			decoder := yaml.NewYAMLOrJSONDecoder(f, 4096)
+			for {
+				cr := &credreqv1.CredentialsRequest{}
+				if err := decoder.Decode(cr); err != nil {
+					if err == io.EOF {
+						break
+					}
+					return nil, errors.Wrap(err, "Failed to decode to CredentialsRequest")
+				}
+				if cr.APIVersion != "" && !knownCredentialsRequestAPIVersions.Has(cr.APIVersion) {
+					return nil, fmt.Errorf("CredentialsRequest %s/%s has unrecognized apiVersion %q", cr.Namespace, cr.Name, cr.APIVersion)
+				}
+				if !includeCredentialsRequest(cr, enableTechPreview) {
 					continue
 				}
-				if value != string(configv1.TechPreviewNoUpgrade) {
-					log.Printf("Ignoring CredentialsRequest %s/%s with tech-preview value %s", cr.Namespace, cr.Name, value)
+
+				if err := validateServiceAccountNames(cr, requireServiceAccountNames); err != nil {
+					return nil, err
+				}
+
+				key := fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)
+				if existing, ok := seen[key]; ok {
+					if !reflect.DeepEqual(existing.Spec, cr.Spec) {
+						return nil, fmt.Errorf("CredentialsRequest %s found in multiple --credentials-requests-dir directories with conflicting specs", key)
+					}
 					continue
-				} // else allow it to be added it to the list of CredReqs to process
+				}
+				seen[key] = cr
+
+				credRequests = append(credRequests, cr)
 			}
+		}
+	}
+
+	if err := detectDuplicateSecretRefs(credRequests); err != nil {
+		return nil, err
+	}
+
+	return credRequests, nil
+}
+
+// GetCredentialsRequestFromFile decodes a single CredentialsRequest from the given manifest file.
+func GetCredentialsRequestFromFile(path string) (*credreqv1.CredentialsRequest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open file")
+	}
+	defer f.Close()
+
+	cr := &credreqv1.CredentialsRequest{}
+	if err := yaml.NewYAMLOrJSONDecoder(f, 4096).Decode(cr); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode to CredentialsRequest")
+	}
+	if cr.APIVersion != "" && !knownCredentialsRequestAPIVersions.Has(cr.APIVersion) {
+		return nil, fmt.Errorf("CredentialsRequest %s/%s has unrecognized apiVersion %q", cr.Namespace, cr.Name, cr.APIVersion)
+	}
+
+	return cr, nil
+}
+
+// detectDuplicateSecretRefs returns an error naming the conflicting CredentialsRequests if two or
+// more of them target the same secretRef namespace+name. ccoctl writes one Secret manifest file per
+// secretRef, so a collision means one CredentialsRequest's Secret silently overwrites another's,
+// usually from a copy-paste mistake when authoring CredentialsRequests.
+func detectDuplicateSecretRefs(credRequests []*credreqv1.CredentialsRequest) error {
+	owners := map[string][]string{}
+	for _, cr := range credRequests {
+		if cr.Spec.SecretRef.Name == "" {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+		owners[key] = append(owners[key], fmt.Sprintf("%s/%s", cr.Namespace, cr.Name))
+	}
+
+	for secretRef, crNames := range owners {
+		if len(crNames) > 1 {
+			return fmt.Errorf("CredentialsRequests %v all target secretRef %s, which would overwrite each other's generated Secret", crNames, secretRef)
+		}
+	}
+
+	return nil
+}
+
+// validateServiceAccountNames checks that cr has a non-empty .spec.serviceAccountNames. STS/WIF
+// modes (requireServiceAccountNames true) scope the generated Role/ServiceAccount trust policy to
+// these names, so a CredentialsRequest without any produces a Secret no workload can consume
+// correctly; that case is an error naming the offending request. Other modes don't depend on the
+// field, so an empty list is only worth a warning.
+func validateServiceAccountNames(cr *credreqv1.CredentialsRequest, requireServiceAccountNames bool) error {
+	if len(cr.Spec.ServiceAccountNames) > 0 {
+		return nil
+	}
+
+	if requireServiceAccountNames {
+		return fmt.Errorf("CredentialsRequest %s/%s has no entries in spec.serviceAccountNames, which is required for this mode", cr.Namespace, cr.Name)
+	}
+
+	log.Printf("CredentialsRequest %s/%s has no entries in spec.serviceAccountNames", cr.Namespace, cr.Name)
+	return nil
+}
+
+// includeCredentialsRequest reports whether cr should be processed by ccoctl: it excludes
+// CredentialsRequests marked for in-cluster deletion, and tech-preview-gated CredentialsRequests
+// unless enableTechPreview is set. It is shared by the directory and in-cluster CredentialsRequest
+// readers so both apply the same filtering rules.
+func includeCredentialsRequest(cr *credreqv1.CredentialsRequest, enableTechPreview bool) bool {
+	// Ignore CredentialsRequest manifest if it has "release.openshift.io/delete" annotation with value "true"
+	// These manifests are marked for in-cluster deletion and should not be consumed by ccoctl to create credentials
+	// infrastructure.
+	if value, ok := cr.Annotations["release.openshift.io/delete"]; ok && value == "true" {
+		log.Printf("Ignoring CredentialsRequest %s/%s as it is marked for in-cluster deletion", cr.Namespace, cr.Name)
+		return false
+	}
+
+	// Handle CredentialsRequest with the feature-gate annotation
+	if value, ok := cr.Annotations[featureGateAnnotation]; ok {
+		if !enableTechPreview {
+			log.Printf("Ignoring CredentialsRequest %s/%s with tech-preview annotation", cr.Namespace, cr.Name)
+			return false
+		}
+		if value != string(configv1.TechPreviewNoUpgrade) {
+			log.Printf("Ignoring CredentialsRequest %s/%s with tech-preview value %s", cr.Namespace, cr.Name, value)
+			return false
+		} // else allow it to be added it to the list of CredReqs to process
+	}
+
+	return true
+}
 
-			credRequests = append(credRequests, cr)
+// clusterClient builds a controller-runtime client scoped to the CredentialsRequest and core
+// Secret types, using kubeconfigPath if set or the client-go default loading rules otherwise.
+func clusterClient(kubeconfigPath string) (client.Client, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfigPath
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to load kubeconfig")
+	}
+
+	scheme := runtime.NewScheme()
+	if err := credreqv1.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "Failed to register CredentialsRequest scheme")
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "Failed to register Secret scheme")
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create Kubernetes client")
+	}
+
+	return c, nil
+}
+
+// GetCredentialsRequestFromCluster fetches a single named CredentialsRequest from a live cluster,
+// along with its target Secret (nil if the Secret does not exist yet). kubeconfigPath may be empty
+// to use the client-go default loading rules.
+func GetCredentialsRequestFromCluster(kubeconfigPath, namespace, name string) (*credreqv1.CredentialsRequest, *corev1.Secret, error) {
+	c, err := clusterClient(kubeconfigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cr := &credreqv1.CredentialsRequest{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, cr); err != nil {
+		return nil, nil, errors.Wrapf(err, "Failed to get CredentialsRequest %s/%s", namespace, name)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: cr.Spec.SecretRef.Namespace, Name: cr.Spec.SecretRef.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return cr, nil, nil
+		}
+		return nil, nil, errors.Wrapf(err, "Failed to get target Secret %s/%s", cr.Spec.SecretRef.Namespace, cr.Spec.SecretRef.Name)
+	}
+
+	return cr, secret, nil
+}
+
+// GetListOfCredentialsRequestsFromCluster lists CredentialsRequests from a live cluster via the
+// Kubernetes API instead of a manifests directory, for day-2 use when the original manifests
+// directory is no longer available. kubeconfigPath may be empty to use the client-go default
+// loading rules (KUBECONFIG env var, then the in-cluster config). namespace and labelSelector may
+// be empty to mean "all namespaces" and "no selector" respectively. The result is filtered the
+// same way as GetListOfCredentialsRequests so it can feed the same processing path.
+func GetListOfCredentialsRequestsFromCluster(kubeconfigPath, namespace, labelSelector string, enableTechPreview bool) ([]*credreqv1.CredentialsRequest, error) {
+	c, err := clusterClient(kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse label selector")
+	}
+
+	list := &credreqv1.CredentialsRequestList{}
+	if err := c.List(context.TODO(), list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, errors.Wrap(err, "Failed to list CredentialsRequests from cluster")
+	}
+
+	credRequests := []*credreqv1.CredentialsRequest{}
+	for i := range list.Items {
+		cr := &list.Items[i]
+		if !includeCredentialsRequest(cr, enableTechPreview) {
+			continue
 		}
+		credRequests = append(credRequests, cr)
+	}
+
+	if err := detectDuplicateSecretRefs(credRequests); err != nil {
+		return nil, err
 	}
 
 	return credRequests, nil
 }
+
+// RunWithTimeout runs op and returns its error, but gives up and returns a timeout error if op
+// has not finished within timeout. A timeout of zero or less disables the deadline and simply
+// runs op directly. op is expected to be a single cloud operation (e.g. one IAM role creation)
+// rather than an entire command invocation, so a slow step early in a large run doesn't cause
+// later, unrelated operations to be judged against the same clock.
+//
+// Note that op keeps running in its goroutine after a timeout is reported; the underlying AWS SDK
+// calls in this package don't yet accept a context.Context to cancel them outright (see
+// pkg/aws/client.go), so RunWithTimeout can only stop waiting on a stuck call, not abort it.
+func RunWithTimeout(timeout time.Duration, op func() error) error {
+	if timeout <= 0 {
+		return op()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- op()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("operation timed out after %s", timeout)
+	}
+}
+
+// FilterCredentialsRequestsByName returns only the CredentialsRequests from credRequests whose
+// metadata.name is in only. If only is empty, credRequests is returned unfiltered. It is an
+// error for any name in only to not match a CredentialsRequest, since that most likely indicates
+// a typo that would otherwise silently no-op.
+func FilterCredentialsRequestsByName(credRequests []*credreqv1.CredentialsRequest, only []string) ([]*credreqv1.CredentialsRequest, error) {
+	if len(only) == 0 {
+		return credRequests, nil
+	}
+
+	wanted := sets.NewString(only...)
+	matched := sets.NewString()
+	filtered := []*credreqv1.CredentialsRequest{}
+	for _, cr := range credRequests {
+		if wanted.Has(cr.Name) {
+			matched.Insert(cr.Name)
+			filtered = append(filtered, cr)
+		}
+	}
+
+	if missing := wanted.Difference(matched); missing.Len() > 0 {
+		return nil, fmt.Errorf("no CredentialsRequest found matching name(s): %s", strings.Join(missing.List(), ", "))
+	}
+
+	return filtered, nil
+}
+
+// ExcludeCredentialsRequestsByName returns credRequests with any CredentialsRequest named in
+// exclude removed. It errors if a name in exclude doesn't match any CredentialsRequest, to catch
+// typos. Intended to be applied after FilterCredentialsRequestsByName, so that --exclude wins on
+// any overlap with --only.
+func ExcludeCredentialsRequestsByName(credRequests []*credreqv1.CredentialsRequest, exclude []string) ([]*credreqv1.CredentialsRequest, error) {
+	if len(exclude) == 0 {
+		return credRequests, nil
+	}
+
+	unwanted := sets.NewString(exclude...)
+	matched := sets.NewString()
+	filtered := []*credreqv1.CredentialsRequest{}
+	for _, cr := range credRequests {
+		if unwanted.Has(cr.Name) {
+			matched.Insert(cr.Name)
+			continue
+		}
+		filtered = append(filtered, cr)
+	}
+
+	if missing := unwanted.Difference(matched); missing.Len() > 0 {
+		return nil, fmt.Errorf("no CredentialsRequest found matching name(s): %s", strings.Join(missing.List(), ", "))
+	}
+
+	return filtered, nil
+}
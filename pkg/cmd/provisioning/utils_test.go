@@ -97,11 +97,12 @@ func TestEnsureDir(t *testing.T) {
 
 func TestFilteringCredReqs(t *testing.T) {
 	tests := []struct {
-		name              string
-		setup             func(*testing.T)
-		expectError       bool
-		verify            func(*testing.T, []*credreqv1.CredentialsRequest)
-		enableTechPreview bool
+		name                       string
+		setup                      func(*testing.T)
+		expectError                bool
+		verify                     func(*testing.T, []*credreqv1.CredentialsRequest)
+		enableTechPreview          bool
+		requireServiceAccountNames bool
 	}{
 		{
 			name: "ignore CredReq marked as tech-preview",
@@ -146,6 +147,54 @@ func TestFilteringCredReqs(t *testing.T) {
 				assert.Equal(t, 0, len(credReqs))
 			},
 		},
+		{
+			name: "accept CredReq with known prior apiVersion",
+			setup: func(t *testing.T) {
+				testNewCredReqWithAPIVersion(t, "credReqA", "cloudcredential.openshift.io/v1beta1")
+			},
+			verify: func(t *testing.T, credReqs []*credreqv1.CredentialsRequest) {
+				assert.Equal(t, 1, len(credReqs))
+			},
+		},
+		{
+			name: "reject CredReq with unrecognized apiVersion",
+			setup: func(t *testing.T) {
+				testNewCredReqWithAPIVersion(t, "credReqA", "cloudcredential.openshift.io/v2")
+			},
+			expectError: true,
+		},
+		{
+			name: "reject CredReq with no serviceAccountNames when required",
+			setup: func(t *testing.T) {
+				testNewCredReq(t, "credReqA")
+			},
+			requireServiceAccountNames: true,
+			expectError:                true,
+		},
+		{
+			name: "accept CredReq with no serviceAccountNames when not required",
+			setup: func(t *testing.T) {
+				testNewCredReq(t, "credReqA")
+			},
+			requireServiceAccountNames: false,
+			verify: func(t *testing.T, credReqs []*credreqv1.CredentialsRequest) {
+				assert.Equal(t, 1, len(credReqs))
+			},
+		},
+		{
+			name: "accept CredReq with serviceAccountNames when required",
+			setup: func(t *testing.T) {
+				cr := NewCredentialsRequestBuilder().
+					Options(WithName("credReqA")).
+					Options(WithServiceAccountNames("default")).
+					Build()
+				saveCredReq(t, cr)
+			},
+			requireServiceAccountNames: true,
+			verify: func(t *testing.T, credReqs []*credreqv1.CredentialsRequest) {
+				assert.Equal(t, 1, len(credReqs))
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -158,10 +207,13 @@ func TestFilteringCredReqs(t *testing.T) {
 
 			test.setup(t)
 
-			credReqs, err := GetListOfCredentialsRequests(testDirPath, test.enableTechPreview)
-			require.NoError(t, err, "unexpected error")
-
-			test.verify(t, credReqs)
+			credReqs, err := GetListOfCredentialsRequests(testDirPath, test.enableTechPreview, test.requireServiceAccountNames)
+			if test.expectError {
+				require.Error(t, err, "expected error")
+			} else {
+				require.NoError(t, err, "unexpected error")
+				test.verify(t, credReqs)
+			}
 
 			err = os.RemoveAll(testDirPath)
 			require.NoError(t, err, "failed to clean test environment")
@@ -169,6 +221,56 @@ func TestFilteringCredReqs(t *testing.T) {
 	}
 }
 
+func TestFilterCredentialsRequestsByName(t *testing.T) {
+	credReqs := []*credreqv1.CredentialsRequest{
+		NewCredentialsRequestBuilder().Options(WithName("credReqA")).Build(),
+		NewCredentialsRequestBuilder().Options(WithName("credReqB")).Build(),
+	}
+
+	t.Run("empty only returns everything", func(t *testing.T) {
+		filtered, err := FilterCredentialsRequestsByName(credReqs, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(filtered))
+	})
+
+	t.Run("only keeps named CredentialsRequests", func(t *testing.T) {
+		filtered, err := FilterCredentialsRequestsByName(credReqs, []string{"credReqB"})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(filtered))
+		assert.Equal(t, "credReqB", filtered[0].Name)
+	})
+
+	t.Run("no-match errors", func(t *testing.T) {
+		_, err := FilterCredentialsRequestsByName(credReqs, []string{"credReqC"})
+		assert.Error(t, err)
+	})
+}
+
+func TestExcludeCredentialsRequestsByName(t *testing.T) {
+	credReqs := []*credreqv1.CredentialsRequest{
+		NewCredentialsRequestBuilder().Options(WithName("credReqA")).Build(),
+		NewCredentialsRequestBuilder().Options(WithName("credReqB")).Build(),
+	}
+
+	t.Run("empty returns everything", func(t *testing.T) {
+		filtered, err := ExcludeCredentialsRequestsByName(credReqs, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(filtered))
+	})
+
+	t.Run("drops named CredentialsRequests", func(t *testing.T) {
+		filtered, err := ExcludeCredentialsRequestsByName(credReqs, []string{"credReqB"})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(filtered))
+		assert.Equal(t, "credReqA", filtered[0].Name)
+	})
+
+	t.Run("no-match errors", func(t *testing.T) {
+		_, err := ExcludeCredentialsRequestsByName(credReqs, []string{"credReqC"})
+		assert.Error(t, err)
+	})
+}
+
 func testNewCredReq(t *testing.T, crName string) {
 	cr := NewCredentialsRequestBuilder().
 		Options(WithName(crName)).
@@ -186,6 +288,15 @@ func testNewTechPreviewCredReq(t *testing.T, crName string) {
 	saveCredReq(t, cr)
 }
 
+func testNewCredReqWithAPIVersion(t *testing.T, crName, apiVersion string) {
+	cr := NewCredentialsRequestBuilder().
+		Options(WithName(crName)).
+		Options(WithAPIVersion(apiVersion)).
+		Build()
+
+	saveCredReq(t, cr)
+}
+
 func testNewMarkedForDeletionCredReq(t *testing.T, crName string) {
 	cr := NewCredentialsRequestBuilder().
 		Options(WithName(crName)).
@@ -196,6 +307,10 @@ func testNewMarkedForDeletionCredReq(t *testing.T, crName string) {
 }
 
 func saveCredReq(t *testing.T, credReq *credreqv1.CredentialsRequest) {
+	saveCredReqToDir(t, testDirPath, credReq)
+}
+
+func saveCredReqToDir(t *testing.T, dir string, credReq *credreqv1.CredentialsRequest) {
 	re := &runtime.RawExtension{
 		Object: credReq,
 	}
@@ -203,7 +318,7 @@ func saveCredReq(t *testing.T, credReq *credreqv1.CredentialsRequest) {
 	out, err := re.MarshalJSON()
 	require.NoError(t, err, "error marshaling CredReq")
 
-	f, err := ioutil.TempFile(testDirPath, "credreq-testing-")
+	f, err := ioutil.TempFile(dir, "credreq-testing-")
 	require.NoError(t, err, "error creating temp file")
 	defer f.Close()
 
@@ -211,6 +326,54 @@ func saveCredReq(t *testing.T, credReq *credreqv1.CredentialsRequest) {
 	require.Nil(t, err, "err")
 }
 
+func TestGetListOfCredentialsRequestsMultipleDirs(t *testing.T) {
+	dirA, err := ioutil.TempDir("", "credreq-dirA-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirA)
+
+	dirB, err := ioutil.TempDir("", "credreq-dirB-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirB)
+
+	t.Run("merges CredReqs from multiple directories", func(t *testing.T) {
+		saveCredReqToDir(t, dirA, NewCredentialsRequestBuilder().Options(WithName("credReqA")).Build())
+		saveCredReqToDir(t, dirB, NewCredentialsRequestBuilder().Options(WithName("credReqB")).Build())
+
+		credReqs, err := GetListOfCredentialsRequests(dirA+","+dirB, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(credReqs))
+	})
+
+	t.Run("dedups an identical CredReq found in more than one directory", func(t *testing.T) {
+		dirC, err := ioutil.TempDir("", "credreq-dirC-")
+		require.NoError(t, err)
+		defer os.RemoveAll(dirC)
+
+		cr := NewCredentialsRequestBuilder().Options(WithName("credReqDup")).Build()
+		saveCredReqToDir(t, dirA, cr)
+		saveCredReqToDir(t, dirC, cr)
+
+		credReqs, err := GetListOfCredentialsRequests(dirA+","+dirC, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(credReqs)) // credReqA (from the prior subtest) + credReqDup once
+	})
+
+	t.Run("errors on conflicting duplicates across directories", func(t *testing.T) {
+		dirD, err := ioutil.TempDir("", "credreq-dirD-")
+		require.NoError(t, err)
+		defer os.RemoveAll(dirD)
+
+		saveCredReqToDir(t, dirD, NewCredentialsRequestBuilder().
+			Options(WithName("credReqConflict")).
+			Options(WithServiceAccountNames("default")).
+			Build())
+		saveCredReqToDir(t, dirB, NewCredentialsRequestBuilder().Options(WithName("credReqConflict")).Build())
+
+		_, err = GetListOfCredentialsRequests(dirD+","+dirB, false, false)
+		assert.Error(t, err)
+	})
+}
+
 type option func(*credreqv1.CredentialsRequest)
 
 func Build(opts ...option) *credreqv1.CredentialsRequest {
@@ -261,6 +424,18 @@ func WithTechPreviewAnnotation() option {
 	}
 }
 
+func WithAPIVersion(apiVersion string) option {
+	return func(credreq *credreqv1.CredentialsRequest) {
+		credreq.APIVersion = apiVersion
+	}
+}
+
+func WithServiceAccountNames(names ...string) option {
+	return func(credreq *credreqv1.CredentialsRequest) {
+		credreq.Spec.ServiceAccountNames = names
+	}
+}
+
 func WithDeletionAnnotation() option {
 	return func(credreq *credreqv1.CredentialsRequest) {
 		if credreq.Annotations == nil {
@@ -269,3 +444,106 @@ func WithDeletionAnnotation() option {
 		credreq.Annotations[deletionAnnotation] = "true"
 	}
 }
+
+func WithSecretRef(namespace, name string) option {
+	return func(credreq *credreqv1.CredentialsRequest) {
+		credreq.Spec.SecretRef.Namespace = namespace
+		credreq.Spec.SecretRef.Name = name
+	}
+}
+
+func TestDetectDuplicateSecretRefs(t *testing.T) {
+	tests := []struct {
+		name        string
+		credReqs    []*credreqv1.CredentialsRequest
+		expectError bool
+	}{
+		{
+			name: "no collisions",
+			credReqs: []*credreqv1.CredentialsRequest{
+				Build(WithName("cr1"), WithSecretRef("openshift-cloud-credential-operator", "secret1")),
+				Build(WithName("cr2"), WithSecretRef("openshift-cloud-credential-operator", "secret2")),
+			},
+		},
+		{
+			name: "colliding secretRef",
+			credReqs: []*credreqv1.CredentialsRequest{
+				Build(WithName("cr1"), WithSecretRef("openshift-cloud-credential-operator", "shared-secret")),
+				Build(WithName("cr2"), WithSecretRef("openshift-cloud-credential-operator", "shared-secret")),
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := detectDuplicateSecretRefs(test.credReqs)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetCredentialsRequestFromFile(t *testing.T) {
+	os.Mkdir(testDirPath, 0777)
+	defer os.RemoveAll(testDirPath)
+
+	cr := NewCredentialsRequestBuilder().
+		Options(WithName("credReqA")).
+		Options(WithSecretRef("namespace1", "secretName1")).
+		Build()
+	saveCredReq(t, cr)
+
+	files, err := ioutil.ReadDir(testDirPath)
+	require.NoError(t, err, "error reading test dir")
+	require.Len(t, files, 1)
+
+	got, err := GetCredentialsRequestFromFile(testDirPath + "/" + files[0].Name())
+	require.NoError(t, err, "unexpected error reading CredentialsRequest from file")
+	assert.Equal(t, "credReqA", got.Name)
+	assert.Equal(t, "namespace1", got.Spec.SecretRef.Namespace)
+	assert.Equal(t, "secretName1", got.Spec.SecretRef.Name)
+}
+
+func TestParseNamespacedName(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedNS    string
+		expectedName  string
+		expectedError bool
+	}{
+		{
+			name:         "valid namespace/name",
+			input:        "openshift-cloud-credential-operator/my-secret",
+			expectedNS:   "openshift-cloud-credential-operator",
+			expectedName: "my-secret",
+		},
+		{
+			name:          "missing namespace",
+			input:         "/my-secret",
+			expectedError: true,
+		},
+		{
+			name:          "no slash",
+			input:         "my-secret",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ns, name, err := parseNamespacedName(test.input)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedNS, ns)
+			assert.Equal(t, test.expectedName, name)
+		})
+	}
+}
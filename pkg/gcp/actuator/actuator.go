@@ -379,7 +379,7 @@ func (a *Actuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequest
 
 	// Set policy/role binding to the service account
 	svcAcctBindingName := ServiceAccountBindingName(serviceAccount)
-	err = EnsurePolicyBindingsForProject(rootGCPClient, gcpSpec.PredefinedRoles, svcAcctBindingName)
+	err = EnsurePolicyBindingsForProject(rootGCPClient, gcpSpec.PredefinedRoles, svcAcctBindingName, nil)
 	if err != nil {
 		return err
 	}
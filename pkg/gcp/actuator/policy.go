@@ -25,8 +25,12 @@ import (
 	ccgcp "github.com/openshift/cloud-credential-operator/pkg/gcp"
 )
 
-// EnsurePolicyBindingsForProject ensures that given roles and member, appropriate binding is added to project
-func EnsurePolicyBindingsForProject(rootClient ccgcp.Client, roles []string, member string) error {
+// EnsurePolicyBindingsForProject ensures that given roles and member, appropriate binding is added to project.
+// If condition is non-nil, the binding is created as a conditional binding (a GCP IAM condition, e.g. a CEL
+// expression scoping the binding by resource or time) rather than an unconditional one; GCP treats a
+// conditional binding for a role as distinct from an unconditional binding for the same role, so the two never
+// get merged.
+func EnsurePolicyBindingsForProject(rootClient ccgcp.Client, roles []string, member string, condition *cloudresourcemanager.Expr) error {
 	needPolicyUpdate := false
 
 	projectName := rootClient.GetProjectName()
@@ -41,7 +45,7 @@ func EnsurePolicyBindingsForProject(rootClient ccgcp.Client, roles []string, mem
 		// Earlier we've verified that the requested roles already exist.
 
 		// Add policy binding
-		modified := addPolicyBindingForProject(policy, definedRole, member)
+		modified := addPolicyBindingForProject(policy, definedRole, member, condition)
 		if modified {
 			needPolicyUpdate = true
 		}
@@ -170,19 +174,29 @@ func purgeExtraPolicyBindingsForServiceAccount(policy *iam.Policy, roleList []st
 	return modifiedPolicy
 }
 
-func addPolicyBindingForProject(policy *cloudresourcemanager.Policy, roleName, memberName string) bool {
+func addPolicyBindingForProject(policy *cloudresourcemanager.Policy, roleName, memberName string, condition *cloudresourcemanager.Expr) bool {
 	for i, binding := range policy.Bindings {
-		if binding.Role == roleName {
+		if binding.Role == roleName && bindingConditionsEqual(binding.Condition, condition) {
 			return addMemberToBindingForProject(memberName, policy.Bindings[i])
 		}
 	}
 
-	// if we didn't find an existing binding entry, then make one
-	createMemberRoleBindingForProject(policy, roleName, memberName)
+	// if we didn't find an existing binding entry with a matching role and condition, then make one
+	createMemberRoleBindingForProject(policy, roleName, memberName, condition)
 
 	return true
 }
 
+// bindingConditionsEqual reports whether a and b represent the same IAM condition, i.e. both nil
+// (unconditional) or both carrying the same CEL expression. A conditional binding and an
+// unconditional binding for the same role are always distinct bindings, never merged.
+func bindingConditionsEqual(a, b *cloudresourcemanager.Expr) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Expression == b.Expression
+}
+
 func addPolicyBindingForServiceAccount(policy *iam.Policy, roleName, memberName string) bool {
 	for i, binding := range policy.Bindings {
 		if binding.Role == roleName {
@@ -196,10 +210,11 @@ func addPolicyBindingForServiceAccount(policy *iam.Policy, roleName, memberName
 	return true
 }
 
-func createMemberRoleBindingForProject(policy *cloudresourcemanager.Policy, roleName, memberName string) {
+func createMemberRoleBindingForProject(policy *cloudresourcemanager.Policy, roleName, memberName string, condition *cloudresourcemanager.Expr) {
 	policy.Bindings = append(policy.Bindings, &cloudresourcemanager.Binding{
-		Members: []string{memberName},
-		Role:    roleName,
+		Members:   []string{memberName},
+		Role:      roleName,
+		Condition: condition,
 	})
 }
 
@@ -14,6 +14,7 @@ import (
 // Client is a wrapper object for actual IBMCloud SDK clients to allow for easier testing.
 type Client interface {
 	CreatePolicy(*pmv1.CreatePolicyOptions) (*pmv1.Policy, *core.DetailedResponse, error)
+	ListPolicies(*pmv1.ListPoliciesOptions) (*pmv1.PolicyList, *core.DetailedResponse, error)
 	CreateServiceID(*identityv1.CreateServiceIDOptions) (*identityv1.ServiceID, *core.DetailedResponse, error)
 	ListServiceID(*identityv1.ListServiceIdsOptions) (*identityv1.ServiceIDList, *core.DetailedResponse, error)
 	DeleteServiceID(*identityv1.DeleteServiceIDOptions) (*core.DetailedResponse, error)
@@ -77,6 +78,10 @@ func (i *ibmcloudClient) CreatePolicy(options *pmv1.CreatePolicyOptions) (*pmv1.
 	return i.pmClient.CreatePolicy(options)
 }
 
+func (i *ibmcloudClient) ListPolicies(options *pmv1.ListPoliciesOptions) (*pmv1.PolicyList, *core.DetailedResponse, error) {
+	return i.pmClient.ListPolicies(options)
+}
+
 func NewClient(apiKey string, params *ClientParams) (Client, error) {
 	authenticator := &core.IamAuthenticator{
 		ApiKey: apiKey,
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./client.go
+// Source: ./pkg/ibmcloud/client.go
 
 // Package mock is a generated GoMock package.
 package mock
@@ -147,6 +147,22 @@ func (mr *MockClientMockRecorder) ListAPIKeys(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockClient)(nil).ListAPIKeys), arg0)
 }
 
+// ListPolicies mocks base method.
+func (m *MockClient) ListPolicies(arg0 *iampolicymanagementv1.ListPoliciesOptions) (*iampolicymanagementv1.PolicyList, *core.DetailedResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPolicies", arg0)
+	ret0, _ := ret[0].(*iampolicymanagementv1.PolicyList)
+	ret1, _ := ret[1].(*core.DetailedResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPolicies indicates an expected call of ListPolicies.
+func (mr *MockClientMockRecorder) ListPolicies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPolicies", reflect.TypeOf((*MockClient)(nil).ListPolicies), arg0)
+}
+
 // ListResourceGroups mocks base method.
 func (m *MockClient) ListResourceGroups(arg0 *resourcemanagerv2.ListResourceGroupsOptions) (*resourcemanagerv2.ResourceGroupList, *core.DetailedResponse, error) {
 	m.ctrl.T.Helper()
@@ -130,6 +130,13 @@ const (
 	// UpgradeableAnnotation is the annotation CCO will check for on the cloudcredential.operator.openshift.io
 	// CR when determining upgradeability.
 	UpgradeableAnnotation = "cloudcredential.openshift.io/upgradeable-to"
+
+	// AnnotationResyncNow can be set to any changing value (e.g. the current timestamp) on the
+	// cloudcredential.operator.openshift.io CR to force CCO to immediately re-reconcile every
+	// CredentialsRequest, without needing to restart the operator pod. Useful after fixing a
+	// cloud-side issue out of band. CCO only reacts to the annotation's value changing; it does
+	// not clear or otherwise manage the annotation itself.
+	AnnotationResyncNow = "cloudcredential.openshift.io/resync-now"
 )
 
 var (
@@ -29,6 +29,7 @@ import (
 	"github.com/openshift/cloud-credential-operator/pkg/operator/loglevel"
 	"github.com/openshift/cloud-credential-operator/pkg/operator/metrics"
 	"github.com/openshift/cloud-credential-operator/pkg/operator/platform"
+	"github.com/openshift/cloud-credential-operator/pkg/operator/proxy"
 	"github.com/openshift/cloud-credential-operator/pkg/operator/secretannotator"
 	"github.com/openshift/cloud-credential-operator/pkg/operator/status"
 	"github.com/openshift/cloud-credential-operator/pkg/ovirt"
@@ -54,6 +55,7 @@ func init() {
 	AddToManagerFuncs = append(AddToManagerFuncs, status.Add)
 	AddToManagerFuncs = append(AddToManagerFuncs, loglevel.Add)
 	AddToManagerFuncs = append(AddToManagerFuncs, cleanup.Add)
+	AddToManagerFuncs = append(AddToManagerFuncs, proxy.Add)
 	AddToManagerWithActuatorFuncs = append(AddToManagerWithActuatorFuncs, credentialsrequest.AddWithActuator)
 }
 
@@ -64,7 +66,16 @@ var AddToManagerFuncs []func(manager.Manager, string) error
 // AddToManagerWithActuatorFuncs is a list of functions to add all Controllers with Actuators to the Manager
 var AddToManagerWithActuatorFuncs []func(manager.Manager, actuator.Actuator, configv1.PlatformType) error
 
-// AddToManager adds all Controllers to the Manager
+// AddToManager adds all Controllers to the Manager.
+//
+// NOTE: per-provider multiple root credentials (e.g. a hybrid cluster with both an AWS and a
+// vSphere root credential in play at once) are not supported. Exactly one platformType is
+// resolved from the cluster's Infrastructure status below, and exactly one Actuator is
+// constructed and handed to every AddToManagerWithActuatorFuncs entry, so there is nowhere for a
+// second provider's root credential to be selected from. Supporting it would mean dispatching
+// each CredentialsRequest's actuator by its own ProviderSpec kind rather than by a single
+// cluster-wide platformType, which is a larger change than adding a dispatch layer on top of this
+// function; none exists today.
 func AddToManager(m manager.Manager, explicitKubeconfig string) error {
 	for _, f := range AddToManagerFuncs {
 		if err := f(m, explicitKubeconfig); err != nil {
@@ -91,7 +102,7 @@ func AddToManager(m manager.Manager, explicitKubeconfig string) error {
 			}
 		case configv1.AzurePlatformType:
 			log.Info("initializing Azure actuator")
-			a, err = azure.NewActuator(m.GetClient(), util.GetAzureCloudName(infraStatus))
+			a, err = azure.NewActuatorWithARMEndpoint(m.GetClient(), util.GetAzureCloudName(infraStatus), util.GetAzureARMEndpoint(infraStatus))
 			if err != nil {
 				return err
 			}
@@ -132,8 +143,16 @@ func AddToManager(m manager.Manager, explicitKubeconfig string) error {
 				return err
 			}
 		default:
-			log.Info("initializing no-op actuator (unsupported platform)")
-			a = &actuator.DummyActuator{}
+			if constructor, found := actuator.LookupActuator(platformType); found {
+				log.Infof("initializing registered custom actuator for platform %q", platformType)
+				a, err = constructor(m, infraStatus)
+				if err != nil {
+					return err
+				}
+			} else {
+				log.Info("initializing no-op actuator (unsupported platform)")
+				a = &actuator.DummyActuator{}
+			}
 		}
 		if err := f(m, a, platformType); err != nil {
 			return err
@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -17,12 +17,14 @@ package actuator
 
 import (
 	"context"
+	"fmt"
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	minterv1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	"github.com/openshift/cloud-credential-operator/pkg/operator/constants"
@@ -48,6 +50,37 @@ type Actuator interface {
 	GetCredentialsRootSecret(ctx context.Context, cr *minterv1.CredentialsRequest) (*corev1.Secret, error)
 }
 
+// ActuatorConstructor builds an Actuator for a given platform, given the manager and that
+// platform's InfrastructureStatus. It mirrors the constructor signature already used by the
+// in-tree cloud actuators (e.g. awsactuator.NewAWSActuator), so an out-of-tree provider actuator
+// can be registered with RegisterActuator and selected by platform type without requiring edits
+// to the switch in pkg/operator/controller.go.
+type ActuatorConstructor func(m manager.Manager, infraStatus *configv1.InfrastructureStatus) (Actuator, error)
+
+// customActuators holds ActuatorConstructors registered for platform types with no in-tree
+// actuator, keyed by the providerSpec/platform type they handle.
+var customActuators = map[configv1.PlatformType]ActuatorConstructor{}
+
+// RegisterActuator makes constructor the Actuator used for platformType. It is intended to be
+// called from the init() function of a package implementing a custom/downstream provider
+// actuator, compiled into a downstream CCO build alongside a blank import of that package. It
+// panics if platformType is already registered, since two actuators racing to handle the same
+// platform almost certainly indicates a packaging mistake rather than a condition to recover
+// from at runtime.
+func RegisterActuator(platformType configv1.PlatformType, constructor ActuatorConstructor) {
+	if _, exists := customActuators[platformType]; exists {
+		panic(fmt.Sprintf("actuator already registered for platform type %q", platformType))
+	}
+	customActuators[platformType] = constructor
+}
+
+// LookupActuator returns the ActuatorConstructor registered for platformType, if any, and
+// whether one was found.
+func LookupActuator(platformType configv1.PlatformType) (ActuatorConstructor, bool) {
+	constructor, found := customActuators[platformType]
+	return constructor, found
+}
+
 type DummyActuator struct {
 }
 
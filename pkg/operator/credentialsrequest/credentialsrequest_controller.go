@@ -18,8 +18,11 @@ package credentialsrequest
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"sort"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -69,9 +72,29 @@ const (
 	cloudCredDeprovisionSuccess = "CloudCredDeprovisionSuccess"
 
 	credentialsRequestInfraMismatch = "InfrastructureMismatch"
+	credentialsModeManual           = "OperatorDisabled"
+	providerSpecKindDisabled        = "ProviderSpecKindDisabled"
 
 	cloudResourceOrphaned = "CloudResourceOrphaned"
 	cloudResourceCleaned  = "CloudResourceCleaned"
+
+	dependencyCycleDetected = "DependencyCycleDetected"
+	dependencyCycleFree     = "DependencyCycleFree"
+
+	secretWriteFailure = "SecretWriteFailure"
+	secretWriteSuccess = "SecretWriteSuccess"
+
+	cloudQuotaExceeded  = "CloudQuotaExceeded"
+	cloudQuotaAvailable = "CloudQuotaAvailable"
+
+	deferredUntilWindow = "DeferredUntilWindow"
+	withinWindowOrUnset = "WithinMaintenanceWindow"
+
+	crossNamespaceTargetDisallowed = "CrossNamespaceTargetDisallowed"
+	crossNamespaceTargetAllowed    = "CrossNamespaceTargetAllowed"
+
+	additionalSecretDataConfigMapNotFound = "AdditionalSecretDataConfigMapNotFound"
+	additionalSecretDataConfigMapFound    = "AdditionalSecretDataConfigMapFound"
 )
 
 var (
@@ -79,8 +102,34 @@ var (
 	// Set some extra time when requeueing so we are guaranteed that the
 	// syncPeriod has elapsed when we re-reconcile an object.
 	defaultRequeueTime = syncPeriod + time.Minute*10
+
+	// highPrioritySyncPeriod and lowPrioritySyncPeriod scale the default resync interval for
+	// CredentialsRequests that opt into minterv1.SyncPriorityHigh/Low, so a backlog of low-priority
+	// requests doesn't crowd out how promptly high-priority ones get re-checked. These only affect
+	// how soon a CredentialsRequest is put back on the workqueue, not where it lands once there:
+	// controller-runtime's workqueue.RateLimitingInterface has no notion of item priority, so a
+	// high-priority request queued behind a large low-priority backlog still waits its turn in FIFO
+	// order - the scaled requeue interval is the priority lever actually available here.
+	highPrioritySyncPeriod = syncPeriod / 6
+	lowPrioritySyncPeriod  = syncPeriod * 4
+
+	highPriorityRequeueTime = highPrioritySyncPeriod + time.Minute*10
+	lowPriorityRequeueTime  = lowPrioritySyncPeriod + time.Minute*10
 )
 
+// requeueTimeForCR returns how long to wait before the next periodic reconcile of cr, based on its
+// Spec.SyncPriority.
+func requeueTimeForCR(cr *minterv1.CredentialsRequest) time.Duration {
+	switch cr.Spec.SyncPriority {
+	case minterv1.SyncPriorityHigh:
+		return highPriorityRequeueTime
+	case minterv1.SyncPriorityLow:
+		return lowPriorityRequeueTime
+	default:
+		return defaultRequeueTime
+	}
+}
+
 // AddWithActuator creates a new CredentialsRequest Controller and adds it to the Manager with
 // default RBAC. The Manager will set fields on the Controller and Start it when
 // the Manager is Started.
@@ -209,7 +258,21 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	adminCredSecretPredicate := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return isAdminCredSecret(e.ObjectNew.GetNamespace(), e.ObjectNew.GetName())
+			if !isAdminCredSecret(e.ObjectNew.GetNamespace(), e.ObjectNew.GetName()) {
+				return false
+			}
+			oldSecret, ok1 := e.ObjectOld.(*corev1.Secret)
+			newSecret, ok2 := e.ObjectNew.(*corev1.Secret)
+			if ok1 && ok2 && reflect.DeepEqual(oldSecret.Data, newSecret.Data) {
+				// Only the metadata changed, not the credential content itself, so there's
+				// nothing new for the actuators to pick up. Every actuator already rebuilds
+				// its cloud client from this Secret's live content on each reconcile, so an
+				// actual rotation is picked up without a pod restart once we requeue here.
+				return false
+			}
+			log.WithField("secret", fmt.Sprintf("%s/%s", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName())).
+				Info("root cloud credential secret rotated, resyncing all CredentialsRequests")
+			return true
 		},
 		CreateFunc: func(e event.CreateEvent) bool {
 			return isAdminCredSecret(e.Object.GetNamespace(), e.Object.GetName())
@@ -303,10 +366,27 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	// Watch the CloudCredential config object and reconcile everything on changes.
+	// Watch the CloudCredential config object and reconcile everything on changes. This is also
+	// what makes constants.AnnotationResyncNow work: an admin bumping that annotation (e.g. to
+	// the current timestamp) updates this object, which is picked up here like any other config
+	// change and triggers an immediate full reconcile of every CredentialsRequest, without
+	// needing to restart the operator pod.
+	resyncNowPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldValue := e.ObjectOld.GetAnnotations()[constants.AnnotationResyncNow]
+			newValue := e.ObjectNew.GetAnnotations()[constants.AnnotationResyncNow]
+			if newValue != "" && newValue != oldValue {
+				log.WithField(constants.AnnotationResyncNow, newValue).Info("admin-triggered full resync requested")
+			}
+			return true
+		},
+		CreateFunc: func(e event.CreateEvent) bool { return true },
+		DeleteFunc: func(e event.DeleteEvent) bool { return true },
+	}
 	err = c.Watch(
 		&source.Kind{Type: &operatorv1.CloudCredential{}},
 		allCredRequestsMapFn,
+		resyncNowPredicate,
 	)
 	if err != nil {
 		return err
@@ -375,8 +455,11 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 	} else if conflict {
 		logger.Error("configuration conflict betwen legacy configmap and operator config")
 		return reconcile.Result{}, fmt.Errorf("configuration conflict")
-	} else if mode == operatorv1.CloudCredentialsModeManual {
-		logger.Infof("operator set to disabled / manual mode")
+	}
+
+	resyncNowValue, err := utils.GetResyncNowValue(r.Client, logger)
+	if err != nil {
+		logger.WithError(err).Error("error checking resync-now annotation")
 		return reconcile.Result{}, err
 	}
 
@@ -398,6 +481,16 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 	origCR := cr
 	cr = cr.DeepCopy()
 
+	if mode == operatorv1.CloudCredentialsModeManual {
+		logger.Infof("operator set to disabled / manual mode")
+		setIgnoredConditionWithReason(cr, credentialsModeManual,
+			"cloud-credential-operator is in manual/disabled mode and is not provisioning credentials for this request")
+		if err := utils.UpdateStatus(r.Client, origCR, cr, logger); err != nil {
+			logger.WithError(err).Error("failed to update conditions")
+		}
+		return reconcile.Result{}, nil
+	}
+
 	// Ignore CR if it's for a different cloud/infra
 	infraMatch, err := crInfraMatches(cr, r.platformType)
 	if err != nil {
@@ -414,9 +507,38 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, err
 	}
 
+	// Every providerSpec kind is enabled by default. An administrator hardening a locked-down
+	// cluster can opt into restricting CCO to an explicit allowlist of kinds via
+	// enabledProviderSpecKinds in the operator config ConfigMap, so an unexpected credential type
+	// is ignored rather than acted on even if it matches the cluster's platform.
+	providerSpecKind, err := utils.GetCredentialsRequestCloudType(cr.Spec.ProviderSpec)
+	if err != nil {
+		logger.WithError(err).Error("error determining providerSpec kind")
+		return reconcile.Result{}, err
+	}
+	kindEnabled, err := utils.ProviderSpecKindEnabled(r.Client, logger, providerSpecKind)
+	if err != nil {
+		logger.WithError(err).Error("error checking enabled providerSpec kinds")
+		return reconcile.Result{}, err
+	}
+	if !kindEnabled {
+		logger.Warnf("ignoring cr as providerSpec kind %q is not in the operator's enabledProviderSpecKinds allowlist", providerSpecKind)
+		setIgnoredConditionWithReason(cr, providerSpecKindDisabled,
+			fmt.Sprintf("providerSpec kind %q is not in the operator's enabledProviderSpecKinds allowlist", providerSpecKind))
+		err := utils.UpdateStatus(r.Client, origCR, cr, logger)
+		if err != nil {
+			logger.WithError(err).Error("failed to update conditions")
+		}
+		return reconcile.Result{}, err
+	}
+
 	// Handle deletion and the deprovision finalizer:
 	if cr.DeletionTimestamp != nil {
 		if HasFinalizer(cr, minterv1.FinalizerDeprovision) {
+			if err := utils.WaitForCloudAPIRateLimit(ctx); err != nil {
+				logger.WithError(err).Error("error waiting on cloud API rate limiter")
+				return reconcile.Result{}, err
+			}
 			err = r.Actuator.Delete(context.TODO(), cr)
 			if err != nil {
 				logger.WithError(err).Error("actuator error deleting credentials exist")
@@ -482,6 +604,61 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 		}
 	}
 
+	// If this request depends on other CredentialsRequests being provisioned first, don't
+	// proceed until they are, to avoid thundering-herd provisioning and ordering races.
+	if len(cr.Spec.DependsOn) > 0 {
+		cycle, err := r.dependsOnCycle(cr)
+		if err != nil {
+			logger.WithError(err).Error("error checking dependsOn for a cycle")
+			return reconcile.Result{}, err
+		}
+		if cycle {
+			logger.Error("dependsOn forms a cycle, refusing to provision until it is broken")
+			setDependencyCycleCondition(cr, true)
+			if err := utils.UpdateStatus(r.Client, origCR, cr, logger); err != nil {
+				logger.WithError(err).Error("error updating condition")
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{RequeueAfter: requeueTimeForCR(cr)}, nil
+		}
+		setDependencyCycleCondition(cr, false)
+
+		ready, err := r.dependenciesProvisioned(cr)
+		if err != nil {
+			logger.WithError(err).Error("error checking whether dependsOn CredentialsRequests are provisioned")
+			return reconcile.Result{}, err
+		}
+		if !ready {
+			logger.Debug("waiting for dependsOn CredentialsRequests to be provisioned")
+			if err := utils.UpdateStatus(r.Client, origCR, cr, logger); err != nil {
+				logger.WithError(err).Error("error updating condition")
+				return reconcile.Result{}, err
+			}
+			// Nothing enqueues this request when a dependency becomes provisioned; it is
+			// re-checked after requeueTimeForCR(cr), or sooner on an event for this request.
+			return reconcile.Result{RequeueAfter: requeueTimeForCR(cr)}, nil
+		}
+	}
+
+	// Cross-namespace secretRef targets are allowed by default, same as today. An administrator
+	// can opt into restricting them by setting crossNamespaceSecretAllowlist in the operator
+	// config ConfigMap, in which case only the listed target namespaces are permitted.
+	crossNamespaceAllowed, err := utils.CrossNamespaceSecretTargetAllowed(r.Client, logger, cr.Namespace, cr.Spec.SecretRef.Namespace)
+	if err != nil {
+		logger.WithError(err).Error("error checking cross-namespace secret target allowlist")
+		return reconcile.Result{}, err
+	}
+	if !crossNamespaceAllowed {
+		logger.Warnf("secretRef namespace %q is not allowed for a CredentialsRequest in namespace %q", cr.Spec.SecretRef.Namespace, cr.Namespace)
+		setCrossNamespaceTargetCondition(cr, true)
+		if err := utils.UpdateStatus(r.Client, origCR, cr, logger); err != nil {
+			logger.WithError(err).Error("error updating condition")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+	setCrossNamespaceTargetCondition(cr, false)
+
 	// Ensure the target namespace exists for the secret, if not, there's no point
 	// continuing:
 	targetNS := &corev1.Namespace{}
@@ -520,6 +697,12 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 		}
 	} else {
 		crSecretExists = true
+		if cr.Status.TargetSecretResourceVersion != "" && crSecret.ResourceVersion != cr.Status.TargetSecretResourceVersion {
+			if observedHash := hashSecretData(crSecret.Data); observedHash != cr.Status.TargetSecretHash {
+				logger.Warnf("target secret %s/%s content has diverged from what this controller last wrote (resourceVersion %s), possible out-of-band edit",
+					crSecret.Namespace, crSecret.Name, cr.Status.TargetSecretResourceVersion)
+			}
+		}
 	}
 
 	credentialsRootSecret, err := r.Actuator.GetCredentialsRootSecret(context.TODO(), cr)
@@ -530,13 +713,27 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 	isStale := cr.Generation != cr.Status.LastSyncGeneration
 	hasRecentlySynced := cr.Status.LastSyncTimestamp != nil && cr.Status.LastSyncTimestamp.Add(syncPeriod).After(time.Now())
 	hasActiveFailureConditions := checkForFailureConditions(cr)
+	rotateRequested := cr.Annotations[minterv1.AnnotationRotate] == "true"
+	resyncNowRequested := resyncNowValue != "" && resyncNowValue != cr.Status.LastResyncNowObserved
+	if cr.Spec.SyncPriority == minterv1.SyncPriorityHigh {
+		// A high-priority request counts as recently synced only for its shorter sync period.
+		hasRecentlySynced = cr.Status.LastSyncTimestamp != nil && cr.Status.LastSyncTimestamp.Add(highPrioritySyncPeriod).After(time.Now())
+	}
+	if resyncNowRequested {
+		logger.WithField(constants.AnnotationResyncNow, resyncNowValue).Info("admin-triggered resync-now value changed, forcing sync")
+	}
 
-	if !cloudCredsSecretUpdated && !isStale && hasRecentlySynced && crSecretExists && !hasActiveFailureConditions && cr.Status.Provisioned {
+	if !cloudCredsSecretUpdated && !isStale && hasRecentlySynced && crSecretExists && !hasActiveFailureConditions && cr.Status.Provisioned && !rotateRequested && !resyncNowRequested {
 		logger.Debug("lastsyncgeneration is current and lastsynctimestamp was less than an hour ago, so no need to sync")
 		// Since we get no events for changes made directly to the cloud/platform, set the requeueAfter so that we at
 		// least periodically check that nothing out in the cloud/platform was modified that would require us to fix up
 		// users/permissions/tags/etc.
-		return reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
+		return reconcile.Result{RequeueAfter: requeueTimeForCR(cr)}, nil
+	}
+
+	if err := utils.WaitForCloudAPIRateLimit(ctx); err != nil {
+		logger.WithError(err).Error("error waiting on cloud API rate limiter")
+		return reconcile.Result{}, err
 	}
 
 	credsExists, err := r.Actuator.Exists(context.TODO(), cr)
@@ -545,13 +742,45 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, err
 	}
 
+	if !rotateRequested {
+		withinWindow, err := utils.IsWithinMaintenanceWindow(r.Client, logger)
+		if err != nil {
+			logger.WithError(err).Error("error checking maintenance window")
+			return reconcile.Result{}, err
+		}
+		if !withinWindow {
+			logger.Info("outside configured maintenance window, deferring cloud mutation")
+			setDeferredUntilWindowCondition(cr, true)
+			if err := utils.UpdateStatus(r.Client, origCR, cr, logger); err != nil {
+				logger.WithError(err).Error("failed to update condition")
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{RequeueAfter: requeueTimeForCR(cr)}, nil
+		}
+		setDeferredUntilWindowCondition(cr, false)
+	} else {
+		logger.Info("rotate annotation present, bypassing maintenance window")
+		setDeferredUntilWindowCondition(cr, false)
+	}
+
 	var syncErr error
 	if !credsExists {
 		syncErr = r.Actuator.Create(context.TODO(), cr)
+	} else if rotateRequested {
+		logger.Info("rotate annotation present, forcing credentials rotation")
+		syncErr = r.Actuator.Update(context.TODO(), cr)
 	} else {
 		syncErr = r.Actuator.Update(context.TODO(), cr)
 	}
 
+	if rotateRequested && syncErr == nil {
+		delete(cr.Annotations, minterv1.AnnotationRotate)
+		if err := r.Update(context.TODO(), cr); err != nil {
+			logger.WithError(err).Error("failed to clear rotate annotation")
+			return reconcile.Result{}, err
+		}
+	}
+
 	var provisionErr bool
 	if syncErr != nil {
 		switch t := syncErr.(type) {
@@ -590,6 +819,24 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 		if credentialsRootSecret != nil {
 			cr.Status.LastSyncCloudCredsSecretResourceVersion = credentialsRootSecret.ResourceVersion
 		}
+		cr.Status.LastResyncNowObserved = resyncNowValue
+
+		updatedSecret := &corev1.Secret{}
+		if err := r.Get(context.TODO(), secretKey, updatedSecret); err != nil {
+			logger.WithError(err).Warning("error retrieving target secret to record its resourceVersion/hash")
+		} else {
+			cr.Status.TargetSecretResourceVersion = updatedSecret.ResourceVersion
+			cr.Status.TargetSecretHash = hashSecretData(updatedSecret.Data)
+		}
+
+		if err := r.deleteStaleSecretIfSecretRefChanged(cr, logger); err != nil {
+			logger.WithError(err).Error("error deleting stale secret at previous secretRef location")
+			return reconcile.Result{}, err
+		}
+		cr.Status.LastWrittenSecretRef = &corev1.ObjectReference{
+			Namespace: cr.Spec.SecretRef.Namespace,
+			Name:      cr.Spec.SecretRef.Name,
+		}
 	}
 
 	err = utils.UpdateStatus(r.Client, origCR, cr, logger)
@@ -605,9 +852,9 @@ func (r *ReconcileCredentialsRequest) Reconcile(ctx context.Context, request rec
 		// We could have a non-critical error (eg OrphanedCloudResource) in the syncErr
 		// but we wouldn't want to treat that as an overal controller error while
 		// reconciling.
-		return reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
+		return reconcile.Result{RequeueAfter: requeueTimeForCR(cr)}, nil
 	} else {
-		return reconcile.Result{RequeueAfter: defaultRequeueTime}, syncErr
+		return reconcile.Result{RequeueAfter: requeueTimeForCR(cr)}, syncErr
 	}
 }
 
@@ -633,6 +880,25 @@ func (r *ReconcileCredentialsRequest) updateActuatorConditions(cr *minterv1.Cred
 		setOrphanedCloudResourceCondition(cr, false, conditionError)
 	}
 
+	if reason == minterv1.SecretWriteFailure {
+		setSecretWriteFailureCondition(cr, true, conditionError)
+	} else {
+		// If this is not our error, ensure the condition is cleared.
+		setSecretWriteFailureCondition(cr, false, nil)
+	}
+
+	if reason == minterv1.AdditionalSecretDataConfigMapNotFound {
+		setAdditionalSecretDataConfigMapNotFoundCondition(cr, true, conditionError)
+	} else {
+		// If this is not our error, ensure the condition is cleared.
+		setAdditionalSecretDataConfigMapNotFoundCondition(cr, false, nil)
+	}
+
+	// Quota exhaustion isn't a distinct actuator Reason - it surfaces as a
+	// CredentialsProvisionFailure whose underlying error happens to be quota-related - so detect it
+	// from conditionError directly rather than switching on reason like the conditions above.
+	setCloudQuotaExceededCondition(cr, utils.IsQuotaExceededError(conditionError), conditionError)
+
 	return
 }
 
@@ -657,6 +923,119 @@ func setMissingTargetNamespaceCondition(cr *minterv1.CredentialsRequest, missing
 		status, reason, msg, updateCheck)
 }
 
+func setCrossNamespaceTargetCondition(cr *minterv1.CredentialsRequest, disallowed bool) {
+	var (
+		msg, reason string
+		status      corev1.ConditionStatus
+		updateCheck utils.UpdateConditionCheck
+	)
+	if disallowed {
+		msg = fmt.Sprintf("secretRef namespace %q differs from CredentialsRequest namespace %q and is not in the operator's crossNamespaceSecretAllowlist", cr.Spec.SecretRef.Namespace, cr.Namespace)
+		status = corev1.ConditionTrue
+		reason = crossNamespaceTargetDisallowed
+		updateCheck = utils.UpdateConditionIfReasonOrMessageChange
+	} else {
+		msg = "secretRef namespace matches the CredentialsRequest's namespace, or is allowed by the operator's crossNamespaceSecretAllowlist"
+		status = corev1.ConditionFalse
+		reason = crossNamespaceTargetAllowed
+		updateCheck = utils.UpdateConditionNever
+	}
+	cr.Status.Conditions = utils.SetCredentialsRequestCondition(cr.Status.Conditions, minterv1.CrossNamespaceTargetDisallowed,
+		status, reason, msg, updateCheck)
+}
+
+func setDependencyCycleCondition(cr *minterv1.CredentialsRequest, cycle bool) {
+	var (
+		msg, reason string
+		status      corev1.ConditionStatus
+		updateCheck utils.UpdateConditionCheck
+	)
+	if cycle {
+		msg = "spec.dependsOn forms a cycle with one or more other CredentialsRequests"
+		status = corev1.ConditionTrue
+		reason = dependencyCycleDetected
+		updateCheck = utils.UpdateConditionIfReasonOrMessageChange
+	} else {
+		msg = "spec.dependsOn does not form a cycle"
+		status = corev1.ConditionFalse
+		reason = dependencyCycleFree
+		updateCheck = utils.UpdateConditionNever
+	}
+	cr.Status.Conditions = utils.SetCredentialsRequestCondition(cr.Status.Conditions, minterv1.CredentialsRequestDependencyCycle,
+		status, reason, msg, updateCheck)
+}
+
+// dependsOnNamespacedName resolves a dependsOn entry's namespace, defaulting to the
+// referencing CredentialsRequest's own namespace when left unset.
+func dependsOnNamespacedName(cr *minterv1.CredentialsRequest, ref corev1.ObjectReference) types.NamespacedName {
+	ns := ref.Namespace
+	if ns == "" {
+		ns = cr.Namespace
+	}
+	return types.NamespacedName{Namespace: ns, Name: ref.Name}
+}
+
+// dependenciesProvisioned returns whether every CredentialsRequest in cr.Spec.DependsOn has
+// been successfully provisioned. A dependency that does not yet exist is treated the same as
+// one that is not yet provisioned, rather than as an error, since it may simply not have been
+// created yet during bulk provisioning.
+func (r *ReconcileCredentialsRequest) dependenciesProvisioned(cr *minterv1.CredentialsRequest) (bool, error) {
+	for _, ref := range cr.Spec.DependsOn {
+		dep := &minterv1.CredentialsRequest{}
+		if err := r.Get(context.TODO(), dependsOnNamespacedName(cr, ref), dep); err != nil {
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if !dep.Status.Provisioned {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// dependsOnCycle walks the dependsOn graph reachable from cr, and reports whether it leads
+// back to cr itself. Dependencies that do not exist are treated as dead ends, not cycles.
+func (r *ReconcileCredentialsRequest) dependsOnCycle(cr *minterv1.CredentialsRequest) (bool, error) {
+	start := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	visited := sets.NewString()
+
+	var visit func(key types.NamespacedName) (bool, error)
+	visit = func(key types.NamespacedName) (bool, error) {
+		if key == start {
+			return true, nil
+		}
+		if visited.Has(key.String()) {
+			return false, nil
+		}
+		visited.Insert(key.String())
+
+		dep := &minterv1.CredentialsRequest{}
+		if err := r.Get(context.TODO(), key, dep); err != nil {
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		for _, ref := range dep.Spec.DependsOn {
+			found, err := visit(dependsOnNamespacedName(dep, ref))
+			if err != nil || found {
+				return found, err
+			}
+		}
+		return false, nil
+	}
+
+	for _, ref := range cr.Spec.DependsOn {
+		found, err := visit(dependsOnNamespacedName(cr, ref))
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
 func setOrphanedCloudResourceCondition(cr *minterv1.CredentialsRequest, orphaned bool, orphanedErr error) {
 	var (
 		msg, reason string
@@ -721,6 +1100,106 @@ func setFailedToProvisionCredentialsRequest(cr *minterv1.CredentialsRequest, fai
 		status, reason, msg, updateCheck)
 }
 
+// setSecretWriteFailureCondition reflects whether writing the target Secret to the cluster failed,
+// as distinct from CredentialsProvisionFailure, so admission-webhook/quota interference with the
+// Secret itself can be triaged separately from cloud-side provisioning failures.
+func setSecretWriteFailureCondition(cr *minterv1.CredentialsRequest, failed bool, err error) {
+	var (
+		msg, reason string
+		status      corev1.ConditionStatus
+		updateCheck utils.UpdateConditionCheck
+	)
+	if failed {
+		msg = fmt.Sprintf("failed to write target secret: %v", utils.ErrorScrub(err))
+		status = corev1.ConditionTrue
+		reason = secretWriteFailure
+		updateCheck = utils.UpdateConditionIfReasonOrMessageChange
+		metrics.MetricSecretWriteFailuresTotal.Inc()
+	} else {
+		msg = "successfully wrote target secret"
+		status = corev1.ConditionFalse
+		reason = secretWriteSuccess
+		updateCheck = utils.UpdateConditionNever
+	}
+	cr.Status.Conditions = utils.SetCredentialsRequestCondition(cr.Status.Conditions, minterv1.SecretWriteFailure,
+		status, reason, msg, updateCheck)
+}
+
+// setAdditionalSecretDataConfigMapNotFoundCondition reflects whether the providerSpec's
+// AdditionalSecretDataConfigMapRef names a ConfigMap that could not be found, which leaves the
+// target Secret missing the companion config the ConfigMap was meant to contribute.
+func setAdditionalSecretDataConfigMapNotFoundCondition(cr *minterv1.CredentialsRequest, notFound bool, err error) {
+	var (
+		msg, reason string
+		status      corev1.ConditionStatus
+		updateCheck utils.UpdateConditionCheck
+	)
+	if notFound {
+		msg = fmt.Sprintf("additionalSecretDataConfigMapRef not found: %v", utils.ErrorScrub(err))
+		status = corev1.ConditionTrue
+		reason = additionalSecretDataConfigMapNotFound
+		updateCheck = utils.UpdateConditionIfReasonOrMessageChange
+	} else {
+		msg = "additionalSecretDataConfigMapRef resolved successfully"
+		status = corev1.ConditionFalse
+		reason = additionalSecretDataConfigMapFound
+		updateCheck = utils.UpdateConditionNever
+	}
+	cr.Status.Conditions = utils.SetCredentialsRequestCondition(cr.Status.Conditions, minterv1.AdditionalSecretDataConfigMapNotFound,
+		status, reason, msg, updateCheck)
+}
+
+// setCloudQuotaExceededCondition reflects whether the last provisioning failure looked like the
+// cloud account having exhausted an IAM-related quota (see utils.IsQuotaExceededError), so
+// platform teams can alert on quota pressure specifically instead of only on the generic
+// CredentialsProvisionFailure condition.
+func setCloudQuotaExceededCondition(cr *minterv1.CredentialsRequest, exceeded bool, err error) {
+	var (
+		msg, reason string
+		status      corev1.ConditionStatus
+		updateCheck utils.UpdateConditionCheck
+	)
+	if exceeded {
+		msg = fmt.Sprintf("cloud account IAM quota exceeded while provisioning: %v", utils.ErrorScrub(err))
+		status = corev1.ConditionTrue
+		reason = cloudQuotaExceeded
+		updateCheck = utils.UpdateConditionIfReasonOrMessageChange
+		metrics.MetricCloudQuotaExceededTotal.Inc()
+	} else {
+		msg = "cloud account IAM quota not exceeded"
+		status = corev1.ConditionFalse
+		reason = cloudQuotaAvailable
+		updateCheck = utils.UpdateConditionNever
+	}
+	cr.Status.Conditions = utils.SetCredentialsRequestCondition(cr.Status.Conditions, minterv1.CloudQuotaExceeded,
+		status, reason, msg, updateCheck)
+}
+
+// setDeferredUntilWindowCondition reflects whether the controller skipped a needed cloud mutation
+// for this CredentialsRequest because it fell outside the configured maintenance window (see
+// utils.IsWithinMaintenanceWindow). Urgent rotations bypass the window, so this is never set true
+// alongside a rotate annotation request.
+func setDeferredUntilWindowCondition(cr *minterv1.CredentialsRequest, deferred bool) {
+	var (
+		msg, reason string
+		status      corev1.ConditionStatus
+		updateCheck utils.UpdateConditionCheck
+	)
+	if deferred {
+		msg = "outside the configured maintenance window, deferring cloud mutation until the window reopens"
+		status = corev1.ConditionTrue
+		reason = deferredUntilWindow
+		updateCheck = utils.UpdateConditionIfReasonOrMessageChange
+	} else {
+		msg = "within the maintenance window or none is configured"
+		status = corev1.ConditionFalse
+		reason = withinWindowOrUnset
+		updateCheck = utils.UpdateConditionNever
+	}
+	cr.Status.Conditions = utils.SetCredentialsRequestCondition(cr.Status.Conditions, minterv1.DeferredUntilWindow,
+		status, reason, msg, updateCheck)
+}
+
 func setCredentialsDeprovisionFailureCondition(cr *minterv1.CredentialsRequest, failed bool, err error) {
 	var (
 		msg, reason string
@@ -743,9 +1222,14 @@ func setCredentialsDeprovisionFailureCondition(cr *minterv1.CredentialsRequest,
 }
 
 func setIgnoredCondition(cr *minterv1.CredentialsRequest, clusterPlatform configv1.PlatformType) {
-	// Only supporting the ability to set the condition
 	msg := fmt.Sprintf("CredentialsRequest is not for platform %s", clusterPlatform)
-	reason := credentialsRequestInfraMismatch
+	setIgnoredConditionWithReason(cr, credentialsRequestInfraMismatch, msg)
+}
+
+// setIgnoredConditionWithReason sets the Ignored condition with a caller-supplied reason and
+// human-readable message, explaining to an `oc describe credentialsrequest` user why CCO is not
+// acting on this request.
+func setIgnoredConditionWithReason(cr *minterv1.CredentialsRequest, reason, msg string) {
 	updateCheck := utils.UpdateConditionIfReasonOrMessageChange
 	status := corev1.ConditionTrue
 
@@ -760,6 +1244,37 @@ func setIgnoredCondition(cr *minterv1.CredentialsRequest, clusterPlatform config
 	}
 }
 
+// deleteStaleSecretIfSecretRefChanged deletes the Secret at cr.Status.LastWrittenSecretRef if it
+// differs from the current cr.Spec.SecretRef, the namespace/name this controller last successfully
+// wrote to. This is called only after the new Secret has already been written successfully, so a
+// changed secretRef in a manifest update leaves behind no orphaned credential Secret at the old
+// location.
+func (r *ReconcileCredentialsRequest) deleteStaleSecretIfSecretRefChanged(cr *minterv1.CredentialsRequest, logger log.FieldLogger) error {
+	staleRef := cr.Status.LastWrittenSecretRef
+	if staleRef == nil {
+		return nil
+	}
+	if staleRef.Namespace == cr.Spec.SecretRef.Namespace && staleRef.Name == cr.Spec.SecretRef.Name {
+		return nil
+	}
+
+	staleSecret := &corev1.Secret{}
+	staleKey := types.NamespacedName{Namespace: staleRef.Namespace, Name: staleRef.Name}
+	if err := r.Get(context.TODO(), staleKey, staleSecret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	logger.WithField("staleSecret", fmt.Sprintf("%s/%s", staleRef.Namespace, staleRef.Name)).
+		Info("secretRef changed, deleting stale secret at previous location")
+	if err := r.Delete(context.TODO(), staleSecret); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 func (r *ReconcileCredentialsRequest) addDeprovisionFinalizer(cr *minterv1.CredentialsRequest) error {
 	AddFinalizer(cr, minterv1.FinalizerDeprovision)
 	return r.Update(context.TODO(), cr)
@@ -821,6 +1336,25 @@ func crInfraMatches(cr *minterv1.CredentialsRequest, clusterCloudPlatform config
 	}
 }
 
+// hashSecretData computes a deterministic hash of a Secret's data, for detecting when its
+// content has changed out-of-band between reconciles.
+func hashSecretData(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(data[k])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func checkForFailureConditions(cr *minterv1.CredentialsRequest) bool {
 	for _, t := range minterv1.FailureConditionTypes {
 		failureCond := utils.FindCredentialsRequestCondition(cr.Status.Conditions, t)
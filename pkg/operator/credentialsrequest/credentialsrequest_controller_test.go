@@ -34,6 +34,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -185,6 +186,56 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 				assert.True(t, cr.Status.Provisioned)
 				assert.Equal(t, int64(testCRGeneration), int64(cr.Status.LastSyncGeneration))
 				assert.NotNil(t, cr.Status.LastSyncTimestamp)
+				assert.Equal(t, targetSecret.ResourceVersion, cr.Status.TargetSecretResourceVersion)
+				assert.NotEmpty(t, cr.Status.TargetSecretHash)
+			},
+		},
+		{
+			name: "secretRef changed deletes stale secret",
+			existing: []runtime.Object{
+				testOperatorConfig(""),
+				createTestNamespace(testNamespace),
+				createTestNamespace(testSecretNamespace),
+				func() *minterv1.CredentialsRequest {
+					cr := testCredentialsRequest(t)
+					cr.Status.LastWrittenSecretRef = &corev1.ObjectReference{Namespace: "old-ns", Name: "old-secret"}
+					return cr
+				}(),
+				createTestNamespace("old-ns"),
+				testAWSCredsSecret("old-ns", "old-secret", testAWSAccessKeyID, testAWSSecretAccessKey),
+				testAWSCredsSecret("kube-system", "aws-creds", testRootAWSAccessKeyID, testRootAWSSecretAccessKey),
+				testAWSCredsSecret("openshift-cloud-credential-operator", "cloud-credential-operator-iam-ro-creds", testReadAWSAccessKeyID, testReadAWSSecretAccessKey),
+				testClusterVersion(),
+				testInfrastructure(testInfraName),
+			},
+			mockRootAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUser(mockAWSClient)
+				mockCreateUser(mockAWSClient)
+				mockPutUserPolicy(mockAWSClient)
+				mockCreateAccessKey(mockAWSClient, testAWSAccessKeyID, testAWSSecretAccessKey)
+				mockTagUser(mockAWSClient)
+				return mockAWSClient
+			},
+			mockReadAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUserNotFound(mockAWSClient)
+				mockGetUserPolicyMissing(mockAWSClient)
+				mockListAccessKeysEmpty(mockAWSClient)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				require.NotNil(t, targetSecret)
+
+				staleSecret := &corev1.Secret{}
+				err := c.Get(context.TODO(), client.ObjectKey{Name: "old-secret", Namespace: "old-ns"}, staleSecret)
+				assert.True(t, errors.IsNotFound(err), "expected stale secret at the previous secretRef location to be deleted")
+
+				cr := getCR(c)
+				require.NotNil(t, cr.Status.LastWrittenSecretRef)
+				assert.Equal(t, testSecretNamespace, cr.Status.LastWrittenSecretRef.Namespace)
+				assert.Equal(t, testSecretName, cr.Status.LastWrittenSecretRef.Name)
 			},
 		},
 		{
@@ -872,6 +923,66 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 				assert.Equal(t, testTwentyMinuteOldTimestamp.Unix(), cr.Status.LastSyncTimestamp.Time.Unix())
 			},
 		},
+		{
+			name: "rotate annotation bypasses recently synced skip",
+			existing: []runtime.Object{
+				testOperatorConfig(""),
+				createTestNamespace(testNamespace),
+				testInfrastructure(testInfraName),
+				createTestNamespace(testSecretNamespace),
+				testCredentialsRequestWithRecentLastSyncAndRotateRequested(t),
+				testAWSCredsSecret("kube-system", "aws-creds", testRootAWSAccessKeyID, testRootAWSSecretAccessKey),
+				testAWSCredsSecret(testSecretNamespace, testSecretName, testAWSAccessKeyID, testAWSSecretAccessKey),
+				testClusterVersion(),
+			},
+			mockRootAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUser(mockAWSClient)
+				mockGetUserPolicy(mockAWSClient, testPolicy1)
+				mockListAccessKeys(mockAWSClient, testAWSAccessKeyID)
+				mockDeleteAccessKey(mockAWSClient, testAWSAccessKeyID)
+				mockCreateAccessKey(mockAWSClient, testAWSAccessKeyID2, testAWSSecretAccessKey2)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.NotEqual(t, testTwentyMinuteOldTimestamp.Unix(), cr.Status.LastSyncTimestamp.Time.Unix())
+				assert.NotContains(t, cr.Annotations, minterv1.AnnotationRotate)
+				targetSecret := getSecret(c)
+				require.NotNil(t, targetSecret)
+				assert.Equal(t, testAWSAccessKeyID2, string(targetSecret.Data["aws_access_key_id"]))
+			},
+		},
+		{
+			name: "resync-now annotation bypasses recently synced skip",
+			existing: []runtime.Object{
+				testOperatorConfigWithResyncNow("", "2022-02-02T00:00:00Z"),
+				createTestNamespace(testNamespace),
+				createTestNamespace(testSecretNamespace),
+				testCredentialsRequestWithRecentLastSyncAndStaleResyncNow(t),
+				testAWSCredsSecret("kube-system", "aws-creds", testRootAWSAccessKeyID, testRootAWSSecretAccessKey),
+				testAWSCredsSecret("openshift-cloud-credential-operator", "cloud-credential-operator-iam-ro-creds", testReadAWSAccessKeyID, testReadAWSSecretAccessKey),
+				testAWSCredsSecret(testSecretNamespace, testSecretName, testAWSAccessKeyID, testAWSSecretAccessKey),
+				testClusterVersion(),
+				testInfrastructure(testInfraName),
+			},
+			mockRootAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				return mockAWSClient
+			},
+			mockReadAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUser(mockAWSClient)
+				mockListAccessKeys(mockAWSClient, testAWSAccessKeyID)
+				mockGetUserPolicy(mockAWSClient, testPolicy1)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.NotEqual(t, testTwentyMinuteOldTimestamp.Unix(), cr.Status.LastSyncTimestamp.Time.Unix())
+				assert.Equal(t, "2022-02-02T00:00:00Z", cr.Status.LastResyncNowObserved)
+			},
+		},
 		{
 			name: "regenerate secret if missing",
 			existing: []runtime.Object{
@@ -1031,6 +1142,88 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 				assert.NotNil(t, cr.Status.LastSyncTimestamp)
 			},
 		},
+		{
+			name: "new credential with secretRef outside allowlisted namespaces",
+			existing: []runtime.Object{
+				testOperatorConfig(""),
+				createTestNamespace(testNamespace),
+				createTestNamespace(testSecretNamespace),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      constants.CloudCredOperatorConfigMap,
+						Namespace: minterv1.CloudCredOperatorNamespace,
+					},
+					Data: map[string]string{
+						"crossNamespaceSecretAllowlist": "some-other-namespace",
+					},
+				},
+				testCredentialsRequest(t),
+				testClusterVersion(),
+				testInfrastructure(testInfraName),
+			},
+			mockRootAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				return mockAWSClient
+			},
+			mockReadAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				assert.Nil(t, targetSecret, "expected target secret not to be created for a disallowed cross-namespace target")
+				cr := getCR(c)
+				assert.False(t, cr.Status.Provisioned)
+			},
+			expectedConditions: []ExpectedCondition{
+				{
+					conditionType: minterv1.CrossNamespaceTargetDisallowed,
+					reason:        crossNamespaceTargetDisallowed,
+					status:        corev1.ConditionTrue,
+				},
+			},
+		},
+		{
+			name: "new credential with providerSpec kind not in enabledProviderSpecKinds allowlist",
+			existing: []runtime.Object{
+				testOperatorConfig(""),
+				createTestNamespace(testNamespace),
+				createTestNamespace(testSecretNamespace),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      constants.CloudCredOperatorConfigMap,
+						Namespace: minterv1.CloudCredOperatorNamespace,
+					},
+					Data: map[string]string{
+						"enabledProviderSpecKinds": "AzureProviderSpec",
+					},
+				},
+				testCredentialsRequest(t),
+				testClusterVersion(),
+				testInfrastructure(testInfraName),
+			},
+			mockRootAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				return mockAWSClient
+			},
+			mockReadAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				assert.Nil(t, targetSecret, "expected target secret not to be created for a providerSpec kind not in the allowlist")
+				cr := getCR(c)
+				assert.False(t, cr.Status.Provisioned)
+			},
+			expectedConditions: []ExpectedCondition{
+				{
+					conditionType: minterv1.Ignored,
+					reason:        providerSpecKindDisabled,
+					status:        corev1.ConditionTrue,
+				},
+			},
+		},
 		{
 			name: "new credential but operator disabled via configmap",
 			existing: []runtime.Object{
@@ -1356,6 +1549,117 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 				assert.Equal(t, testCredRootSecretResourceVersion, cr.Status.LastSyncCloudCredsSecretResourceVersion)
 			},
 		},
+		{
+			name: "credential waiting on unprovisioned dependency",
+			existing: []runtime.Object{
+				testOperatorConfig(""),
+				createTestNamespace(testNamespace),
+				createTestNamespace(testSecretNamespace),
+				func() *minterv1.CredentialsRequest {
+					cr := testCredentialsRequest(t)
+					cr.Spec.DependsOn = []corev1.ObjectReference{
+						{Namespace: testNamespace, Name: "some-other-cred-request"},
+					}
+					return cr
+				}(),
+				testAWSCredsSecret("kube-system", "aws-creds", testRootAWSAccessKeyID, testRootAWSSecretAccessKey),
+				testInfrastructure(testInfraName),
+			},
+			mockRootAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				return mockaws.NewMockClient(mockCtrl)
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.False(t, cr.Status.Provisioned, "should not provision until dependency is provisioned")
+				assert.Nil(t, getSecret(c))
+			},
+		},
+		{
+			name: "credential proceeds once dependency is provisioned",
+			existing: []runtime.Object{
+				testOperatorConfig(""),
+				createTestNamespace(testNamespace),
+				createTestNamespace(testSecretNamespace),
+				func() *minterv1.CredentialsRequest {
+					cr := testCredentialsRequest(t)
+					cr.Spec.DependsOn = []corev1.ObjectReference{
+						{Namespace: testNamespace, Name: "some-other-cred-request"},
+					}
+					return cr
+				}(),
+				func() *minterv1.CredentialsRequest {
+					dep := testCredentialsRequest(t)
+					dep.Name = "some-other-cred-request"
+					dep.Status.Provisioned = true
+					return dep
+				}(),
+				testAWSCredsSecret("kube-system", "aws-creds", testRootAWSAccessKeyID, testRootAWSSecretAccessKey),
+				testAWSCredsSecret("openshift-cloud-credential-operator", "cloud-credential-operator-iam-ro-creds", testReadAWSAccessKeyID, testReadAWSSecretAccessKey),
+				testClusterVersion(),
+				testInfrastructure(testInfraName),
+			},
+			mockRootAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUser(mockAWSClient)
+				mockCreateUser(mockAWSClient)
+				mockPutUserPolicy(mockAWSClient)
+				mockCreateAccessKey(mockAWSClient, testAWSAccessKeyID, testAWSSecretAccessKey)
+				mockTagUser(mockAWSClient)
+				return mockAWSClient
+			},
+			mockReadAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUserNotFound(mockAWSClient)
+				mockGetUserPolicyMissing(mockAWSClient)
+				mockListAccessKeysEmpty(mockAWSClient)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+				require.NotNil(t, getSecret(c))
+			},
+		},
+		{
+			name: "dependency cycle is detected",
+			existing: []runtime.Object{
+				testOperatorConfig(""),
+				createTestNamespace(testNamespace),
+				createTestNamespace(testSecretNamespace),
+				func() *minterv1.CredentialsRequest {
+					cr := testCredentialsRequest(t)
+					cr.Spec.DependsOn = []corev1.ObjectReference{
+						{Namespace: testNamespace, Name: "cycle-partner"},
+					}
+					return cr
+				}(),
+				func() *minterv1.CredentialsRequest {
+					partner := testCredentialsRequest(t)
+					partner.Name = "cycle-partner"
+					partner.Spec.DependsOn = []corev1.ObjectReference{
+						{Namespace: testNamespace, Name: testCRName},
+					}
+					return partner
+				}(),
+				testAWSCredsSecret("kube-system", "aws-creds", testRootAWSAccessKeyID, testRootAWSSecretAccessKey),
+				testInfrastructure(testInfraName),
+			},
+			mockRootAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				return mockaws.NewMockClient(mockCtrl)
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.False(t, cr.Status.Provisioned)
+				assert.Nil(t, getSecret(c))
+			},
+			expectedConditions: []ExpectedCondition{
+				{
+					conditionType: minterv1.CredentialsRequestDependencyCycle,
+					reason:        dependencyCycleDetected,
+					status:        corev1.ConditionTrue,
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1488,6 +1792,20 @@ func testCredentialsRequestWithRecentLastSync(t *testing.T) *minterv1.Credential
 	return cr
 }
 
+func testCredentialsRequestWithRecentLastSyncAndRotateRequested(t *testing.T) *minterv1.CredentialsRequest {
+	cr := testCredentialsRequestWithRecentLastSync(t)
+	cr.Annotations = map[string]string{
+		minterv1.AnnotationRotate: "true",
+	}
+	return cr
+}
+
+func testCredentialsRequestWithRecentLastSyncAndStaleResyncNow(t *testing.T) *minterv1.CredentialsRequest {
+	cr := testCredentialsRequestWithRecentLastSync(t)
+	cr.Status.LastResyncNowObserved = "2021-01-01T00:00:00Z"
+	return cr
+}
+
 func testCredentialsRequestWithDeletionTimestamp(t *testing.T) *minterv1.CredentialsRequest {
 	cr := testCredentialsRequest(t)
 	now := metav1.Now()
@@ -1903,3 +2221,51 @@ func testOperatorConfig(mode operatorv1.CloudCredentialsMode) *operatorv1.CloudC
 
 	return conf
 }
+
+func testOperatorConfigWithResyncNow(mode operatorv1.CloudCredentialsMode, resyncNowValue string) *operatorv1.CloudCredential {
+	conf := testOperatorConfig(mode)
+	conf.Annotations = map[string]string{
+		constants.AnnotationResyncNow: resyncNowValue,
+	}
+	return conf
+}
+
+func TestRequeueTimeForCR(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority minterv1.SyncPriority
+		expected time.Duration
+	}{
+		{
+			name:     "unset defaults to normal",
+			priority: "",
+			expected: defaultRequeueTime,
+		},
+		{
+			name:     "normal priority",
+			priority: minterv1.SyncPriorityNormal,
+			expected: defaultRequeueTime,
+		},
+		{
+			name:     "high priority",
+			priority: minterv1.SyncPriorityHigh,
+			expected: highPriorityRequeueTime,
+		},
+		{
+			name:     "low priority",
+			priority: minterv1.SyncPriorityLow,
+			expected: lowPriorityRequeueTime,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cr := &minterv1.CredentialsRequest{
+				Spec: minterv1.CredentialsRequestSpec{
+					SyncPriority: test.priority,
+				},
+			}
+			assert.Equal(t, test.expected, requeueTimeForCR(cr))
+		})
+	}
+}
@@ -22,9 +22,10 @@ import (
 )
 
 const (
-	reasonCredentialsFailing = "CredentialsFailing"
-	reasonReconciling        = "Reconciling"
-	reasonStaleCredentials   = "StaleCredentials"
+	reasonCredentialsFailing      = "CredentialsFailing"
+	reasonReconciling             = "Reconciling"
+	reasonStaleCredentials        = "StaleCredentials"
+	reasonCredentialsProvisioning = "CredentialsProvisioning"
 )
 
 var _ status.Handler = &ReconcileCredentialsRequest{}
@@ -109,6 +110,12 @@ func computeStatusConditions(
 	}
 
 	failingCredRequests := 0
+	// unresolvedCredRequests counts CredentialsRequests that have reached neither Provisioned nor
+	// one of FailureConditionTypes yet, i.e. the controller is still actively working on them. This
+	// drives the Available condition below: other operators and the install flow should be able to
+	// rely on CCO reporting Available only once every CredentialsRequest for this platform has
+	// reached a terminal outcome, not merely once the operator process itself is up.
+	unresolvedCredRequests := 0
 
 	validCredRequests := []minterv1.CredentialsRequest{}
 	// Filter out credRequests that are for different clouds
@@ -138,9 +145,22 @@ func computeStatusConditions(
 
 		if foundFailure {
 			failingCredRequests = failingCredRequests + 1
+		} else if !cr.Status.Provisioned {
+			unresolvedCredRequests = unresolvedCredRequests + 1
 		}
 	}
 
+	if unresolvedCredRequests > 0 {
+		var availableCondition configv1.ClusterOperatorStatusCondition
+		availableCondition.Type = configv1.OperatorAvailable
+		availableCondition.Status = configv1.ConditionFalse
+		availableCondition.Reason = reasonCredentialsProvisioning
+		availableCondition.Message = fmt.Sprintf(
+			"%d of %d credentials requests are still being provisioned.",
+			unresolvedCredRequests, len(validCredRequests))
+		conditions = append(conditions, availableCondition)
+	}
+
 	if failingCredRequests > 0 {
 		var degradedCondition configv1.ClusterOperatorStatusCondition
 		degradedCondition.Type = configv1.OperatorDegraded
@@ -96,6 +96,28 @@ func TestClusterOperatorStatus(t *testing.T) {
 				testCondition(configv1.OperatorProgressing, configv1.ConditionTrue, reasonReconciling),
 			},
 		},
+		{
+			name: "not available while provisioning in progress",
+			credRequests: []minterv1.CredentialsRequest{
+				testCredentialsRequestWithStatus("cred1", true, []minterv1.CredentialsRequestCondition{}, nil),
+				testCredentialsRequestWithStatus("cred2", false, []minterv1.CredentialsRequestCondition{}, nil),
+			},
+			cloudPlatform: configv1.AWSPlatformType,
+			expectedConditions: []configv1.ClusterOperatorStatusCondition{
+				testCondition(configv1.OperatorAvailable, configv1.ConditionFalse, reasonCredentialsProvisioning),
+			},
+		},
+		{
+			name: "available once unprovisioned requests have a bounded failure",
+			credRequests: []minterv1.CredentialsRequest{
+				testCredentialsRequestWithStatus("cred1", true, []minterv1.CredentialsRequestCondition{}, nil),
+				testCredentialsRequestWithStatus("cred2", false, []minterv1.CredentialsRequestCondition{
+					testCRCondition(minterv1.CredentialsProvisionFailure, corev1.ConditionTrue),
+				}, nil),
+			},
+			cloudPlatform:      configv1.AWSPlatformType,
+			expectedConditions: []configv1.ClusterOperatorStatusCondition{},
+		},
 		{
 			name: "progressing with errors",
 			credRequests: []minterv1.CredentialsRequest{
@@ -28,6 +28,11 @@ import (
 
 const (
 	controllerName = "metrics"
+
+	// providerSpecKindDisabledReason mirrors the Ignored condition reason the credentialsrequest
+	// controller sets when a CredentialsRequest's providerSpec kind is not in the operator's
+	// enabledProviderSpecKinds allowlist.
+	providerSpecKindDisabledReason = "ProviderSpecKindDisabled"
 )
 
 var (
@@ -59,14 +64,43 @@ var (
 		},
 		[]string{"controller"},
 	)
+
+	// MetricSecretWriteFailuresTotal tracks how many times writing a CredentialsRequest's target
+	// Secret to the cluster has failed (e.g. quota, admission webhook rejection), as distinct from
+	// cloud-side provisioning failures, to speed triage of Kubernetes-side interference.
+	MetricSecretWriteFailuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "cco_credentials_requests_secret_write_failures_total",
+		Help: "Total number of failures writing a CredentialsRequest's target Secret to the cluster.",
+	})
+
+	// MetricCloudQuotaExceededTotal tracks how many times provisioning has failed because the
+	// cloud account exhausted an IAM-related quota (users/roles/policies/keys), so platform teams
+	// can alert on quota pressure before it cascades into broader provisioning failures.
+	MetricCloudQuotaExceededTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "cco_credentials_requests_cloud_quota_exceeded_total",
+		Help: "Total number of times provisioning a CredentialsRequest has failed due to a cloud account IAM quota being exceeded.",
+	})
+
+	// metricCredentialsRequestProviderKindDisabled tracks CredentialsRequests currently ignored
+	// because their providerSpec kind is not in the operator's enabledProviderSpecKinds allowlist,
+	// by cloud_type. Tracked separately from metricCredentialsRequestConditions because the
+	// Ignored condition is also set for the much noisier, expected case of a CredentialsRequest
+	// targeting a different cloud than the cluster's platform, which admins don't need to alert on.
+	metricCredentialsRequestProviderKindDisabled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cco_credentials_requests_provider_kind_disabled",
+		Help: "CredentialsRequests currently ignored because their providerSpec kind is disabled via the operator's enabledProviderSpecKinds allowlist.",
+	}, []string{"cloud_type"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(metricCredentialsRequestTotal)
 	metrics.Registry.MustRegister(metricCredentialsRequestConditions)
+	metrics.Registry.MustRegister(metricCredentialsRequestProviderKindDisabled)
 	metrics.Registry.MustRegister(metricCredentialsMode)
 
 	metrics.Registry.MustRegister(MetricControllerReconcileTime)
+	metrics.Registry.MustRegister(MetricSecretWriteFailuresTotal)
+	metrics.Registry.MustRegister(MetricCloudQuotaExceededTotal)
 }
 
 // Add creates a new metrics Calculator and adds it to the Manager.
@@ -210,9 +244,10 @@ type credRequestAccumulator struct {
 	kubeClient client.Client
 	logger     log.FieldLogger
 
-	crTotals     map[string]int
-	crConditions map[credreqv1.CredentialsRequestConditionType]int
-	crMode       map[constants.CredentialsMode]int
+	crTotals               map[string]int
+	crConditions           map[credreqv1.CredentialsRequestConditionType]int
+	crMode                 map[constants.CredentialsMode]int
+	crProviderKindDisabled map[string]int
 
 	podIdentityCredentials int
 }
@@ -223,6 +258,7 @@ func newAccumulator(client client.Client, logger log.FieldLogger) *credRequestAc
 		logger:                 logger,
 		crTotals:               map[string]int{},
 		crConditions:           map[credreqv1.CredentialsRequestConditionType]int{},
+		crProviderKindDisabled: map[string]int{},
 		podIdentityCredentials: 0,
 	}
 
@@ -233,6 +269,12 @@ func newAccumulator(client client.Client, logger log.FieldLogger) *credRequestAc
 	}
 	acc.crConditions[credreqv1.StaleCredentials] = 0
 
+	// make entries with '0' for every known cloud type so we send updated metrics once a
+	// providerSpec kind is removed from, or never added to, enabledProviderSpecKinds
+	for _, cloudKey := range []string{"aws", "azure", "gcp", "openstack", "ovirt", "vsphere", "kubevirt", "unknown"} {
+		acc.crProviderKindDisabled[cloudKey] = 0
+	}
+
 	return acc
 }
 
@@ -253,6 +295,12 @@ func (a *credRequestAccumulator) processCR(cr *credreqv1.CredentialsRequest, cco
 		a.podIdentityCredentials++
 	}
 
+	for _, cond := range cr.Status.Conditions {
+		if cond.Type == credreqv1.Ignored && cond.Status == corev1.ConditionTrue && cond.Reason == providerSpecKindDisabledReason {
+			a.crProviderKindDisabled[cloudKey]++
+		}
+	}
+
 	// Skip reporting conditions if CCO is disabled, as we shouldn't be alerting in that case, except for stale credentials.
 	// condition. The stale credentials are removed by cleanup controller. But when CCO is disabled the only way to inform
 	// users to remove these credentials is through alerts.
@@ -355,6 +403,10 @@ func (a *credRequestAccumulator) setMetrics() {
 	for k, v := range a.crConditions {
 		metricCredentialsRequestConditions.WithLabelValues(string(k)).Set(float64(v))
 	}
+
+	for k, v := range a.crProviderKindDisabled {
+		metricCredentialsRequestProviderKindDisabled.WithLabelValues(k).Set(float64(v))
+	}
 }
 
 func credRequestIsPodIdentity(cr *credreqv1.CredentialsRequest, cloudType string, kubeClient client.Client) (bool, error) {
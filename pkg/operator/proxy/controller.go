@@ -0,0 +1,187 @@
+/*
+Copyright 2026 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/cloud-credential-operator/pkg/operator/status"
+)
+
+const (
+	controllerName = "proxy"
+
+	clusterProxyName = "cluster"
+
+	// These are the env vars Go's net/http (and so the AWS/GCP/Azure SDKs the actuators use, all of
+	// which build http.Client/http.Transport values from scratch rather than inheriting an injected
+	// one) consult via http.ProxyFromEnvironment when deciding whether to route a request through a
+	// proxy. Keeping them in sync with the cluster-wide Proxy config is what lets actuator HTTP calls
+	// pick up the proxy without each actuator wiring one in by hand.
+	envHTTPProxy  = "HTTP_PROXY"
+	envHTTPSProxy = "HTTPS_PROXY"
+	envNoProxy    = "NO_PROXY"
+)
+
+// Add creates a new proxy controller and adds it to the Manager. The controller keeps the
+// process's HTTP_PROXY/HTTPS_PROXY/NO_PROXY env vars in sync with the cluster's Proxy "cluster"
+// object, so actuator HTTP clients (which all pick up proxy settings from the environment via
+// http.ProxyFromEnvironment) automatically route through the configured proxy without any
+// actuator-specific wiring.
+//
+// Caveat: http.ProxyFromEnvironment reads these env vars through a sync.Once and caches the
+// result for the life of the process, so this only works for clients that make their first
+// request after Reconcile has run at least once. Once any client has issued a request, it keeps
+// whatever proxy settings were in the environment at that time — later Proxy config changes
+// reconciled here are invisible to it. There's no portable way to invalidate that cache short of
+// restarting the process; a real fix needs each actuator's http.Transport to read the Proxy
+// config itself (e.g. via Transport.Proxy) instead of relying on the environment.
+func Add(mgr manager.Manager, kubeConfig string) error {
+	r := &ReconcileProxy{
+		Client: mgr.GetClient(),
+	}
+	status.AddHandler(controllerName, r)
+
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(
+		&source.Kind{Type: &configv1.Proxy{}},
+		&handler.EnqueueRequestForObject{})
+}
+
+var _ reconcile.Reconciler = &ReconcileProxy{}
+
+// ReconcileProxy reconciles the cluster's Proxy object into this process's proxy env vars.
+type ReconcileProxy struct {
+	client.Client
+
+	// cacheDriftMu guards cacheDrifted, which is read by GetConditions (called from the status
+	// controller's own goroutine) and written by Reconcile.
+	cacheDriftMu sync.Mutex
+	// cacheDrifted records whether the last Reconcile found that net/http's cached
+	// http.ProxyFromEnvironment result (see the caveat on Add) no longer matches the cluster
+	// Proxy config that was just applied to the environment.
+	cacheDrifted bool
+}
+
+// Reconcile reads the cluster's Proxy "cluster" object and applies its HTTPProxy/HTTPSProxy/NoProxy
+// status fields to this process's environment, so that every outbound HTTP call made by an
+// actuator (or any AWS/GCP/Azure/etc SDK client it constructs) automatically routes through the
+// configured proxy, as long as that client hasn't already issued a request under the old env vars
+// (see the caveat on Add).
+func (r *ReconcileProxy) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := log.WithFields(log.Fields{
+		"controller": controllerName,
+		"cr":         fmt.Sprintf("%s/%s", request.NamespacedName.Namespace, request.NamespacedName.Name),
+	})
+
+	clusterProxy := &configv1.Proxy{}
+	err := r.Get(ctx, types.NamespacedName{Name: clusterProxyName}, clusterProxy)
+	if errors.IsNotFound(err) {
+		logger.Debug("no cluster-wide Proxy config found, clearing any proxy env vars")
+		clusterProxy = &configv1.Proxy{}
+	} else if err != nil {
+		logger.WithError(err).Error("error getting cluster Proxy config")
+		return reconcile.Result{}, err
+	}
+
+	setOrUnset(envHTTPProxy, clusterProxy.Status.HTTPProxy)
+	setOrUnset(envHTTPSProxy, clusterProxy.Status.HTTPSProxy)
+	setOrUnset(envNoProxy, clusterProxy.Status.NoProxy)
+
+	logger.Debug("synced process proxy env vars from cluster Proxy config")
+
+	r.detectCacheDrift(logger, clusterProxy)
+
+	return reconcile.Result{}, nil
+}
+
+func setOrUnset(envVar, value string) {
+	if value == "" {
+		os.Unsetenv(envVar)
+		return
+	}
+	os.Setenv(envVar, value)
+}
+
+// detectCacheDrift checks whether net/http has already cached an http.ProxyFromEnvironment result
+// (from some HTTP client's first request, possibly made before this Reconcile ever ran) that no
+// longer matches the proxy config we just wrote to the environment. Because that cache lives for
+// the life of the process, finding drift here means some actuator HTTP client is silently still
+// using stale proxy settings, with no way for this controller to fix it short of a process
+// restart; the best we can do is surface it via GetConditions.
+func (r *ReconcileProxy) detectCacheDrift(logger log.FieldLogger, desired *configv1.Proxy) {
+	drifted := cachedProxyDiffers(logger, "http://example.com", desired.Status.HTTPProxy) ||
+		cachedProxyDiffers(logger, "https://example.com", desired.Status.HTTPSProxy)
+
+	r.cacheDriftMu.Lock()
+	r.cacheDrifted = drifted
+	r.cacheDriftMu.Unlock()
+}
+
+// cachedProxyDiffers compares what net/http's process-cached http.ProxyFromEnvironment would
+// currently resolve for a request to sampleURL against the desired proxy, logging and returning
+// true if they disagree.
+func cachedProxyDiffers(logger log.FieldLogger, sampleURL, desiredProxy string) bool {
+	req, err := http.NewRequest(http.MethodGet, sampleURL, nil)
+	if err != nil {
+		logger.WithError(err).Warning("error building sample request to check cached proxy settings")
+		return false
+	}
+
+	cachedProxyURL, err := http.ProxyFromEnvironment(req)
+	if err != nil {
+		logger.WithError(err).Warning("error reading cached proxy settings")
+		return false
+	}
+
+	cachedProxy := ""
+	if cachedProxyURL != nil {
+		cachedProxy = cachedProxyURL.String()
+	}
+
+	if cachedProxy == desiredProxy {
+		return false
+	}
+
+	logger.WithFields(log.Fields{
+		"cachedProxy":  cachedProxy,
+		"desiredProxy": desiredProxy,
+	}).Warning("process-cached proxy settings have drifted from the cluster Proxy config; HTTP clients that already made a request are still using the stale value until the operator pod restarts")
+	return true
+}
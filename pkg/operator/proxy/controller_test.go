@@ -0,0 +1,127 @@
+package proxy
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	schemeutils "github.com/openshift/cloud-credential-operator/pkg/util"
+)
+
+func TestReconcileProxy(t *testing.T) {
+	schemeutils.SetupScheme(scheme.Scheme)
+
+	tests := []struct {
+		name               string
+		existing           []runtime.Object
+		expectedHTTPProxy  string
+		expectedHTTPSProxy string
+		expectedNoProxy    string
+	}{
+		{
+			name: "proxy configured",
+			existing: []runtime.Object{
+				testClusterProxy("http://proxy.example.com:8080", "https://proxy.example.com:8080", "localhost,.svc"),
+			},
+			expectedHTTPProxy:  "http://proxy.example.com:8080",
+			expectedHTTPSProxy: "https://proxy.example.com:8080",
+			expectedNoProxy:    "localhost,.svc",
+		},
+		{
+			name:     "no cluster proxy object",
+			existing: []runtime.Object{},
+		},
+		{
+			name: "empty proxy status",
+			existing: []runtime.Object{
+				testClusterProxy("", "", ""),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer os.Unsetenv(envHTTPProxy)
+			defer os.Unsetenv(envHTTPSProxy)
+			defer os.Unsetenv(envNoProxy)
+
+			fakeClient := fake.NewClientBuilder().WithRuntimeObjects(test.existing...).Build()
+			r := &ReconcileProxy{
+				Client: fakeClient,
+			}
+
+			_, err := r.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: clusterProxyName},
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedHTTPProxy, os.Getenv(envHTTPProxy))
+			assert.Equal(t, test.expectedHTTPSProxy, os.Getenv(envHTTPSProxy))
+			assert.Equal(t, test.expectedNoProxy, os.Getenv(envNoProxy))
+		})
+	}
+}
+
+// TestReconcileProxyCacheDriftDetection exercises the real net/http behavior the proxy
+// controller's doc comment warns about: once http.ProxyFromEnvironment has been called for a
+// given env var combination, it caches the result for the life of the process, so a later
+// Reconcile that changes the env vars again cannot make already-cached HTTP clients see the new
+// proxy. The second Reconcile's proxy config is guaranteed to differ from whatever got cached by
+// the first call to http.ProxyFromEnvironment anywhere in this test binary (including in other
+// tests in this package), so this assertion holds regardless of test execution order.
+func TestReconcileProxyCacheDriftDetection(t *testing.T) {
+	schemeutils.SetupScheme(scheme.Scheme)
+	defer os.Unsetenv(envHTTPProxy)
+	defer os.Unsetenv(envHTTPSProxy)
+	defer os.Unsetenv(envNoProxy)
+
+	r := &ReconcileProxy{
+		Client: fake.NewClientBuilder().WithRuntimeObjects(
+			testClusterProxy("http://first-proxy.example.com:8080", "https://first-proxy.example.com:8080", "")).Build(),
+	}
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: clusterProxyName},
+	})
+	require.NoError(t, err)
+
+	r.Client = fake.NewClientBuilder().WithRuntimeObjects(
+		testClusterProxy("http://second-proxy.example.com:8080", "https://second-proxy.example.com:8080", "")).Build()
+	_, err = r.Reconcile(context.TODO(), reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: clusterProxyName},
+	})
+	require.NoError(t, err)
+
+	conditions, err := r.GetConditions(log.WithField("test", t.Name()))
+	require.NoError(t, err)
+	require.Len(t, conditions, 1)
+	assert.Equal(t, configv1.OperatorDegraded, conditions[0].Type)
+	assert.Equal(t, configv1.ConditionTrue, conditions[0].Status)
+	assert.Equal(t, reasonProxyCacheDrifted, conditions[0].Reason)
+}
+
+func testClusterProxy(httpProxy, httpsProxy, noProxy string) *configv1.Proxy {
+	return &configv1.Proxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterProxyName,
+		},
+		Status: configv1.ProxyStatus{
+			HTTPProxy:  httpProxy,
+			HTTPSProxy: httpsProxy,
+			NoProxy:    noProxy,
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package proxy
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cloud-credential-operator/pkg/operator/status"
+)
+
+const (
+	reasonProxyCacheDrifted = "ProxyEnvironmentCacheDrifted"
+)
+
+var _ status.Handler = &ReconcileProxy{}
+
+// GetConditions reports Degraded when net/http's process-lifetime http.ProxyFromEnvironment
+// cache (see the caveat on Add) has drifted from the cluster Proxy config, meaning some actuator
+// HTTP client is silently still using stale proxy settings until the operator pod restarts.
+func (r *ReconcileProxy) GetConditions(logger log.FieldLogger) ([]configv1.ClusterOperatorStatusCondition, error) {
+	r.cacheDriftMu.Lock()
+	drifted := r.cacheDrifted
+	r.cacheDriftMu.Unlock()
+
+	if !drifted {
+		return []configv1.ClusterOperatorStatusCondition{}, nil
+	}
+
+	return []configv1.ClusterOperatorStatusCondition{
+		{
+			Type:    configv1.OperatorDegraded,
+			Status:  configv1.ConditionTrue,
+			Reason:  reasonProxyCacheDrifted,
+			Message: "cluster Proxy config changed, but some HTTP clients made their first request before this change and are still using stale proxy settings; restarting the cloud-credential-operator pod will pick up the new config",
+		},
+	}, nil
+}
+
+func (r *ReconcileProxy) GetRelatedObjects(logger log.FieldLogger) ([]configv1.ObjectReference, error) {
+	return []configv1.ObjectReference{
+		{
+			Resource: "proxies",
+			Group:    configv1.GroupName,
+			Name:     clusterProxyName,
+		},
+	}, nil
+}
+
+func (r *ReconcileProxy) Name() string {
+	return controllerName
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvAnnotationTagPrefixes, when set to a comma-separated list of annotation key prefixes, has
+// actuators project CredentialsRequest annotations whose key starts with one of those prefixes
+// onto the cloud resource they mint as tags, the inverse of EnvPropagateTagLabels. This lets
+// platform teams drive cloud tagging policy from the CredentialsRequest's own metadata rather than
+// maintaining a separate mapping of resource to tags.
+const EnvAnnotationTagPrefixes = "CLOUD_CREDENTIAL_OPERATOR_ANNOTATION_TAG_PREFIXES"
+
+// maxTagKeyLength and maxTagValueLength are AWS's limits on a resource tag's key and value. Other
+// cloud tagging APIs have similar (often more generous) limits, but AWS's is the one actuator this
+// feature currently wires into.
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// AnnotationTagPrefixes returns the annotation key prefixes EnvAnnotationTagPrefixes configures to
+// be projected onto minted cloud resources as tags. Returns nil if the feature is not configured.
+func AnnotationTagPrefixes() []string {
+	raw := os.Getenv(EnvAnnotationTagPrefixes)
+	if raw == "" {
+		return nil
+	}
+	var prefixes []string
+	for _, prefix := range strings.Split(raw, ",") {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// BuildAnnotationTags filters annotations down to the keys matching a prefix configured via
+// EnvAnnotationTagPrefixes and returns them as a map of cloud resource tag key/value pairs, each
+// truncated to fit the target cloud's tag length limits. Returns nil (not an empty map) when there
+// is nothing to propagate, so callers can skip tagging entirely when the feature isn't in use.
+func BuildAnnotationTags(annotations map[string]string) map[string]string {
+	prefixes := AnnotationTagPrefixes()
+	if len(prefixes) == 0 || len(annotations) == 0 {
+		return nil
+	}
+
+	var tags map[string]string
+	for key, value := range annotations {
+		if !hasAnyPrefix(key, prefixes) {
+			continue
+		}
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tags[truncate(key, maxTagKeyLength)] = truncate(value, maxTagValueLength)
+	}
+	return tags
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen]
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildAnnotationTags(t *testing.T) {
+	os.Setenv(EnvAnnotationTagPrefixes, "tags.example.com/,cost.example.com/")
+	defer os.Unsetenv(EnvAnnotationTagPrefixes)
+
+	tags := BuildAnnotationTags(map[string]string{
+		"tags.example.com/cost-center": "team-awesome",
+		"cost.example.com/owner":       "platform",
+		"kubectl.kubernetes.io/other":  "should-not-be-propagated",
+	})
+
+	assert.Equal(t, "team-awesome", tags["tags.example.com/cost-center"])
+	assert.Equal(t, "platform", tags["cost.example.com/owner"])
+	assert.NotContains(t, tags, "kubectl.kubernetes.io/other")
+}
+
+func TestBuildAnnotationTagsNotConfigured(t *testing.T) {
+	os.Unsetenv(EnvAnnotationTagPrefixes)
+
+	tags := BuildAnnotationTags(map[string]string{"tags.example.com/cost-center": "team-awesome"})
+	assert.Nil(t, tags, "no tags should be built when EnvAnnotationTagPrefixes isn't set")
+}
+
+func TestBuildAnnotationTagsTruncated(t *testing.T) {
+	os.Setenv(EnvAnnotationTagPrefixes, "tags.example.com/")
+	defer os.Unsetenv(EnvAnnotationTagPrefixes)
+
+	longKey := "tags.example.com/" + strings.Repeat("k", 200)
+	longValue := strings.Repeat("v", 300)
+	tags := BuildAnnotationTags(map[string]string{longKey: longValue})
+
+	for key, value := range tags {
+		assert.LessOrEqual(t, len(key), maxTagKeyLength)
+		assert.LessOrEqual(t, len(value), maxTagValueLength)
+	}
+}
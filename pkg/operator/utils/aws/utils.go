@@ -2,6 +2,7 @@ package aws
 
 import (
 	"context"
+	"os"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -18,6 +19,14 @@ import (
 	"github.com/openshift/cloud-credential-operator/pkg/operator/utils"
 )
 
+const (
+	// webIdentityRoleARNEnvVar and webIdentityTokenFileEnvVar are the standard AWS SDK environment
+	// variables set on a pod that has been granted a projected service account token for use with
+	// AssumeRoleWithWebIdentity (as configured by IRSA, or manually for STS-mode clusters).
+	webIdentityRoleARNEnvVar   = "AWS_ROLE_ARN"
+	webIdentityTokenFileEnvVar = "AWS_WEB_IDENTITY_TOKEN_FILE"
+)
+
 // ClientBuilder handles creating an AWS client using the details found in the cluster's
 // Infrastructure object.
 func ClientBuilder(accessKeyID, secretAccessKey []byte, c client.Client) (ccaws.Client, error) {
@@ -37,6 +46,32 @@ func ClientBuilder(accessKeyID, secretAccessKey []byte, c client.Client) (ccaws.
 	return ccaws.NewClient(accessKeyID, secretAccessKey, params)
 }
 
+// HasWebIdentityCredentials reports whether the pod has been given a projected web identity
+// token to assume a role with, as an alternative to a root credentials Secret.
+func HasWebIdentityCredentials() bool {
+	return os.Getenv(webIdentityRoleARNEnvVar) != "" && os.Getenv(webIdentityTokenFileEnvVar) != ""
+}
+
+// WebIdentityClientBuilder handles creating an AWS client using the pod's projected web identity
+// token (as set up by webIdentityRoleARNEnvVar/webIdentityTokenFileEnvVar) rather than a
+// long-lived access key pair sourced from a Secret.
+func WebIdentityClientBuilder(c client.Client) (ccaws.Client, error) {
+	infra, err := utils.GetInfrastructure(c)
+	if err != nil {
+		return nil, err
+	}
+
+	params := setupClientParams(infra)
+
+	caBundle, err := loadCABundle(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load CA bundle")
+	}
+	params.CABundle = caBundle
+
+	return ccaws.NewClientFromWebIdentity(os.Getenv(webIdentityRoleARNEnvVar), os.Getenv(webIdentityTokenFileEnvVar), params)
+}
+
 func setupClientParams(infra *configv1.Infrastructure) *ccaws.ClientParams {
 	region := ""
 	endpoint := ""
@@ -0,0 +1,14 @@
+package utils
+
+import "os"
+
+// EnvImmutableSecrets, when set to "true", has actuators mark every Secret they create with
+// immutable: true, to prevent accidental edits. Since an immutable Secret's data can't be changed
+// via Update, actuators must delete and recreate it (rather than update it) whenever the stored
+// credential needs to change, e.g. during rotation.
+const EnvImmutableSecrets = "CLOUD_CREDENTIAL_OPERATOR_IMMUTABLE_SECRETS"
+
+// ImmutableSecretsEnabled reports whether EnvImmutableSecrets is set to "true".
+func ImmutableSecretsEnabled() bool {
+	return os.Getenv(EnvImmutableSecrets) == "true"
+}
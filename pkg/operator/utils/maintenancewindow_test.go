@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMaintenanceWindowCheck(t *testing.T) {
+	logger := log.WithField("controller", "testing")
+
+	// 2022-01-05 is a Wednesday.
+	midWindow := time.Date(2022, time.January, 5, 3, 0, 0, 0, time.UTC)
+	beforeWindow := time.Date(2022, time.January, 5, 1, 0, 0, 0, time.UTC)
+	afterWindow := time.Date(2022, time.January, 5, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		data      map[string]string
+		now       time.Time
+		expected  bool
+		expectErr bool
+	}{
+		{
+			name:     "no schedule configured allows mutation",
+			data:     map[string]string{},
+			now:      beforeWindow,
+			expected: true,
+		},
+		{
+			name: "within the window",
+			data: map[string]string{
+				maintenanceWindowScheduleKey: "0 2 * * *",
+				maintenanceWindowDurationKey: "4h",
+			},
+			now:      midWindow,
+			expected: true,
+		},
+		{
+			name: "before the window",
+			data: map[string]string{
+				maintenanceWindowScheduleKey: "0 2 * * *",
+				maintenanceWindowDurationKey: "4h",
+			},
+			now:      beforeWindow,
+			expected: false,
+		},
+		{
+			name: "after the window",
+			data: map[string]string{
+				maintenanceWindowScheduleKey: "0 2 * * *",
+				maintenanceWindowDurationKey: "4h",
+			},
+			now:      afterWindow,
+			expected: false,
+		},
+		{
+			name: "schedule without a duration is a configuration error",
+			data: map[string]string{
+				maintenanceWindowScheduleKey: "0 2 * * *",
+			},
+			now:       midWindow,
+			expectErr: true,
+		},
+		{
+			name: "invalid schedule is a configuration error",
+			data: map[string]string{
+				maintenanceWindowScheduleKey: "not-a-cron-expression",
+				maintenanceWindowDurationKey: "4h",
+			},
+			now:       midWindow,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{Data: test.data}
+			result, err := MaintenanceWindowCheck(cm, test.now, logger)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
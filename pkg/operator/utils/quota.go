@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strings"
+)
+
+// quotaErrorMarkers are substrings of cloud SDK error messages that indicate the provisioning
+// failure was caused by the cloud account running out of some IAM-related quota (users, roles,
+// policies, keys, etc), rather than a generic API error. Matched by substring rather than error
+// code/type since the actuators for different clouds wrap the underlying SDK errors as plain
+// strings (see e.g. formatAWSErr) by the time they reach the controller.
+var quotaErrorMarkers = []string{
+	// AWS IAM: iam.ErrCodeLimitExceededException ("LimitExceeded"), returned when an account hits
+	// its user/role/policy/key quota. Its message also includes the word "quota" (e.g. "Cannot
+	// exceed quota for UsersPerAccount: 5000"), which the generic marker below also catches.
+	"limitexceeded",
+	// GCP and Azure SDKs surface quota errors with "quota" somewhere in the message (e.g. GCP's
+	// "Quota exceeded for quota metric", Azure's "QuotaExceeded"/"quota has been exceeded").
+	"quota",
+}
+
+// IsQuotaExceededError returns true if err appears to be a cloud API error caused by the account
+// having exhausted an IAM-related quota (e.g. max IAM users/roles/policies/keys), based on
+// substring-matching known quota error markers across the actuators' cloud SDKs. A false negative
+// here only means a quota exhaustion surfaces as a generic CredentialsProvisionFailure instead of
+// the more specific CloudQuotaExceeded condition - it does not affect whether provisioning is
+// retried.
+func IsQuotaExceededError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range quotaErrorMarkers {
+		if strings.Contains(msg, strings.ToLower(marker)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsQuotaExceededError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "aws iam limit exceeded",
+			err:      errors.New("AWS Error: LimitExceeded - LimitExceeded: Cannot exceed quota for UsersPerAccount: 5000"),
+			expected: true,
+		},
+		{
+			name:     "gcp quota exceeded",
+			err:      errors.New("googleapi: Error 403: Quota exceeded for quota metric 'IAM API requests'"),
+			expected: true,
+		},
+		{
+			name:     "azure quota exceeded",
+			err:      errors.New("QuotaExceeded: the subscription has exceeded its quota for role assignments"),
+			expected: true,
+		},
+		{
+			name:     "unrelated error",
+			err:      errors.New("AWS Error: AccessDenied - User is not authorized to perform this action"),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsQuotaExceededError(test.err))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// EnvCloudAPIRateLimitQPS sets the sustained calls/sec cap applied across all actuator calls to
+	// cloud APIs, shared by every CredentialsRequest the operator reconciles. Unset or non-positive
+	// disables rate limiting (the default).
+	EnvCloudAPIRateLimitQPS = "CLOUD_CREDENTIAL_OPERATOR_RATE_LIMIT_QPS"
+	// EnvCloudAPIRateLimitBurst sets the token bucket's burst size. Defaults to 1 if
+	// EnvCloudAPIRateLimitQPS is set but this is not.
+	EnvCloudAPIRateLimitBurst = "CLOUD_CREDENTIAL_OPERATOR_RATE_LIMIT_BURST"
+)
+
+var (
+	cloudAPILimiterOnce sync.Once
+	cloudAPILimiter     *rate.Limiter
+)
+
+// WaitForCloudAPIRateLimit blocks until the global cloud API token bucket (configured via
+// EnvCloudAPIRateLimitQPS/EnvCloudAPIRateLimitBurst) allows another call through, so that one busy
+// cluster sharing a cloud account with other tooling can't exhaust the account's API quota. It is a
+// no-op when rate limiting is not configured. Call it immediately before any actuator call that
+// reaches out to a cloud provider.
+func WaitForCloudAPIRateLimit(ctx context.Context) error {
+	cloudAPILimiterOnce.Do(initCloudAPILimiter)
+	if cloudAPILimiter == nil {
+		return nil
+	}
+	return cloudAPILimiter.Wait(ctx)
+}
+
+func initCloudAPILimiter() {
+	cloudAPILimiter = newCloudAPILimiterFromEnv(os.Getenv(EnvCloudAPIRateLimitQPS), os.Getenv(EnvCloudAPIRateLimitBurst))
+}
+
+// newCloudAPILimiterFromEnv builds the rate limiter from the raw EnvCloudAPIRateLimitQPS/
+// EnvCloudAPIRateLimitBurst values, returning nil if qpsStr is empty or invalid.
+func newCloudAPILimiterFromEnv(qpsStr, burstStr string) *rate.Limiter {
+	if qpsStr == "" {
+		return nil
+	}
+	qps, err := strconv.ParseFloat(qpsStr, 64)
+	if err != nil || qps <= 0 {
+		log.Errorf("invalid %s value %q, cloud API rate limiting disabled", EnvCloudAPIRateLimitQPS, qpsStr)
+		return nil
+	}
+
+	burst := 1
+	if burstStr != "" {
+		parsedBurst, err := strconv.Atoi(burstStr)
+		if err != nil || parsedBurst <= 0 {
+			log.Errorf("invalid %s value %q, defaulting burst to 1", EnvCloudAPIRateLimitBurst, burstStr)
+		} else {
+			burst = parsedBurst
+		}
+	}
+
+	log.Infof("cloud API calls globally rate-limited to %v/sec, burst %d", qps, burst)
+	return rate.NewLimiter(rate.Limit(qps), burst)
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCloudAPILimiterFromEnv(t *testing.T) {
+	cases := []struct {
+		name          string
+		qpsStr        string
+		burstStr      string
+		expectLimiter bool
+		expectBurst   int
+	}{
+		{
+			name:          "unset disables rate limiting",
+			qpsStr:        "",
+			expectLimiter: false,
+		},
+		{
+			name:          "invalid qps disables rate limiting",
+			qpsStr:        "not-a-number",
+			expectLimiter: false,
+		},
+		{
+			name:          "zero qps disables rate limiting",
+			qpsStr:        "0",
+			expectLimiter: false,
+		},
+		{
+			name:          "valid qps with default burst",
+			qpsStr:        "5",
+			expectLimiter: true,
+			expectBurst:   1,
+		},
+		{
+			name:          "valid qps with explicit burst",
+			qpsStr:        "5",
+			burstStr:      "10",
+			expectLimiter: true,
+			expectBurst:   10,
+		},
+		{
+			name:          "invalid burst falls back to default",
+			qpsStr:        "5",
+			burstStr:      "not-a-number",
+			expectLimiter: true,
+			expectBurst:   1,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			limiter := newCloudAPILimiterFromEnv(test.qpsStr, test.burstStr)
+			if !test.expectLimiter {
+				assert.Nil(t, limiter)
+				return
+			}
+			assert.NotNil(t, limiter)
+			assert.Equal(t, test.expectBurst, limiter.Burst())
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package utils
+
+import "os"
+
+// EnvStampMetadata, when set to "true", has actuators record rotation-governance metadata
+// (creation time, and for temporary credentials, expiry) as annotations on Secrets they mint, so
+// external tooling can alert on stale credentials without needing to inspect the cloud account
+// directly.
+const EnvStampMetadata = "CLOUD_CREDENTIAL_OPERATOR_STAMP_METADATA"
+
+// StampMetadataEnabled reports whether EnvStampMetadata is set to "true".
+func StampMetadataEnabled() bool {
+	return os.Getenv(EnvStampMetadata) == "true"
+}
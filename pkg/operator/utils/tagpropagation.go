@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// EnvPropagateTagLabels, when set to a comma-separated list of cloud resource tag keys, has
+// actuators copy those tags (when present on the cloud resource they minted) onto the generated
+// Secret as labels, so cost/ownership tooling that already keys off cluster-side labels doesn't
+// need a separate pass over the cloud API to look up the same tags.
+const EnvPropagateTagLabels = "CLOUD_CREDENTIAL_OPERATOR_PROPAGATE_TAG_LABELS"
+
+// tagLabelPrefix namespaces propagated tag labels so they can't collide with labels CCO or other
+// controllers manage for their own purposes.
+const tagLabelPrefix = "cloudcredential.openshift.io/tag-"
+
+// labelSanitizeInvalidCharsRegexp matches runs of characters not permitted in a Kubernetes label
+// value (or, here, the portion of a label key after tagLabelPrefix).
+var labelSanitizeInvalidCharsRegexp = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// maxLabelPartLength is the Kubernetes-enforced limit on a label key's name segment and on a label
+// value.
+const maxLabelPartLength = 63
+
+// PropagateTagLabelKeys returns the cloud resource tag keys EnvPropagateTagLabels configures to be
+// copied onto generated Secrets as labels. Returns nil if the feature is not configured.
+func PropagateTagLabelKeys() []string {
+	raw := os.Getenv(EnvPropagateTagLabels)
+	if raw == "" {
+		return nil
+	}
+	var keys []string
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// SanitizeLabelValue makes s safe to use as a Kubernetes label value (or the name segment of a
+// label key): disallowed characters are collapsed to "-", the result is truncated to
+// maxLabelPartLength, and any leading/trailing non-alphanumeric characters left by truncation or
+// substitution are trimmed so the value still satisfies the "must start/end alphanumeric" rule.
+func SanitizeLabelValue(s string) string {
+	sanitized := labelSanitizeInvalidCharsRegexp.ReplaceAllString(s, "-")
+	if len(sanitized) > maxLabelPartLength {
+		sanitized = sanitized[:maxLabelPartLength]
+	}
+	return strings.Trim(sanitized, "-_.")
+}
+
+// TagLabelKey returns the Secret label key that a propagated cloud resource tag named tagKey
+// should be written under.
+func TagLabelKey(tagKey string) string {
+	return tagLabelPrefix + SanitizeLabelValue(tagKey)
+}
+
+// BuildTagLabels filters cloudTags down to the keys configured via EnvPropagateTagLabels and
+// returns them as a map of sanitized Secret label key/value pairs ready to merge into a Secret's
+// ObjectMeta.Labels. Returns nil (not an empty map) when there is nothing to propagate, so callers
+// can skip touching Labels entirely when the feature isn't in use.
+func BuildTagLabels(cloudTags map[string]string) map[string]string {
+	keys := PropagateTagLabelKeys()
+	if len(keys) == 0 || len(cloudTags) == 0 {
+		return nil
+	}
+
+	var labels map[string]string
+	for _, key := range keys {
+		value, ok := cloudTags[key]
+		if !ok {
+			continue
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[TagLabelKey(key)] = SanitizeLabelValue(value)
+	}
+	return labels
+}
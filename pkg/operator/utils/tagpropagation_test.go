@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeLabelValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "already valid",
+			input:    "production",
+			expected: "production",
+		},
+		{
+			name:     "spaces and punctuation replaced",
+			input:    "Team Awesome!",
+			expected: "Team-Awesome",
+		},
+		{
+			name:     "leading and trailing separators trimmed",
+			input:    "-cost.center-",
+			expected: "cost.center",
+		},
+		{
+			name:     "truncated to 63 characters",
+			input:    "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			expected: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SanitizeLabelValue(tc.input))
+		})
+	}
+}
+
+func TestBuildTagLabels(t *testing.T) {
+	os.Setenv(EnvPropagateTagLabels, "cost-center,environment")
+	defer os.Unsetenv(EnvPropagateTagLabels)
+
+	labels := BuildTagLabels(map[string]string{
+		"cost-center": "Team Awesome!",
+		"owner":       "should-not-be-propagated",
+	})
+
+	assert.Equal(t, "Team-Awesome", labels[TagLabelKey("cost-center")])
+	assert.NotContains(t, labels, TagLabelKey("owner"))
+	assert.NotContains(t, labels, TagLabelKey("environment"), "tag absent from the resource should not appear in the result")
+}
+
+func TestBuildTagLabelsNotConfigured(t *testing.T) {
+	os.Unsetenv(EnvPropagateTagLabels)
+
+	labels := BuildTagLabels(map[string]string{"cost-center": "teamA"})
+	assert.Nil(t, labels, "no labels should be built when EnvPropagateTagLabels isn't set")
+}
@@ -27,13 +27,21 @@ import (
 )
 
 const (
-	awsCredsSecretIDKey          = "aws_access_key_id"
-	awsCredsSecretAccessKey      = "aws_secret_access_key"
-	operatorConfigMapDisabledKey = "disabled"
+	awsCredsSecretIDKey                         = "aws_access_key_id"
+	awsCredsSecretAccessKey                     = "aws_secret_access_key"
+	operatorConfigMapDisabledKey                = "disabled"
+	operatorConfigMapValidateMintedKeysKey      = "validateMintedKeys"
+	operatorConfigMapCrossNamespaceAllowlistKey = "crossNamespaceSecretAllowlist"
+	operatorConfigMapEnabledProviderKindsKey    = "enabledProviderSpecKinds"
 
 	// OperatorDisabledDefault holds the default behavior of whether CCO is disabled
 	// in the absence of any setting in the ConfigMap
 	OperatorDisabledDefault = false
+
+	// ValidateMintedKeysDefault holds the default behavior of whether a newly minted
+	// cloud credential is verified against the cloud API before being written to its
+	// target Secret, in the absence of any setting in the ConfigMap.
+	ValidateMintedKeysDefault = false
 )
 
 func LoadCredsFromSecret(kubeClient client.Client, namespace, secretName string) ([]byte, []byte, error) {
@@ -120,7 +128,8 @@ func GetCredentialsRequestCloudType(providerSpec *runtime.RawExtension) (string,
 // than their respective MaxLen argument. it will then add a unique ending to the resulting name
 // by appending '-<5 random chars>' to the resulting string.
 // Example: passing "thisIsInfraName", 8, "thisIsCrName", 8 will return:
-//		'thisIsIn-thisIsCr-<5 random chars>'
+//
+//	'thisIsIn-thisIsCr-<5 random chars>'
 func GenerateUniqueNameWithFieldLimits(infraName string, infraNameMaxLen int, crName string, crNameMaxlen int) (string, error) {
 	genName, err := GenerateNameWithFieldLimits(infraName, infraNameMaxLen, crName, crNameMaxlen)
 	if err != nil {
@@ -133,7 +142,8 @@ func GenerateUniqueNameWithFieldLimits(infraName string, infraNameMaxLen int, cr
 // GenerateNameWithFieldLimits lets you pass in two strings which will be clipped to their respective
 // maximum lengths.
 // Example: passing "thisIsInfraName", 8, "thisIsCrName", 8 will return:
-//      'thisIsIn-thisIsCr'
+//
+//	'thisIsIn-thisIsCr'
 func GenerateNameWithFieldLimits(infraName string, infraNameMaxLen int, crName string, crNameLen int) (string, error) {
 	if crName == "" {
 		return "", fmt.Errorf("empty credential request name")
@@ -206,6 +216,22 @@ func GetOperatorConfiguration(kubeClient client.Client, logger log.FieldLogger)
 	return
 }
 
+// GetResyncNowValue returns the current value of constants.AnnotationResyncNow on the
+// cloudcredential.operator.openshift.io CR, or "" if the CR/annotation does not exist. Callers
+// compare this against a per-CredentialsRequest record of the last value they honored to detect
+// that an admin has requested an immediate full resync.
+func GetResyncNowValue(kubeClient client.Client, logger log.FieldLogger) (string, error) {
+	conf, err := getOperatorConfiguration(kubeClient, logger)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return conf.GetAnnotations()[constants.AnnotationResyncNow], nil
+}
+
 func GetLogLevel(kubeClient client.Client, logger log.FieldLogger) (operatorv1.LogLevel, error) {
 	conf, err := getOperatorConfiguration(kubeClient, logger)
 	if err != nil {
@@ -292,6 +318,150 @@ func CCODisabledCheck(cm *corev1.ConfigMap, logger log.FieldLogger) (bool, error
 	return strconv.ParseBool(disabled)
 }
 
+// ValidateMintedKeysCheck will take the operator configuration ConfigMap and return whether
+// actuators should verify a newly minted credential against the cloud API (with retry) before
+// writing it to its target Secret. Cloud credential creation APIs (e.g. AWS IAM CreateAccessKey)
+// can be eventually consistent, so a consumer may otherwise receive a not-yet-propagated
+// credential.
+func ValidateMintedKeysCheck(cm *corev1.ConfigMap, logger log.FieldLogger) (bool, error) {
+	validate, ok := cm.Data[operatorConfigMapValidateMintedKeysKey]
+	if !ok {
+		logger.Debugf("%s ConfigMap has no %s key, assuming default behavior", constants.CloudCredOperatorConfigMap, operatorConfigMapValidateMintedKeysKey)
+		return ValidateMintedKeysDefault, nil
+	}
+	return strconv.ParseBool(validate)
+}
+
+// ValidateMintedKeysEnabled reads the operator config ConfigMap to decide whether newly minted
+// credentials should be verified against the cloud API before being written to their target
+// Secret, tolerating the ConfigMap not existing the same way isOperatorDisabledViaConfigmap does.
+func ValidateMintedKeysEnabled(kubeClient client.Client, logger log.FieldLogger) (bool, error) {
+	cm, err := GetLegacyConfigMap(kubeClient)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ValidateMintedKeysDefault, nil
+		}
+		return ValidateMintedKeysDefault, err
+	}
+
+	return ValidateMintedKeysCheck(cm, logger)
+}
+
+// CrossNamespaceSecretAllowlistCheck takes the operator configuration ConfigMap and returns the
+// list of namespaces the crossNamespaceSecretAllowlist key restricts cross-namespace Secret
+// targets to, and whether that restriction is enabled at all. Every CredentialsRequest in this
+// operator already lives in minterv1.CloudCredOperatorNamespace while its secretRef routinely
+// targets a different, component-owned namespace (that's the baseline design, not an edge case),
+// so the absence of this key must mean "no restriction" rather than "deny everything" to avoid
+// breaking every existing CredentialsRequest.
+func CrossNamespaceSecretAllowlistCheck(cm *corev1.ConfigMap, logger log.FieldLogger) (namespaces []string, restrictionEnabled bool, err error) {
+	allowlist, ok := cm.Data[operatorConfigMapCrossNamespaceAllowlistKey]
+	if !ok || strings.TrimSpace(allowlist) == "" {
+		logger.Debugf("%s ConfigMap has no %s key, cross-namespace secret targets are unrestricted", constants.CloudCredOperatorConfigMap, operatorConfigMapCrossNamespaceAllowlistKey)
+		return nil, false, nil
+	}
+
+	for _, ns := range strings.Split(allowlist, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces, true, nil
+}
+
+// CrossNamespaceSecretTargetAllowed reports whether a CredentialsRequest in crNamespace is
+// allowed to target targetNamespace for its Secret. Same-namespace targets are always allowed.
+// Cross-namespace targets are unrestricted (matching this operator's existing behavior, where
+// every CredentialsRequest already lives in minterv1.CloudCredOperatorNamespace regardless of
+// which namespace its secretRef targets) unless an administrator opts into restricting them by
+// setting crossNamespaceSecretAllowlist in the operator config ConfigMap, in which case only the
+// listed target namespaces are permitted. Tolerates the ConfigMap not existing the same way
+// isOperatorDisabledViaConfigmap does (unrestricted in that case).
+func CrossNamespaceSecretTargetAllowed(kubeClient client.Client, logger log.FieldLogger, crNamespace, targetNamespace string) (bool, error) {
+	if crNamespace == targetNamespace {
+		return true, nil
+	}
+
+	cm, err := GetLegacyConfigMap(kubeClient)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	allowlist, restrictionEnabled, err := CrossNamespaceSecretAllowlistCheck(cm, logger)
+	if err != nil {
+		return false, err
+	}
+	if !restrictionEnabled {
+		return true, nil
+	}
+
+	for _, ns := range allowlist {
+		if ns == targetNamespace {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EnabledProviderSpecKindsCheck takes the operator configuration ConfigMap and returns the list of
+// providerSpec Kind values (e.g. "AWSProviderSpec") the enabledProviderSpecKinds key restricts CCO
+// to acting on, and whether that restriction is enabled at all. Absence of the key means "no
+// restriction", consistent with every other opt-in allowlist this operator exposes (see
+// [[CrossNamespaceSecretAllowlistCheck]]), so a cluster that never sets this key keeps processing
+// every providerSpec kind it always has.
+func EnabledProviderSpecKindsCheck(cm *corev1.ConfigMap, logger log.FieldLogger) (kinds []string, restrictionEnabled bool, err error) {
+	allowlist, ok := cm.Data[operatorConfigMapEnabledProviderKindsKey]
+	if !ok || strings.TrimSpace(allowlist) == "" {
+		logger.Debugf("%s ConfigMap has no %s key, all providerSpec kinds are enabled", constants.CloudCredOperatorConfigMap, operatorConfigMapEnabledProviderKindsKey)
+		return nil, false, nil
+	}
+
+	for _, kind := range strings.Split(allowlist, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds, true, nil
+}
+
+// ProviderSpecKindEnabled reports whether CCO should act on a CredentialsRequest whose providerSpec
+// is of the given Kind (e.g. "AWSProviderSpec"). Every kind is enabled by default, matching this
+// operator's existing behavior of acting on any CredentialsRequest that matches the cluster's
+// platform. An administrator can opt into hardening a locked-down cluster by setting
+// enabledProviderSpecKinds in the operator config ConfigMap to a comma-separated allowlist of
+// kinds, in which case every other kind is rejected even if it matches the cluster's platform.
+// Tolerates the ConfigMap not existing the same way isOperatorDisabledViaConfigmap does (enabled in
+// that case).
+func ProviderSpecKindEnabled(kubeClient client.Client, logger log.FieldLogger, kind string) (bool, error) {
+	cm, err := GetLegacyConfigMap(kubeClient)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	allowlist, restrictionEnabled, err := EnabledProviderSpecKindsCheck(cm, logger)
+	if err != nil {
+		return false, err
+	}
+	if !restrictionEnabled {
+		return true, nil
+	}
+
+	for _, enabledKind := range allowlist {
+		if enabledKind == kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ModeToAnnotation converts a CCO operator mode to a CCO secret annotation
 // or errors if the mode is not one that converts to a secret annotation.
 func ModeToAnnotation(operatorMode operatorv1.CloudCredentialsMode) (string, error) {
@@ -318,8 +488,10 @@ func IsValidMode(operatorMode operatorv1.CloudCredentialsMode) bool {
 }
 
 // UpgradeableCheck will set the Upgradeable condition based on the mode CCO is in:
-//   Mint/Passthrough: check that the root creds secret exists
-//   Manual: check that the CCO's config CR has been annotated properly to signal that the user has performed the pre-upgrade credentials tasks.
+//
+//	Mint/Passthrough: check that the root creds secret exists
+//	Manual: check that the CCO's config CR has been annotated properly to signal that the user has performed the pre-upgrade credentials tasks.
+//
 // Note: the upgradeable flag can only stop upgrades from 4.x to 4.y, not 4.x.y to 4.x.z.
 func UpgradeableCheck(kubeClient client.Client, mode operatorv1.CloudCredentialsMode, rootSecret types.NamespacedName) *configv1.ClusterOperatorStatusCondition {
 	upgradeableCondition := &configv1.ClusterOperatorStatusCondition{
@@ -15,3 +15,15 @@ func GetAzureCloudName(infraStatus *configv1.InfrastructureStatus) configv1.Azur
 	}
 	return configv1.AzurePublicCloud
 }
+
+// GetAzureARMEndpoint gets the Azure Resource Manager endpoint override to use given the
+// specified infrastructure status. This is only populated for non-sovereign clouds such as
+// Azure Stack, where the Azure SDK cannot derive the endpoint from the cloud name alone.
+func GetAzureARMEndpoint(infraStatus *configv1.InfrastructureStatus) string {
+	if s := infraStatus.PlatformStatus; s != nil {
+		if a := s.Azure; a != nil {
+			return a.ARMEndpoint
+		}
+	}
+	return ""
+}
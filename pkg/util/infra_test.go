@@ -65,3 +65,34 @@ func TestGetAzureCloudName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAzureARMEndpoint(t *testing.T) {
+	cases := []struct {
+		name                string
+		infraStatus         *configv1.InfrastructureStatus
+		expectedARMEndpoint string
+	}{
+		{
+			name:                "no platform status",
+			infraStatus:         &configv1.InfrastructureStatus{},
+			expectedARMEndpoint: "",
+		},
+		{
+			name: "azure stack endpoint set",
+			infraStatus: &configv1.InfrastructureStatus{
+				PlatformStatus: &configv1.PlatformStatus{
+					Azure: &configv1.AzurePlatformStatus{
+						CloudName:   configv1.AzureStackCloud,
+						ARMEndpoint: "https://management.local.azurestack.external",
+					},
+				},
+			},
+			expectedARMEndpoint: "https://management.local.azurestack.external",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedARMEndpoint, GetAzureARMEndpoint(tc.infraStatus))
+		})
+	}
+}